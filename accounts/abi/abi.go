@@ -30,6 +30,7 @@ type ABI struct {
 	Constructor Method
 	Methods     map[string]Method
 	Events      map[string]Event
+	Errors      map[string]Error
 }
 
 // JSON returns a parsed ABI interface and error if it failed.
@@ -108,6 +109,7 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 
 	abi.Methods = make(map[string]Method)
 	abi.Events = make(map[string]Event)
+	abi.Errors = make(map[string]Error)
 	for _, field := range fields {
 		switch field.Type {
 		case "constructor":
@@ -128,6 +130,11 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 				Anonymous: field.Anonymous,
 				Inputs:    field.Inputs,
 			}
+		case "error":
+			abi.Errors[field.Name] = Error{
+				Name:   field.Name,
+				Inputs: field.Inputs,
+			}
 		}
 	}
 
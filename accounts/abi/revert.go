@@ -0,0 +1,164 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/crypto"
+)
+
+var (
+	// revertSelector is the selector of the Error(string) revert reasons.
+	revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+	// panicSelector is the selector of the Panic(uint256) failed assertions.
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+	errInvalidRevert = errors.New("abi: invalid data for unpacking revert reason")
+)
+
+// panicReasons are the descriptions of the codes of the Panic(uint256) errors
+// raised by the Solidity compiler.
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesSlice",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// UnpackRevert resolves the reason of the data returned by a reverted call,
+// either an Error(string) revert reason or a Panic(uint256) failed assertion.
+func UnpackRevert(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", errInvalidRevert
+	}
+	switch {
+	case bytes.Equal(data[:4], revertSelector):
+		typ, _ := NewType("string")
+		unpacked, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		return unpacked[0].(string), nil
+
+	case bytes.Equal(data[:4], panicSelector):
+		typ, _ := NewType("uint256")
+		unpacked, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		code := unpacked[0].(*big.Int)
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return reason, nil
+			}
+		}
+		return fmt.Sprintf("unknown panic code: %#x", code), nil
+	}
+	return "", errInvalidRevert
+}
+
+// Error is a custom error of a contract, returned as the revert data of the
+// calls failing with it.
+type Error struct {
+	Name   string
+	Inputs Arguments
+}
+
+// Sig returns the error's string signature according to the ABI spec.
+func (e Error) Sig() string {
+	types := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		types[i] = input.Type.String()
+	}
+	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
+}
+
+func (e Error) String() string {
+	inputs := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		inputs[i] = fmt.Sprintf("%v %v", input.Name, input.Type)
+	}
+	return fmt.Sprintf("error %v(%v)", e.Name, strings.Join(inputs, ", "))
+}
+
+// Id returns the selector of the error, the first 4 bytes of its revert data.
+func (e Error) Id() []byte {
+	return crypto.Keccak256([]byte(e.Sig()))[:4]
+}
+
+// Unpack decodes the arguments of the error from the revert data of a call.
+func (e Error) Unpack(data []byte) ([]interface{}, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], e.Id()) {
+		return nil, fmt.Errorf("abi: revert data is not a %v error", e.Name)
+	}
+	return e.Inputs.UnpackValues(data[4:])
+}
+
+// ErrorById looks up a custom error by the 4-byte selector of revert data
+// returns nil if none found
+func (abi *ABI) ErrorById(sigdata []byte) (*Error, error) {
+	if len(sigdata) < 4 {
+		return nil, errInvalidRevert
+	}
+	for _, e := range abi.Errors {
+		if bytes.Equal(e.Id(), sigdata[:4]) {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("no error with id: %#x", sigdata[:4])
+}
+
+// UnpackError resolves the revert data of a call into a readable reason,
+// either a custom error of the contract formatted with its arguments, or one
+// of the builtin Error(string) and Panic(uint256) reasons.
+func (abi *ABI) UnpackError(data []byte) (string, error) {
+	if e, err := abi.ErrorById(data); err == nil {
+		args, err := e.Unpack(data)
+		if err != nil {
+			return "", err
+		}
+		values := make([]string, len(args))
+		for i, arg := range args {
+			switch e.Inputs[i].Type.T {
+			case AddressTy:
+				var addr common.Address
+				reflect.Copy(reflect.ValueOf(addr[:]), reflect.ValueOf(arg))
+				values[i] = addr.Hex()
+			case FixedBytesTy, BytesTy:
+				values[i] = fmt.Sprintf("%#x", arg)
+			default:
+				values[i] = fmt.Sprint(arg)
+			}
+		}
+		return fmt.Sprintf("%v(%v)", e.Name, strings.Join(values, ", ")), nil
+	}
+	return UnpackRevert(data)
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+)
+
+func TestUnpackRevert(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		input     string
+		expect    string
+		expectErr bool
+	}{
+		{"", "", true},
+		{"08c379a1", "", true},
+		{"08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000", "revert reason", false},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000000", "generic panic", false},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000011", "arithmetic underflow or overflow", false},
+		{"4e487b7100000000000000000000000000000000000000000000000000000000000000ff", "unknown panic code: 0xff", false},
+	}
+	for i, c := range cases {
+		reason, err := UnpackRevert(common.Hex2Bytes(c.input))
+		if c.expectErr {
+			if err == nil {
+				t.Errorf("case %d: expected error, have %q", i, reason)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		}
+		if reason != c.expect {
+			t.Errorf("case %d: reason mismatch: have %q, want %q", i, reason, c.expect)
+		}
+	}
+}
+
+func TestUnpackCustomError(t *testing.T) {
+	t.Parallel()
+
+	const definition = `[
+		{"type": "error", "name": "InsufficientFee", "inputs": [{"name": "required", "type": "uint256"}, {"name": "token", "type": "address"}]},
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}]}
+	]`
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := abi.Errors["InsufficientFee"]
+	if !ok {
+		t.Fatal("custom error not parsed")
+	}
+	if have, want := e.Sig(), "InsufficientFee(uint256,address)"; have != want {
+		t.Fatalf("signature mismatch: have %s, want %s", have, want)
+	}
+	args, _ := e.Inputs.Pack(common.Big3, common.HexToAddress("0x01"))
+	data := append(e.Id(), args...)
+
+	reason, err := abi.UnpackError(data)
+	if err != nil {
+		t.Fatalf("failed to unpack custom error: %v", err)
+	}
+	if want := "InsufficientFee(3, 0x0000000000000000000000000000000000000001)"; reason != want {
+		t.Errorf("reason mismatch: have %q, want %q", reason, want)
+	}
+	// Builtin reasons are resolved even if the contract defines custom errors
+	reason, err = abi.UnpackError(common.Hex2Bytes("4e487b710000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil || reason != "assert(false)" {
+		t.Errorf("builtin reason mismatch: have %q (%v), want %q", reason, err, "assert(false)")
+	}
+	if _, err := abi.UnpackError(common.Hex2Bytes("deadbeef")); err == nil {
+		t.Error("expected error for unknown revert data")
+	}
+}
@@ -29,17 +29,10 @@ import (
 	"github.com/tomochain/tomochain/log"
 	"github.com/tomochain/tomochain/params"
 
-	"context"
 	"math/big"
 
-	"github.com/tomochain/tomochain/accounts/abi/bind"
-	"github.com/tomochain/tomochain/accounts/abi/bind/backends"
-	blockSignerContract "github.com/tomochain/tomochain/contracts/blocksigner"
+	genesisContracts "github.com/tomochain/tomochain/contracts/genesis"
 	multiSignWalletContract "github.com/tomochain/tomochain/contracts/multisigwallet"
-	randomizeContract "github.com/tomochain/tomochain/contracts/randomize"
-	validatorContract "github.com/tomochain/tomochain/contracts/validator"
-	"github.com/tomochain/tomochain/crypto"
-	"github.com/tomochain/tomochain/rlp"
 )
 
 // makeGenesis creates a new genesis struct based on some user input.
@@ -151,14 +144,7 @@ func (w *wizard) makeGenesis() {
 				}
 			}
 		}
-		validatorCap := new(big.Int)
-		validatorCap.SetString("50000000000000000000000", 10)
-		var validatorCaps []*big.Int
-		genesis.ExtraData = make([]byte, 32+len(signers)*common.AddressLength+65)
-		for i, signer := range signers {
-			validatorCaps = append(validatorCaps, validatorCap)
-			copy(genesis.ExtraData[32+i*common.AddressLength:], signer[:])
-		}
+		genesis.ExtraData = genesisContracts.PosvExtraData(signers)
 
 		fmt.Println()
 		fmt.Println("How many blocks per epoch? (default = 900)")
@@ -174,37 +160,13 @@ func (w *wizard) makeGenesis() {
 		fmt.Println("What is foundation wallet address? (default = 0x0000000000000000000000000000000000000068)")
 		genesis.Config.Posv.FoudationWalletAddr = w.readDefaultAddress(common.HexToAddress(common.FoudationAddr))
 
-		// Validator Smart Contract Code
-		pKey, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
-		addr := crypto.PubkeyToAddress(pKey.PublicKey)
-		contractBackend := backends.NewSimulatedBackend(core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}})
-		transactOpts := bind.NewKeyedTransactor(pKey)
-
-		validatorAddress, _, err := validatorContract.DeployValidator(transactOpts, contractBackend, signers, validatorCaps, owner)
-		if err != nil {
-			fmt.Println("Can't deploy root registry")
-		}
-		contractBackend.Commit()
-
-		d := time.Now().Add(1000 * time.Millisecond)
-		ctx, cancel := context.WithDeadline(context.Background(), d)
-		defer cancel()
-		code, _ := contractBackend.CodeAt(ctx, validatorAddress, nil)
-		storage := make(map[common.Hash]common.Hash)
-		f := func(key, val common.Hash) bool {
-			decode := []byte{}
-			trim := bytes.TrimLeft(val.Bytes(), "\x00")
-			rlp.DecodeBytes(trim, &decode)
-			storage[key] = common.BytesToHash(decode)
-			log.Info("DecodeBytes", "value", val.String(), "decode", storage[key].String())
-			return true
-		}
-		contractBackend.ForEachStorageAt(ctx, validatorAddress, nil, f)
-		genesis.Alloc[common.HexToAddress(common.MasternodeVotingSMC)] = core.GenesisAccount{
-			Balance: validatorCap.Mul(validatorCap, big.NewInt(int64(len(validatorCaps)))),
-			Code:    code,
-			Storage: storage,
+		// Validator, Block Signers and Randomize Smart Contracts
+		validatorCap := new(big.Int)
+		validatorCap.SetString("50000000000000000000000", 10)
+		if err := genesisContracts.DeployPosvContracts(genesis, owner, signers, validatorCap); err != nil {
+			log.Crit("Can't deploy PoSV system contracts", "err", err)
 		}
+		contractBackend, transactOpts := genesisContracts.Deployer()
 
 		fmt.Println()
 		fmt.Println("Which accounts are allowed to confirm in Foudation MultiSignWallet?")
@@ -228,49 +190,14 @@ func (w *wizard) makeGenesis() {
 			fmt.Println("Can't deploy MultiSignWallet SMC")
 		}
 		contractBackend.Commit()
-		code, _ = contractBackend.CodeAt(ctx, multiSignWalletAddr, nil)
-		storage = make(map[common.Hash]common.Hash)
-		contractBackend.ForEachStorageAt(ctx, multiSignWalletAddr, nil, f)
 		fBalance := big.NewInt(0) // 16m
 		fBalance.Add(fBalance, big.NewInt(16*1000*1000))
 		fBalance.Mul(fBalance, big.NewInt(1000000000000000000))
-		genesis.Alloc[common.HexToAddress(common.FoudationAddr)] = core.GenesisAccount{
-			Balance: fBalance,
-			Code:    code,
-			Storage: storage,
-		}
-
-		// Block Signers Smart Contract
-		blockSignerAddress, _, err := blockSignerContract.DeployBlockSigner(transactOpts, contractBackend, big.NewInt(int64(epochNumber)))
+		foundation, err := genesisContracts.ContractAccount(contractBackend, multiSignWalletAddr, fBalance)
 		if err != nil {
-			fmt.Println("Can't deploy root registry")
-		}
-		contractBackend.Commit()
-
-		code, _ = contractBackend.CodeAt(ctx, blockSignerAddress, nil)
-		storage = make(map[common.Hash]common.Hash)
-		contractBackend.ForEachStorageAt(ctx, blockSignerAddress, nil, f)
-		genesis.Alloc[common.HexToAddress(common.BlockSigners)] = core.GenesisAccount{
-			Balance: big.NewInt(0),
-			Code:    code,
-			Storage: storage,
-		}
-
-		// Randomize Smart Contract Code
-		randomizeAddress, _, err := randomizeContract.DeployRandomize(transactOpts, contractBackend)
-		if err != nil {
-			fmt.Println("Can't deploy root registry")
-		}
-		contractBackend.Commit()
-
-		code, _ = contractBackend.CodeAt(ctx, randomizeAddress, nil)
-		storage = make(map[common.Hash]common.Hash)
-		contractBackend.ForEachStorageAt(ctx, randomizeAddress, nil, f)
-		genesis.Alloc[common.HexToAddress(common.RandomizeSMC)] = core.GenesisAccount{
-			Balance: big.NewInt(0),
-			Code:    code,
-			Storage: storage,
+			log.Crit("Can't read MultiSignWallet SMC", "err", err)
 		}
+		genesis.Alloc[common.HexToAddress(common.FoudationAddr)] = foundation
 
 		fmt.Println()
 		fmt.Println("Which accounts are allowed to confirm in Team MultiSignWallet?")
@@ -294,9 +221,6 @@ func (w *wizard) makeGenesis() {
 			fmt.Println("Can't deploy MultiSignWallet SMC")
 		}
 		contractBackend.Commit()
-		code, _ = contractBackend.CodeAt(ctx, multiSignWalletTeamAddr, nil)
-		storage = make(map[common.Hash]common.Hash)
-		contractBackend.ForEachStorageAt(ctx, multiSignWalletTeamAddr, nil, f)
 		// Team balance.
 		balance := big.NewInt(0) // 12m
 		balance.Add(balance, big.NewInt(12*1000*1000))
@@ -305,11 +229,11 @@ func (w *wizard) makeGenesis() {
 		subBalance.Add(subBalance, big.NewInt(int64(len(signers))*50*1000))
 		subBalance.Mul(subBalance, big.NewInt(1000000000000000000))
 		balance.Sub(balance, subBalance) // 12m - i * 50k
-		genesis.Alloc[common.HexToAddress(common.TeamAddr)] = core.GenesisAccount{
-			Balance: balance,
-			Code:    code,
-			Storage: storage,
+		team, err := genesisContracts.ContractAccount(contractBackend, multiSignWalletTeamAddr, balance)
+		if err != nil {
+			log.Crit("Can't read MultiSignWallet SMC", "err", err)
 		}
+		genesis.Alloc[common.HexToAddress(common.TeamAddr)] = team
 
 		fmt.Println()
 		fmt.Println("What is swap wallet address for fund 55m tomo?")
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/consensus/posv"
+	"github.com/tomochain/tomochain/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	checkpointCommand = cli.Command{
+		Name:      "checkpoint",
+		Usage:     "Manage the trusted PoSV checkpoints used by fast sync",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			checkpointDumpCmd,
+		},
+	}
+	checkpointDumpCmd = cli.Command{
+		Action:    utils.MigrateFlags(checkpointDump),
+		Name:      "dump",
+		Usage:     "Dump a trusted checkpoint from the local chain",
+		ArgsUsage: "[<epoch>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+		},
+		Description: `
+The dump command reads the checkpoint block of the given epoch and the signer
+snapshot taken at the gap block preceding it from the local database, and prints
+them both as JSON, usable with --checkpoint or as the Eth.Checkpoint config
+option, and as Go source to refresh the checkpoints embedded in the params
+package.
+
+Without an epoch, the checkpoint of the latest finalized epoch is dumped, or the
+one of the latest epoch if no block was finalized yet.`,
+	}
+)
+
+// checkpointDump prints the trusted checkpoint of an epoch of the local chain.
+func checkpointDump(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	chainDB, _, chainConfig, blockchain, err := setupChain(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open blockchain: %v", err)
+	}
+	defer chainDB.Close()
+	defer blockchain.Stop()
+
+	engine, ok := blockchain.Engine().(*posv.Posv)
+	if !ok || chainConfig.Posv == nil {
+		utils.Fatalf("Trusted checkpoints are only supported by PoSV chains")
+	}
+	config := chainConfig.Posv
+
+	head := blockchain.CurrentBlock().NumberU64()
+	if finalized := blockchain.CurrentFinalizedBlock(); finalized != nil {
+		head = finalized.NumberU64()
+	}
+	number := head - head%config.Epoch
+	if len(ctx.Args()) == 1 {
+		epoch, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid epoch number: %v", err)
+		}
+		number = epoch * config.Epoch
+	}
+	if number <= config.Gap || number > blockchain.CurrentBlock().NumberU64() {
+		utils.Fatalf("No checkpoint available at block %d", number)
+	}
+	header := blockchain.GetHeaderByNumber(number)
+	gapHeader := blockchain.GetHeaderByNumber(number - config.Gap)
+	if header == nil || gapHeader == nil {
+		utils.Fatalf("Missing headers of checkpoint %d", number)
+	}
+	snap, err := engine.GetSnapshot(blockchain, gapHeader)
+	if err != nil {
+		utils.Fatalf("Failed to retrieve snapshot at block %d: %v", gapHeader.Number, err)
+	}
+	checkpoint := &params.TrustedCheckpoint{
+		Number:       number,
+		Hash:         header.Hash(),
+		SnapshotHash: gapHeader.Hash(),
+		Signers:      snap.GetSigners(),
+	}
+	out, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode checkpoint: %v", err)
+	}
+	fmt.Println(string(out))
+	fmt.Println()
+	fmt.Println("&TrustedCheckpoint{")
+	fmt.Printf("\tNumber:       %d,\n", checkpoint.Number)
+	fmt.Printf("\tHash:         common.HexToHash(\"%s\"),\n", checkpoint.Hash.Hex())
+	fmt.Printf("\tSnapshotHash: common.HexToHash(\"%s\"),\n", checkpoint.SnapshotHash.Hex())
+	fmt.Println("\tSigners: []common.Address{")
+	for _, signer := range checkpoint.Signers {
+		fmt.Printf("\t\tcommon.HexToAddress(\"%s\"),\n", signer.Hex())
+	}
+	fmt.Println("\t},")
+	fmt.Println("}")
+	return nil
+}
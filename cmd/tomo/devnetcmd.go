@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/eth/devnet"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	devnetMasternodesFlag = cli.IntFlag{
+		Name:  "masternodes",
+		Usage: "Number of masternodes of the local network",
+		Value: devnet.DefaultConfig.Masternodes,
+	}
+	devnetPeriodFlag = cli.Uint64Flag{
+		Name:  "devnet.period",
+		Usage: "Number of seconds between blocks",
+		Value: devnet.DefaultConfig.Period,
+	}
+	devnetEpochFlag = cli.Uint64Flag{
+		Name:  "devnet.epoch",
+		Usage: "Number of blocks per epoch",
+		Value: devnet.DefaultConfig.Epoch,
+	}
+	devnetGapFlag = cli.Uint64Flag{
+		Name:  "devnet.gap",
+		Usage: "Number of blocks before the checkpoint the next masternodes are prepared",
+		Value: devnet.DefaultConfig.Gap,
+	}
+	devnetGenesisFlag = cli.StringFlag{
+		Name:  "devnet.genesis",
+		Usage: "File to write the generated genesis to",
+	}
+	devnetCommand = cli.Command{
+		Action:    utils.MigrateFlags(runDevnet),
+		Name:      "devnet",
+		Usage:     "Run a local multi-masternode PoSV network",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Flags: []cli.Flag{
+			devnetMasternodesFlag,
+			devnetPeriodFlag,
+			devnetEpochFlag,
+			devnetGapFlag,
+			devnetGenesisFlag,
+		},
+		Description: `
+The devnet command generates a genesis block with the validator, block signer and
+randomize contracts pre-deployed, then runs one in-process node per masternode,
+all of them connected over the p2p simulation adapters. The epoch and gap are
+shortened so that checkpoints happen within minutes.
+
+The network runs without any external connectivity until interrupted and its
+data is discarded on exit. The keys of the masternodes are printed on start.`,
+	}
+)
+
+// runDevnet starts a local PoSV network and blocks until interrupted.
+func runDevnet(ctx *cli.Context) error {
+	config := devnet.DefaultConfig
+	config.Masternodes = ctx.Int(devnetMasternodesFlag.Name)
+	config.Period = ctx.Uint64(devnetPeriodFlag.Name)
+	config.Epoch = ctx.Uint64(devnetEpochFlag.Name)
+	config.Gap = ctx.Uint64(devnetGapFlag.Name)
+
+	network, err := devnet.New(config)
+	if err != nil {
+		utils.Fatalf("Failed to create devnet: %v", err)
+	}
+	if path := ctx.String(devnetGenesisFlag.Name); path != "" {
+		out, err := json.MarshalIndent(network.Genesis(), "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to encode genesis: %v", err)
+		}
+		if err := ioutil.WriteFile(path, out, 0644); err != nil {
+			utils.Fatalf("Failed to write genesis: %v", err)
+		}
+		fmt.Printf("Genesis:     %s\n", path)
+	}
+	fmt.Printf("Masternodes: %d\n", config.Masternodes)
+	for _, key := range network.Keys() {
+		fmt.Printf("  %s %s\n", crypto.PubkeyToAddress(key.PublicKey).Hex(), hex.EncodeToString(crypto.FromECDSA(key)))
+	}
+	if err := network.Start(); err != nil {
+		utils.Fatalf("Failed to start devnet: %v", err)
+	}
+	defer network.Stop()
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	fmt.Println("Shutting down devnet")
+	return nil
+}
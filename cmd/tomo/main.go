@@ -29,7 +29,6 @@ import (
 	"github.com/tomochain/tomochain/cmd/utils"
 	"github.com/tomochain/tomochain/consensus/posv"
 	"github.com/tomochain/tomochain/console"
-	"github.com/tomochain/tomochain/core"
 	"github.com/tomochain/tomochain/eth"
 	"github.com/tomochain/tomochain/ethclient"
 	"github.com/tomochain/tomochain/internal/debug"
@@ -71,6 +70,7 @@ var (
 		utils.TomoXDBConnectionUrlFlag,
 		utils.TomoXDBReplicaSetNameFlag,
 		utils.TomoXDBNameFlag,
+		utils.TomoXLendingPriceFeedFlag,
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
@@ -85,6 +85,9 @@ var (
 		utils.LightModeFlag,
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
+		utils.CheckpointFlag,
+		utils.AddressIndexFlag,
+		utils.RevertReasonsFlag,
 		//utils.LightServFlag,
 		//utils.LightPeersFlag,
 		//utils.LightKDFFlag,
@@ -135,11 +138,19 @@ var (
 		utils.RPCPortFlag,
 		utils.RPCApiFlag,
 		utils.RPCTimeoutFlag,
+		utils.RPCGlobalGasCapFlag,
+		utils.RPCGlobalEVMTimeoutFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.AuthRPCEnabledFlag,
+		utils.AuthRPCListenAddrFlag,
+		utils.AuthRPCPortFlag,
+		utils.AuthRPCVirtualHostsFlag,
+		utils.AuthRPCApiFlag,
+		utils.AuthRPCJWTSecretFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 	}
@@ -172,12 +183,22 @@ func init() {
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
+		// See privacycmd.go:
+		privacyCommand,
+		trc21Command,
+		// See relayercmd.go:
+		relayerCommand,
+		tomoxCommand,
 		// See consolecmd.go:
 		consoleCommand,
 		attachCommand,
 		javascriptCommand,
 		// See dbcmd.go:
 		dbCommand,
+		// See posvcmd.go:
+		posvCommand,
+		devnetCommand,
+		checkpointCommand,
 		// See misccmd.go:
 		versionCommand,
 		// See config.go
@@ -328,8 +349,8 @@ func startNode(ctx *cli.Context, stack *node.Node, cfg tomoConfig) {
 				started = true
 				log.Info("Enabled mining node!!!")
 			}
-			defer close(core.CheckpointCh)
-			for range core.CheckpointCh {
+			defer close(ethereum.BlockChain().CheckpointCh)
+			for range ethereum.BlockChain().CheckpointCh {
 				log.Info("Checkpoint!!! It's time to reconcile node's state...")
 				ok, err := ethereum.ValidateMasternode()
 				if err != nil {
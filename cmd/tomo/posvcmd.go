@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/eth"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	posvCommand = cli.Command{
+		Name:      "posv",
+		Usage:     "PoSV consensus audit tools",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			posvVerifyM2Cmd,
+		},
+	}
+	posvVerifyM2Cmd = cli.Command{
+		Action:    utils.MigrateFlags(posvVerifyM2),
+		Name:      "verify-m2",
+		Usage:     "Verify the validators (M2) assigned at an epoch checkpoint",
+		ArgsUsage: "<epoch> [<block>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+		},
+		Description: `
+The verify-m2 command recomputes the masternode to validator (M1 -> M2) mapping
+of the given epoch from the secrets and openings stored in the randomize contract,
+compares it with the validators recorded in the checkpoint header and reports the
+masternodes which did not reveal their openings.
+
+Past TIPRandomize the validators rotate within the epoch, so the mapping printed
+is the one of the given block of the epoch, the checkpoint by default.
+
+It needs the state of the block preceding the checkpoint.`,
+	}
+)
+
+// posvVerifyM2 re-derives the M2 validators of an epoch from the local chain
+// database and prints how they compare with the checkpoint header.
+func posvVerifyM2(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
+		utils.Fatalf("This command requires an epoch and an optional block number.")
+	}
+	epoch, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid epoch number: %v", err)
+	}
+	var block *big.Int
+	if len(ctx.Args()) == 2 {
+		number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+		block = new(big.Int).SetUint64(number)
+	}
+	chainDB, _, _, blockchain, err := setupChain(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open blockchain: %v", err)
+	}
+	defer chainDB.Close()
+	defer blockchain.Stop()
+
+	mapping, err := eth.GetM1M2Mapping(blockchain, epoch, block)
+	if err != nil {
+		utils.Fatalf("Failed to build M1/M2 mapping: %v", err)
+	}
+	fmt.Printf("Checkpoint:  %d\n", mapping.Checkpoint)
+	fmt.Printf("Block:       %d\n", mapping.Block)
+	fmt.Printf("Masternodes: %d\n", len(mapping.Mapping))
+	for _, entry := range mapping.Mapping {
+		fmt.Printf("  %s -> %s (random %d, revealed %t)\n", entry.Masternode.Hex(), entry.Validator.Hex(), entry.Random, entry.Revealed)
+	}
+	fmt.Printf("Unrevealed:  %d\n", len(mapping.Unrevealed))
+	for _, addr := range mapping.Unrevealed {
+		fmt.Printf("  %s\n", addr.Hex())
+	}
+	if !mapping.Match {
+		fmt.Printf("Header validators:   %v\n", mapping.HeaderValidators)
+		fmt.Printf("Computed validators: %v\n", mapping.ComputedValidators)
+		utils.Fatalf("Validators of checkpoint %d do not match the randomize contract", mapping.Checkpoint)
+	}
+	fmt.Println("Validators match the randomize contract")
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/tomochain/tomochain/accounts/keystore"
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/core/vm/privacy"
+	"github.com/tomochain/tomochain/internal/ethapi"
+	"github.com/tomochain/tomochain/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	privacyMessageFlag = cli.StringFlag{
+		Name:  "message",
+		Usage: "Hash of the message to sign",
+	}
+	privacyTxKeyFlag = cli.StringFlag{
+		Name:  "txkey",
+		Usage: "Transaction key of the output to spend",
+	}
+	privacyDecoysFlag = cli.StringFlag{
+		Name:  "decoys",
+		Usage: "Comma separated one-time keys to hide the spent output among",
+	}
+	privacyFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to scan",
+	}
+	privacyToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block to scan (default = head block)",
+	}
+
+	privacyCommand = cli.Command{
+		Name:      "privacy",
+		Usage:     "Private transfer wallet tools",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Description: `
+Tools creating the stealth addresses, ring signatures and range proofs of the
+private transfers verified by the ring signature (30) and bulletproof (40)
+precompiles, along with the stealth transfers of plain value to one-time keys
+and a scanner of the ones sent to an account.
+
+The same tools are served by the privacy RPC namespace.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(privacyAddress),
+				Name:      "address",
+				Usage:     "Print the stealth address of an account",
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+				},
+			},
+			{
+				Action:    utils.MigrateFlags(privacyOneTime),
+				Name:      "onetime",
+				Usage:     "Derive a new one-time key to send an output to a stealth address",
+				ArgsUsage: "<stealth address>",
+			},
+			{
+				Action:    utils.MigrateFlags(privacyTransfer),
+				Name:      "transfer",
+				Usage:     "Print the recipient and the data of a stealth transfer to a stealth address",
+				ArgsUsage: "<stealth address>",
+				Description: `
+The transfer command derives a new one-time key of the stealth address. Sending
+value to the printed recipient with the printed data, the transaction key of
+the one-time key, lets the owner of the stealth address find and spend it.`,
+			},
+			{
+				Action:    utils.MigrateFlags(privacyProve),
+				Name:      "prove",
+				Usage:     "Create a range proof of 1, 2, 4 or 8 values",
+				ArgsUsage: "<value> [<value>...]",
+			},
+			{
+				Action:    utils.MigrateFlags(privacySign),
+				Name:      "sign",
+				Usage:     "Create a ring signature spending an output of an account",
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+					privacyMessageFlag,
+					privacyTxKeyFlag,
+					privacyDecoysFlag,
+				},
+			},
+			{
+				Action:    utils.MigrateFlags(privacyScan),
+				Name:      "scan",
+				Usage:     "Find the stealth transfers sent to an account",
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+					utils.CacheFlag,
+					privacyFromFlag,
+					privacyToFlag,
+				},
+				Description: `
+The scan command reads the blocks of the local chain and prints the stealth
+transfers whose one-time key was derived from the stealth address of the
+account.`,
+			},
+		},
+	}
+)
+
+// privacyKeys unlocks the account given as argument and derives its stealth keys.
+func privacyKeys(ctx *cli.Context) *privacy.StealthKeys {
+	stack, _ := makeConfigNode(ctx)
+	return stealthKeys(ctx, stack)
+}
+
+// stealthKeys unlocks the account given as argument in the keystore of the
+// node and derives its stealth keys.
+func stealthKeys(ctx *cli.Context, stack *node.Node) *privacy.StealthKeys {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an account address.")
+	}
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	account, password := unlockAccount(ctx, ks, ctx.Args().First(), 0, utils.MakePasswordList(ctx))
+	keys, err := ethapi.StealthKeys(stack.AccountManager(), account.Address, password)
+	if err != nil {
+		utils.Fatalf("Failed to derive stealth keys: %v", err)
+	}
+	return keys
+}
+
+func privacyAddress(ctx *cli.Context) error {
+	keys := privacyKeys(ctx)
+	fmt.Printf("Stealth address: %s\n", hexutil.Encode(keys.Address().Bytes()))
+	return nil
+}
+
+func privacyOneTime(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a stealth address.")
+	}
+	addr, err := privacy.ParseStealthAddress(common.FromHex(ctx.Args().First()))
+	if err != nil {
+		utils.Fatalf("Invalid stealth address: %v", err)
+	}
+	oneTime, txKey, err := addr.GenerateOneTimeKey()
+	if err != nil {
+		utils.Fatalf("Failed to derive one-time key: %v", err)
+	}
+	fmt.Printf("One-time key:    %s\n", hexutil.Encode(privacy.SerializeCompressed(oneTime)))
+	fmt.Printf("Transaction key: %s\n", hexutil.Encode(privacy.SerializeCompressed(txKey)))
+	return nil
+}
+
+func privacyTransfer(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a stealth address.")
+	}
+	addr, err := privacy.ParseStealthAddress(common.FromHex(ctx.Args().First()))
+	if err != nil {
+		utils.Fatalf("Invalid stealth address: %v", err)
+	}
+	to, data, err := privacy.StealthTransfer(addr)
+	if err != nil {
+		utils.Fatalf("Failed to derive one-time key: %v", err)
+	}
+	fmt.Printf("Recipient: %s\n", to.Hex())
+	fmt.Printf("Data:      %s\n", hexutil.Encode(data))
+	return nil
+}
+
+func privacyProve(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		utils.Fatalf("This command requires at least one value.")
+	}
+	values := make([]*big.Int, len(ctx.Args()))
+	for i, arg := range ctx.Args() {
+		value, ok := new(big.Int).SetString(arg, 0)
+		if !ok {
+			utils.Fatalf("Invalid value: %s", arg)
+		}
+		values[i] = value
+	}
+	proof, commitments, blindings, err := privacy.ProveRange(values)
+	if err != nil {
+		utils.Fatalf("Failed to prove range: %v", err)
+	}
+	for i, commitment := range commitments {
+		fmt.Printf("Commitment %d: %s, blinding factor %s\n", i, hexutil.Encode(privacy.SerializeCompressed(commitment)), hexutil.EncodeBig(blindings[i]))
+	}
+	fmt.Printf("Proof: %s\n", hexutil.Encode(proof))
+	return nil
+}
+
+func privacySign(ctx *cli.Context) error {
+	if !ctx.IsSet(privacyMessageFlag.Name) || !ctx.IsSet(privacyTxKeyFlag.Name) || !ctx.IsSet(privacyDecoysFlag.Name) {
+		utils.Fatalf("The --%s, --%s and --%s flags are required.", privacyMessageFlag.Name, privacyTxKeyFlag.Name, privacyDecoysFlag.Name)
+	}
+	txKey, err := privacy.ParseCompressed(common.FromHex(ctx.String(privacyTxKeyFlag.Name)))
+	if err != nil {
+		utils.Fatalf("Invalid transaction key: %v", err)
+	}
+	var decoys []*ecdsa.PublicKey
+	for _, decoy := range strings.Split(ctx.String(privacyDecoysFlag.Name), ",") {
+		pub, err := privacy.ParseCompressed(common.FromHex(strings.TrimSpace(decoy)))
+		if err != nil {
+			utils.Fatalf("Invalid decoy %s: %v", decoy, err)
+		}
+		decoys = append(decoys, pub)
+	}
+	keys := privacyKeys(ctx)
+	message := common.HexToHash(ctx.String(privacyMessageFlag.Name))
+	sig, err := privacy.SignInputs(message, []*ecdsa.PrivateKey{keys.OutputKey(txKey)}, [][]*ecdsa.PublicKey{decoys})
+	if err != nil {
+		utils.Fatalf("Failed to sign: %v", err)
+	}
+	fmt.Printf("Ring signature: %s\n", hexutil.Encode(sig))
+	return nil
+}
+
+func privacyScan(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	keys := stealthKeys(ctx, stack)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	first, last := ctx.Uint64(privacyFromFlag.Name), chain.CurrentBlock().NumberU64()
+	if ctx.IsSet(privacyToFlag.Name) && ctx.Uint64(privacyToFlag.Name) < last {
+		last = ctx.Uint64(privacyToFlag.Name)
+	}
+	found := 0
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			utils.Fatalf("Block %d not found", number)
+		}
+		txs := block.Transactions()
+		for _, i := range privacy.ScanTransfers(keys, txs) {
+			fmt.Printf("Block %d, transaction %s: %v wei to %s, transaction key %s\n", number, txs[i].Hash().Hex(),
+				txs[i].Value(), txs[i].To().Hex(), hexutil.Encode(txs[i].Data()))
+			found++
+		}
+	}
+	fmt.Printf("Found %d stealth transfers in blocks %d-%d\n", found, first, last)
+	return nil
+}
@@ -0,0 +1,417 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/contracts/tomox"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/ethclient"
+	"github.com/tomochain/tomochain/internal/ethapi"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// tomoxListingFee is the fee paid to list a token in the TomoX listing contract.
+var tomoxListingFee = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+
+// maxRelayerFee is the exclusive upper bound of the trading fee of a relayer.
+const maxRelayerFee = 1000
+
+var (
+	relayerFeeFlag = cli.UintFlag{
+		Name:  "fee",
+		Usage: "Trading fee of the relayer, in 1/10000 of the traded amount",
+	}
+	relayerPairsFlag = cli.StringFlag{
+		Name:  "pairs",
+		Usage: "Comma separated <base token>/<quote token> pairs traded by the relayer",
+	}
+	relayerValueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Amount of wei deposited (default = minimum deposit of the registration contract)",
+	}
+
+	relayerCommand = cli.Command{
+		Name:      "relayer",
+		Usage:     "Manage the TomoX relayers",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Description: `
+Tools registering the relayers of the TomoX exchange in the relayer registration
+contract and managing their deposit, trading fee and pairs.
+
+The commands attach to a running node, over IPC by default, and sign their
+transactions with the keys of the local keystore. The first argument is the
+account owning the relayer, the second one the coinbase of the relayer.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(relayerRegister),
+				Name:      "register",
+				Usage:     "Register a relayer trading the given pairs",
+				ArgsUsage: "<address> <coinbase>",
+				Flags:     append([]cli.Flag{relayerFeeFlag, relayerPairsFlag, relayerValueFlag}, trc21TxFlags...),
+				Description: `
+The register command first checks that the coinbase isn't registered, that the
+deposit covers the minimum one and that every token of the pairs is listed and
+keeps its balances and decimals where TomoX reads them from.`,
+			},
+			{
+				Action:    utils.MigrateFlags(relayerUpdate),
+				Name:      "update",
+				Usage:     "Replace the trading fee and the pairs of a relayer",
+				ArgsUsage: "<address> <coinbase>",
+				Flags:     append([]cli.Flag{relayerFeeFlag, relayerPairsFlag}, trc21TxFlags...),
+			},
+			{
+				Action:    utils.MigrateFlags(relayerDeposit),
+				Name:      "deposit",
+				Usage:     "Add to the deposit of a relayer",
+				ArgsUsage: "<address> <coinbase>",
+				Flags:     append([]cli.Flag{relayerValueFlag}, trc21TxFlags...),
+			},
+			{
+				Action:    utils.MigrateFlags(relayerResign),
+				Name:      "resign",
+				Usage:     "Resign a relayer, releasing its deposit after 4 weeks",
+				ArgsUsage: "<address> <coinbase>",
+				Flags:     trc21TxFlags,
+			},
+			{
+				Action:    utils.MigrateFlags(relayerTransfer),
+				Name:      "transfer",
+				Usage:     "Transfer the ownership of a relayer",
+				ArgsUsage: "<address> <coinbase> <new owner>",
+				Flags:     trc21TxFlags,
+			},
+			{
+				Action:    utils.MigrateFlags(relayerListPairs),
+				Name:      "list-pairs",
+				Usage:     "Print the deposit, trading fee and pairs of a relayer, or of all of them",
+				ArgsUsage: "[<coinbase>]",
+				Flags:     []cli.Flag{trc21AttachFlag},
+			},
+		},
+	}
+
+	tomoxCommand = cli.Command{
+		Name:      "tomox",
+		Usage:     "Manage the tokens of the TomoX exchange",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(tomoxListToken),
+				Name:      "list-token",
+				Usage:     "List a token in the TomoX listing contract",
+				ArgsUsage: "<address> <token>",
+				Flags:     trc21TxFlags,
+				Description: `
+The list-token command first checks that the token isn't listed yet and that it
+keeps its balances and decimals where TomoX reads them from, so that the listing
+fee isn't spent on a token which can't be traded. It warns if the fees of the
+transactions sent to the token can't be sponsored by the TRC21 issuer contract.`,
+			},
+		},
+	}
+)
+
+// relayerCoinbase parses the coinbase given as second argument.
+func relayerCoinbase(ctx *cli.Context) common.Address {
+	arg := ctx.Args().Get(1)
+	if !common.IsHexAddress(arg) {
+		utils.Fatalf("Invalid coinbase address: %s", arg)
+	}
+	return common.HexToAddress(arg)
+}
+
+// relayerInfo retrieves the registration of a relayer at the latest block, nil
+// if it isn't registered.
+func relayerInfo(client *rpc.Client, coinbase common.Address) *ethapi.RelayerInfo {
+	var info *ethapi.RelayerInfo
+	if err := client.Call(&info, "tomox_getRelayer", coinbase, "latest"); err != nil {
+		utils.Fatalf("Failed to retrieve relayer: %v", err)
+	}
+	return info
+}
+
+// relayerOwned retrieves the registration of a relayer and fails if the relayer
+// isn't active or isn't owned by the given account.
+func relayerOwned(client *rpc.Client, coinbase, owner common.Address) *ethapi.RelayerInfo {
+	info := relayerInfo(client, coinbase)
+	switch {
+	case info == nil:
+		utils.Fatalf("Relayer %s is not registered", coinbase.Hex())
+	case info.Owner != owner:
+		utils.Fatalf("Relayer %s is owned by %s", coinbase.Hex(), info.Owner.Hex())
+	case info.ReleaseTime != nil:
+		utils.Fatalf("Relayer %s has resigned", coinbase.Hex())
+	}
+	return info
+}
+
+// relayerPairs parses the trading fee and the pairs given by the flags and
+// checks that every token of the pairs can be traded.
+func relayerPairs(ctx *cli.Context, client *rpc.Client) (uint16, []common.Address, []common.Address) {
+	if !ctx.IsSet(relayerFeeFlag.Name) || !ctx.IsSet(relayerPairsFlag.Name) {
+		utils.Fatalf("The --%s and --%s flags are required.", relayerFeeFlag.Name, relayerPairsFlag.Name)
+	}
+	fee := ctx.Uint(relayerFeeFlag.Name)
+	if fee >= maxRelayerFee {
+		utils.Fatalf("Invalid --%s: %d, must be below %d", relayerFeeFlag.Name, fee, maxRelayerFee)
+	}
+	var listed []common.Address
+	if err := client.Call(&listed, "tomox_listTokens", "latest"); err != nil {
+		utils.Fatalf("Failed to list tokens: %v", err)
+	}
+	checked := map[common.Address]bool{common.HexToAddress(common.TomoNativeAddress): true}
+	for _, token := range listed {
+		checked[token] = false
+	}
+	var bases, quotes []common.Address
+	for _, pair := range strings.Split(ctx.String(relayerPairsFlag.Name), ",") {
+		tokens := strings.Split(strings.TrimSpace(pair), "/")
+		if len(tokens) != 2 || !common.IsHexAddress(tokens[0]) || !common.IsHexAddress(tokens[1]) {
+			utils.Fatalf("Invalid pair: %s", pair)
+		}
+		base, quote := common.HexToAddress(tokens[0]), common.HexToAddress(tokens[1])
+		for _, token := range []common.Address{base, quote} {
+			valid, ok := checked[token]
+			if !ok {
+				utils.Fatalf("Token %s is not listed in the TomoX listing contract", token.Hex())
+			}
+			if !valid {
+				if err := client.Call(&valid, "tomox_validateToken", token, "latest"); err != nil || !valid {
+					utils.Fatalf("Token %s can't be traded: %v", token.Hex(), err)
+				}
+				checked[token] = true
+			}
+		}
+		bases, quotes = append(bases, base), append(quotes, quote)
+	}
+	return uint16(fee), bases, quotes
+}
+
+// relayerSession binds the relayer registration contract for the account
+// given as first argument.
+func relayerSession(ctx *cli.Context, client *ethclient.Client) *tomox.RelayerRegistration {
+	registration, err := tomox.NewRelayerRegistration(trc21Transactor(ctx, client), common.HexToAddress(common.RelayerRegistrationSMC), client)
+	if err != nil {
+		utils.Fatalf("Failed to bind the relayer registration contract: %v", err)
+	}
+	return registration
+}
+
+// relayerPrint prints the registration of a relayer.
+func relayerPrint(info *ethapi.RelayerInfo) {
+	fmt.Printf("Relayer %s\n", info.Coinbase.Hex())
+	fmt.Printf("  Owner:       %s\n", info.Owner.Hex())
+	fmt.Printf("  Deposit:     %v wei\n", info.Deposit.ToInt())
+	fmt.Printf("  Trading fee: %d/10000\n", info.TradeFee)
+	if info.ReleaseTime != nil {
+		fmt.Printf("  Resigned, deposit released at %v\n", time.Unix(info.ReleaseTime.ToInt().Int64(), 0).UTC())
+	}
+	for _, pair := range info.Pairs {
+		fmt.Printf("  Pair:        %s/%s\n", pair.BaseToken.Hex(), pair.QuoteToken.Hex())
+	}
+}
+
+func relayerRegister(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires an account address and a coinbase address.")
+	}
+	coinbase := relayerCoinbase(ctx)
+
+	rpcClient := trc21Client(ctx)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	if info := relayerInfo(rpcClient, coinbase); info != nil {
+		utils.Fatalf("Relayer %s is already registered by %s", coinbase.Hex(), info.Owner.Hex())
+	}
+	fee, bases, quotes := relayerPairs(ctx, rpcClient)
+
+	var minDeposit hexutil.Big
+	if err := rpcClient.Call(&minDeposit, "tomox_getMinimumDeposit", "latest"); err != nil {
+		utils.Fatalf("Failed to retrieve the minimum deposit: %v", err)
+	}
+	deposit := minDeposit.ToInt()
+	if ctx.IsSet(relayerValueFlag.Name) {
+		if deposit = trc21Amount(ctx, relayerValueFlag.Name); deposit.Cmp(minDeposit.ToInt()) < 0 {
+			utils.Fatalf("Deposit %v wei is below the minimum deposit of %v wei", deposit, minDeposit.ToInt())
+		}
+	}
+	registration := relayerSession(ctx, client)
+	if registration.TransactOpts.From == coinbase {
+		utils.Fatalf("The coinbase of a relayer can't be its owner")
+	}
+	registration.TransactOpts.Value = deposit
+
+	tx, err := registration.Register(coinbase, fee, bases, quotes)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	trc21Wait(client, tx)
+	relayerPrint(relayerInfo(rpcClient, coinbase))
+	return nil
+}
+
+func relayerUpdate(ctx *cli.Context) error {
+	return relayerSend(ctx, 2, func(ctx *cli.Context, client *rpc.Client, registration *tomox.RelayerRegistration, coinbase common.Address) (*types.Transaction, error) {
+		fee, bases, quotes := relayerPairs(ctx, client)
+		return registration.Update(coinbase, fee, bases, quotes)
+	})
+}
+
+func relayerDeposit(ctx *cli.Context) error {
+	return relayerSend(ctx, 2, func(ctx *cli.Context, client *rpc.Client, registration *tomox.RelayerRegistration, coinbase common.Address) (*types.Transaction, error) {
+		if !ctx.IsSet(relayerValueFlag.Name) {
+			utils.Fatalf("The --%s flag is required.", relayerValueFlag.Name)
+		}
+		if registration.TransactOpts.Value = trc21Amount(ctx, relayerValueFlag.Name); registration.TransactOpts.Value.Cmp(big.NewInt(params.Ether)) < 0 {
+			utils.Fatalf("At least 1 TOMO must be deposited")
+		}
+		return registration.DepositMore(coinbase)
+	})
+}
+
+func relayerResign(ctx *cli.Context) error {
+	return relayerSend(ctx, 2, func(ctx *cli.Context, client *rpc.Client, registration *tomox.RelayerRegistration, coinbase common.Address) (*types.Transaction, error) {
+		return registration.Resign(coinbase)
+	})
+}
+
+func relayerTransfer(ctx *cli.Context) error {
+	return relayerSend(ctx, 3, func(ctx *cli.Context, client *rpc.Client, registration *tomox.RelayerRegistration, coinbase common.Address) (*types.Transaction, error) {
+		arg := ctx.Args().Get(2)
+		if !common.IsHexAddress(arg) {
+			utils.Fatalf("Invalid owner address: %s", arg)
+		}
+		owner := common.HexToAddress(arg)
+		if owner == registration.TransactOpts.From {
+			utils.Fatalf("Relayer %s is already owned by %s", coinbase.Hex(), owner.Hex())
+		}
+		if relayerInfo(client, owner) != nil {
+			utils.Fatalf("Owner %s is the coinbase of a relayer", owner.Hex())
+		}
+		return registration.Transfer(coinbase, owner)
+	})
+}
+
+// relayerSend checks that the account given as first argument owns the active
+// relayer of the coinbase given as second argument, sends the transaction made
+// by the given function and prints the registration of the relayer once mined.
+func relayerSend(ctx *cli.Context, args int, send func(*cli.Context, *rpc.Client, *tomox.RelayerRegistration, common.Address) (*types.Transaction, error)) error {
+	if len(ctx.Args()) != args {
+		utils.Fatalf("This command requires %d arguments.", args)
+	}
+	coinbase := relayerCoinbase(ctx)
+
+	rpcClient := trc21Client(ctx)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	registration := relayerSession(ctx, client)
+	relayerOwned(rpcClient, coinbase, registration.TransactOpts.From)
+
+	tx, err := send(ctx, rpcClient, registration, coinbase)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	trc21Wait(client, tx)
+	relayerPrint(relayerInfo(rpcClient, coinbase))
+	return nil
+}
+
+func relayerListPairs(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most a coinbase address.")
+	}
+	client := trc21Client(ctx)
+	defer client.Close()
+
+	if len(ctx.Args()) == 1 {
+		arg := ctx.Args().First()
+		if !common.IsHexAddress(arg) {
+			utils.Fatalf("Invalid coinbase address: %s", arg)
+		}
+		info := relayerInfo(client, common.HexToAddress(arg))
+		if info == nil {
+			utils.Fatalf("Relayer %s is not registered", arg)
+		}
+		relayerPrint(info)
+		return nil
+	}
+	var relayers []*ethapi.RelayerInfo
+	if err := client.Call(&relayers, "tomox_listRelayers", "latest"); err != nil {
+		utils.Fatalf("Failed to list relayers: %v", err)
+	}
+	for _, info := range relayers {
+		relayerPrint(info)
+	}
+	fmt.Printf("%d relayers registered\n", len(relayers))
+	return nil
+}
+
+func tomoxListToken(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires an account address and a token address.")
+	}
+	token := trc21Token(ctx, 1)
+
+	rpcClient := trc21Client(ctx)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	var listed []common.Address
+	if err := rpcClient.Call(&listed, "tomox_listTokens", "latest"); err != nil {
+		utils.Fatalf("Failed to list tokens: %v", err)
+	}
+	for _, addr := range listed {
+		if addr == token {
+			utils.Fatalf("Token %s is already listed", token.Hex())
+		}
+	}
+	var valid bool
+	if err := rpcClient.Call(&valid, "tomox_validateToken", token, "latest"); err != nil || !valid {
+		utils.Fatalf("Token %s can't be traded: %v", token.Hex(), err)
+	}
+	if err := rpcClient.Call(&valid, "trc21_validateToken", token, "latest"); err != nil || !valid {
+		fmt.Printf("Warning: the fees of token %s can't be sponsored: %v\n", token.Hex(), err)
+	}
+	listing, err := tomox.NewMyTOMOXListing(trc21Transactor(ctx, client), common.TomoXListingSMC, client)
+	if err != nil {
+		utils.Fatalf("Failed to bind the TomoX listing contract: %v", err)
+	}
+	listing.TransactOpts.Value = tomoxListingFee
+
+	fmt.Printf("Listing token %s for a fee of %v wei\n", token.Hex(), tomoxListingFee)
+	tx, err := listing.Apply(token)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	trc21Wait(client, tx)
+	fmt.Printf("Token %s listed\n", token.Hex())
+	return nil
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2020 Victionchain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// this program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/tomochain/tomochain/accounts/abi/bind"
+	"github.com/tomochain/tomochain/accounts/keystore"
+	"github.com/tomochain/tomochain/cmd/utils"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/contracts/trc21issuer"
+	"github.com/tomochain/tomochain/contracts/trc21issuer/contract"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/ethclient"
+	"github.com/tomochain/tomochain/node"
+	"github.com/tomochain/tomochain/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	trc21AttachFlag = cli.StringFlag{
+		Name:  "attach",
+		Value: node.DefaultIPCEndpoint(clientIdentifier),
+		Usage: "API endpoint to attach to",
+	}
+	trc21NameFlag = cli.StringFlag{
+		Name:  "name",
+		Usage: "Name of the token",
+	}
+	trc21SymbolFlag = cli.StringFlag{
+		Name:  "symbol",
+		Usage: "Symbol of the token",
+	}
+	trc21DecimalsFlag = cli.UintFlag{
+		Name:  "decimals",
+		Value: 18,
+		Usage: "Number of decimals of the token",
+	}
+	trc21CapFlag = cli.StringFlag{
+		Name:  "cap",
+		Usage: "Total supply of the token, minted to the issuer",
+	}
+	trc21FeeFlag = cli.StringFlag{
+		Name:  "fee",
+		Value: "0",
+		Usage: "Minimum fee of the token transfers, paid to the issuer",
+	}
+	trc21ValueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Amount of wei added to the fee capacity of the token (default = minimum capacity of the issuer contract)",
+	}
+	trc21GasPriceFlag = cli.StringFlag{
+		Name:  "gasprice",
+		Usage: "Gas price of the transactions in wei (default = suggested by the node)",
+	}
+
+	// trc21TxFlags are the flags of the commands sending transactions.
+	trc21TxFlags = []cli.Flag{
+		trc21AttachFlag,
+		utils.DataDirFlag,
+		utils.KeyStoreDirFlag,
+		utils.PasswordFileFlag,
+		utils.LightKDFFlag,
+		trc21GasPriceFlag,
+	}
+
+	trc21Command = cli.Command{
+		Name:      "trc21",
+		Usage:     "Manage the TRC21 tokens sponsoring transaction fees",
+		ArgsUsage: "",
+		Category:  "ACCOUNT COMMANDS",
+		Description: `
+Tools deploying TRC21 tokens and managing their fee capacity in the TRC21 issuer
+contract, which pays the fees of the transactions sent to the applied tokens.
+
+The commands attach to a running node, over IPC by default, and sign their
+transactions with the keys of the local keystore.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(trc21Deploy),
+				Name:      "deploy",
+				Usage:     "Deploy a TRC21 token issued by an account",
+				ArgsUsage: "<address>",
+				Flags: append([]cli.Flag{
+					trc21NameFlag,
+					trc21SymbolFlag,
+					trc21DecimalsFlag,
+					trc21CapFlag,
+					trc21FeeFlag,
+				}, trc21TxFlags...),
+			},
+			{
+				Action:    utils.MigrateFlags(trc21Apply),
+				Name:      "apply",
+				Usage:     "Apply a token to the fee sponsorship of the TRC21 issuer contract",
+				ArgsUsage: "<address> <token>",
+				Flags:     append([]cli.Flag{trc21ValueFlag}, trc21TxFlags...),
+				Description: `
+The apply command first checks that the token keeps its balances and its minimum
+fee at the storage slots the fee sponsorship reads them from, so that no fee is
+spent on a token the issuer contract would accept but the chain wouldn't sponsor.`,
+			},
+			{
+				Action:    utils.MigrateFlags(trc21Charge),
+				Name:      "charge",
+				Usage:     "Add to the fee capacity of an applied token",
+				ArgsUsage: "<address> <token>",
+				Flags:     append([]cli.Flag{trc21ValueFlag}, trc21TxFlags...),
+			},
+			{
+				Action:    utils.MigrateFlags(trc21Capacity),
+				Name:      "capacity",
+				Usage:     "Print the fee capacity left to a token",
+				ArgsUsage: "<token>",
+				Flags:     []cli.Flag{trc21AttachFlag},
+			},
+			{
+				Action:    utils.MigrateFlags(trc21List),
+				Name:      "list",
+				Usage:     "Print the tokens applied to the TRC21 issuer contract with their fee capacity",
+				ArgsUsage: "",
+				Flags:     []cli.Flag{trc21AttachFlag},
+			},
+		},
+	}
+)
+
+// trc21Client attaches to the node serving the TRC21 commands.
+func trc21Client(ctx *cli.Context) *rpc.Client {
+	client, err := dialRPC(ctx.String(trc21AttachFlag.Name))
+	if err != nil {
+		utils.Fatalf("Unable to attach to tomo node: %v", err)
+	}
+	return client
+}
+
+// trc21Transactor unlocks the account given as first argument and returns the
+// options signing its transactions.
+func trc21Transactor(ctx *cli.Context, client *ethclient.Client) *bind.TransactOpts {
+	stack, _ := makeConfigNode(ctx)
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	account, password := unlockAccount(ctx, ks, ctx.Args().First(), 0, utils.MakePasswordList(ctx))
+	keyJSON, err := ks.Export(account, password, password)
+	if err != nil {
+		utils.Fatalf("Failed to export account key: %v", err)
+	}
+	opts, err := bind.NewTransactor(bytes.NewReader(keyJSON), password)
+	if err != nil {
+		utils.Fatalf("Failed to decrypt account key: %v", err)
+	}
+	if ctx.IsSet(trc21GasPriceFlag.Name) {
+		opts.GasPrice = trc21Amount(ctx, trc21GasPriceFlag.Name)
+	} else if opts.GasPrice, err = client.SuggestGasPrice(context.Background()); err != nil {
+		utils.Fatalf("Failed to suggest gas price: %v", err)
+	}
+	return opts
+}
+
+// trc21Amount parses the amount of wei given by a flag.
+func trc21Amount(ctx *cli.Context, name string) *big.Int {
+	amount, ok := new(big.Int).SetString(ctx.String(name), 0)
+	if !ok || amount.Sign() < 0 {
+		utils.Fatalf("Invalid --%s: %s", name, ctx.String(name))
+	}
+	return amount
+}
+
+// trc21Token parses the token address given as argument.
+func trc21Token(ctx *cli.Context, i int) common.Address {
+	arg := ctx.Args().Get(i)
+	if !common.IsHexAddress(arg) {
+		utils.Fatalf("Invalid token address: %s", arg)
+	}
+	return common.HexToAddress(arg)
+}
+
+// trc21Wait waits for a transaction to be mined and fails if it reverted.
+func trc21Wait(client *ethclient.Client, tx *types.Transaction) {
+	fmt.Printf("Transaction %s sent, waiting for it to be mined...\n", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	if err != nil {
+		utils.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		utils.Fatalf("Transaction %s failed", tx.Hash().Hex())
+	}
+}
+
+// trc21PrintCapacity prints the fee capacity of a token at the latest block.
+func trc21PrintCapacity(client *rpc.Client, token common.Address) {
+	var capacity *hexutil.Big
+	if err := client.Call(&capacity, "trc21_getFeeCapacity", token, "latest"); err != nil {
+		utils.Fatalf("Failed to retrieve fee capacity: %v", err)
+	}
+	if capacity == nil {
+		fmt.Printf("Token %s is not applied to the TRC21 issuer contract\n", token.Hex())
+		return
+	}
+	fmt.Printf("Fee capacity of %s: %v wei\n", token.Hex(), capacity.ToInt())
+}
+
+func trc21Deploy(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an account address.")
+	}
+	if !ctx.IsSet(trc21NameFlag.Name) || !ctx.IsSet(trc21SymbolFlag.Name) || !ctx.IsSet(trc21CapFlag.Name) {
+		utils.Fatalf("The --%s, --%s and --%s flags are required.", trc21NameFlag.Name, trc21SymbolFlag.Name, trc21CapFlag.Name)
+	}
+	if ctx.Uint(trc21DecimalsFlag.Name) > 255 {
+		utils.Fatalf("Invalid --%s: %d", trc21DecimalsFlag.Name, ctx.Uint(trc21DecimalsFlag.Name))
+	}
+	rpcClient := trc21Client(ctx)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+	opts := trc21Transactor(ctx, client)
+
+	addr, tx, _, err := contract.DeployMyTRC21(opts, client,
+		ctx.String(trc21NameFlag.Name), ctx.String(trc21SymbolFlag.Name), uint8(ctx.Uint(trc21DecimalsFlag.Name)),
+		trc21Amount(ctx, trc21CapFlag.Name), trc21Amount(ctx, trc21FeeFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to deploy token: %v", err)
+	}
+	trc21Wait(client, tx)
+	fmt.Printf("Token %s deployed, issued by %s\n", addr.Hex(), opts.From.Hex())
+	return nil
+}
+
+func trc21Apply(ctx *cli.Context) error {
+	return trc21Send(ctx, true)
+}
+
+func trc21Charge(ctx *cli.Context) error {
+	return trc21Send(ctx, false)
+}
+
+// trc21Send applies a token to the TRC21 issuer contract or charges its fee
+// capacity.
+func trc21Send(ctx *cli.Context, apply bool) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires an account address and a token address.")
+	}
+	token := trc21Token(ctx, 1)
+
+	rpcClient := trc21Client(ctx)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	if apply {
+		var valid bool
+		if err := rpcClient.Call(&valid, "trc21_validateToken", token, "latest"); err != nil || !valid {
+			utils.Fatalf("Token %s can't sponsor fees: %v", token.Hex(), err)
+		}
+	}
+	opts := trc21Transactor(ctx, client)
+	issuer, err := trc21issuer.NewTRC21Issuer(opts, common.TRC21IssuerSMC, client)
+	if err != nil {
+		utils.Fatalf("Failed to bind the TRC21 issuer contract: %v", err)
+	}
+	if ctx.IsSet(trc21ValueFlag.Name) {
+		issuer.TransactOpts.Value = trc21Amount(ctx, trc21ValueFlag.Name)
+	} else if issuer.TransactOpts.Value, err = issuer.MinCap(); err != nil {
+		utils.Fatalf("Failed to retrieve the minimum capacity: %v", err)
+	}
+	var tx *types.Transaction
+	if apply {
+		tx, err = issuer.Apply(token)
+	} else {
+		tx, err = issuer.Charge(token)
+	}
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	trc21Wait(client, tx)
+	trc21PrintCapacity(rpcClient, token)
+	return nil
+}
+
+func trc21Capacity(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a token address.")
+	}
+	client := trc21Client(ctx)
+	defer client.Close()
+
+	trc21PrintCapacity(client, trc21Token(ctx, 0))
+	return nil
+}
+
+func trc21List(ctx *cli.Context) error {
+	client := trc21Client(ctx)
+	defer client.Close()
+
+	var tokens map[common.Address]*hexutil.Big
+	if err := client.Call(&tokens, "trc21_listSponsoredTokens", "latest"); err != nil {
+		utils.Fatalf("Failed to list tokens: %v", err)
+	}
+	addrs := make([]common.Address, 0, len(tokens))
+	for addr := range tokens {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	for _, addr := range addrs {
+		fmt.Printf("%s: %v wei\n", addr.Hex(), tokens[addr].ToInt())
+	}
+	fmt.Printf("%d tokens applied\n", len(addrs))
+	return nil
+}
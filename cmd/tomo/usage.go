@@ -74,6 +74,9 @@ var AppHelpFlagGroups = []flagGroup{
 			//utils.RinkebyFlag,
 			utils.SyncModeFlag,
 			utils.GCModeFlag,
+			utils.CheckpointFlag,
+			utils.AddressIndexFlag,
+			utils.RevertReasonsFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			//utils.LightServFlag,
@@ -147,11 +150,19 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
 			utils.RPCTimeoutFlag,
+			utils.RPCGlobalGasCapFlag,
+			utils.RPCGlobalEVMTimeoutFlag,
 			utils.WSEnabledFlag,
 			utils.WSListenAddrFlag,
 			utils.WSPortFlag,
 			utils.WSApiFlag,
 			utils.WSAllowedOriginsFlag,
+			utils.AuthRPCEnabledFlag,
+			utils.AuthRPCListenAddrFlag,
+			utils.AuthRPCPortFlag,
+			utils.AuthRPCVirtualHostsFlag,
+			utils.AuthRPCApiFlag,
+			utils.AuthRPCJWTSecretFlag,
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
 			utils.RPCCORSDomainFlag,
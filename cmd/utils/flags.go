@@ -19,6 +19,7 @@ package utils
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -190,6 +191,18 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	CheckpointFlag = cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: "JSON file of a trusted PoSV checkpoint for fast sync, as printed by `tomo checkpoint dump`",
+	}
+	AddressIndexFlag = cli.BoolFlag{
+		Name:  "addrindex",
+		Usage: "Index the transactions of every address for eth_getTransactionsByAddress",
+	}
+	RevertReasonsFlag = cli.BoolFlag{
+		Name:  "revertreasons",
+		Usage: "Record the revert reasons of failed transactions for their receipts",
+	}
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
 		Usage: "Maximum percentage of time allowed for serving LES requests (0-90)",
@@ -425,6 +438,16 @@ var (
 		Usage: "Timeout for RPC HTTP Handlers in seconds",
 		Value: 120,
 	}
+	RPCGlobalGasCapFlag = cli.Uint64Flag{
+		Name:  "rpc.gascap",
+		Usage: "Gas available to the calls of an eth_callMany request altogether (0=infinite)",
+		Value: eth.DefaultConfig.RPCGasCap,
+	}
+	RPCGlobalEVMTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc.evmtimeout",
+		Usage: "Timeout of eth_call and eth_callMany requests (0=infinite)",
+		Value: eth.DefaultConfig.RPCEVMTimeout,
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -457,6 +480,34 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	AuthRPCEnabledFlag = cli.BoolFlag{
+		Name:  "authrpc",
+		Usage: "Enable the authenticated HTTP and WS-RPC server, serving requests bearing a JWT",
+	}
+	AuthRPCListenAddrFlag = cli.StringFlag{
+		Name:  "authrpc.addr",
+		Usage: "Authenticated RPC server listening interface",
+		Value: node.DefaultAuthHost,
+	}
+	AuthRPCPortFlag = cli.IntFlag{
+		Name:  "authrpc.port",
+		Usage: "Authenticated RPC server listening port",
+		Value: node.DefaultAuthPort,
+	}
+	AuthRPCVirtualHostsFlag = cli.StringFlag{
+		Name:  "authrpc.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept authenticated requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.AuthVirtualHosts, ","),
+	}
+	AuthRPCApiFlag = cli.StringFlag{
+		Name:  "authrpc.api",
+		Usage: "API's offered over the authenticated RPC interface, the tokens granting access per namespace or method, all the listed ones if they don't say. If empty, all of them are offered and only to the tokens granting access",
+		Value: "",
+	}
+	AuthRPCJWTSecretFlag = cli.StringFlag{
+		Name:  "authrpc.jwtsecret",
+		Usage: "Path to the hex-encoded secret the tokens are signed with (default = inside the datadir)",
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -579,6 +630,10 @@ var (
 		Name:  "tomox.dbReplicaSetName",
 		Usage: "ReplicaSetName if Master-Slave is setup",
 	}
+	TomoXLendingPriceFeedFlag = cli.StringFlag{
+		Name:  "tomox.lendingPriceFeed",
+		Usage: "JSON schedule of the prices per block the lending collateral is valued at, instead of the trading prices (not on the mainnet)",
+	}
 	ReexecFlag = cli.IntFlag{
 		Name:  "reexec",
 		Usage: "Reexec blocks",
@@ -777,6 +832,30 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setAuth creates the authenticated RPC listener interface string from the set
+// command line flags, returning empty if the authenticated endpoint is disabled.
+func setAuth(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalBool(AuthRPCEnabledFlag.Name) && cfg.AuthHost == "" {
+		cfg.AuthHost = node.DefaultAuthHost
+		if ctx.GlobalIsSet(AuthRPCListenAddrFlag.Name) {
+			cfg.AuthHost = ctx.GlobalString(AuthRPCListenAddrFlag.Name)
+		}
+	}
+
+	if ctx.GlobalIsSet(AuthRPCPortFlag.Name) {
+		cfg.AuthPort = ctx.GlobalInt(AuthRPCPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(AuthRPCVirtualHostsFlag.Name) {
+		cfg.AuthVirtualHosts = splitAndTrim(ctx.GlobalString(AuthRPCVirtualHostsFlag.Name))
+	}
+	if ctx.GlobalIsSet(AuthRPCApiFlag.Name) {
+		cfg.AuthModules = splitAndTrim(ctx.GlobalString(AuthRPCApiFlag.Name))
+	}
+	if ctx.GlobalIsSet(AuthRPCJWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.GlobalString(AuthRPCJWTSecretFlag.Name)
+	}
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -935,6 +1014,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setAuth(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 
 	switch {
@@ -1025,6 +1105,27 @@ func setEthash(ctx *cli.Context, cfg *eth.Config) {
 	}
 }
 
+// setCheckpoint loads the trusted checkpoint from the file given on the command
+// line, if any.
+func setCheckpoint(ctx *cli.Context, cfg *eth.Config) {
+	file := ctx.GlobalString(CheckpointFlag.Name)
+	if file == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		Fatalf("Failed to read trusted checkpoint: %v", err)
+	}
+	checkpoint := new(params.TrustedCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		Fatalf("Invalid trusted checkpoint %s: %v", file, err)
+	}
+	if checkpoint.Empty() {
+		Fatalf("Trusted checkpoint %s misses its number or hash", file)
+	}
+	cfg.Checkpoint = checkpoint
+}
+
 // checkExclusive verifies that only a single isntance of the provided flags was
 // set by the user. Each flag might optionally be followed by a string type to
 // specialize it further.
@@ -1107,6 +1208,9 @@ func SetTomoXConfig(ctx *cli.Context, cfg *tomox.Config, tomoDataDir string) {
 	if ctx.GlobalIsSet(TomoXDBReplicaSetNameFlag.Name) {
 		cfg.ReplicaSetName = ctx.GlobalString(TomoXDBReplicaSetNameFlag.Name)
 	}
+	if ctx.GlobalIsSet(TomoXLendingPriceFeedFlag.Name) {
+		cfg.LendingPriceFeed = ctx.GlobalString(TomoXLendingPriceFeedFlag.Name)
+	}
 }
 
 // SetEthConfig applies eth-related command line flags to the config.
@@ -1122,6 +1226,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 	setEthash(ctx, cfg)
+	setCheckpoint(ctx, cfg)
 
 	switch {
 	case ctx.GlobalIsSet(SyncModeFlag.Name):
@@ -1150,6 +1255,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
+	if ctx.GlobalIsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.GlobalBool(AddressIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(RevertReasonsFlag.Name) {
+		cfg.RevertReasons = ctx.GlobalBool(RevertReasonsFlag.Name)
+	}
 
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
@@ -1170,6 +1281,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCGlobalGasCapFlag.Name) {
+		cfg.RPCGasCap = ctx.GlobalUint64(RPCGlobalGasCapFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCGlobalEVMTimeoutFlag.Name) {
+		cfg.RPCEVMTimeout = ctx.GlobalDuration(RPCGlobalEVMTimeoutFlag.Name)
+	}
 	if ctx.GlobalIsSet(StoreRewardFlag.Name) {
 		common.StoreRewardFolder = filepath.Join(stack.DataDir(), "tomo", "rewards")
 		if _, err := os.Stat(common.StoreRewardFolder); os.IsNotExist(err) {
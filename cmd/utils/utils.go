@@ -72,8 +72,16 @@ func RegisterTomoXService(stack *node.Node, cfg *tomox.Config) {
 	}
 
 	// register tomoxlending service
+	lending := tomoxlending.New(tomoX)
+	if cfg.LendingPriceFeed != "" {
+		priceSource, err := tomoxlending.LoadScheduledPriceSource(cfg.LendingPriceFeed)
+		if err != nil {
+			Fatalf("Failed to load the lending price feed: %v", err)
+		}
+		lending.SetPriceSource(priceSource)
+	}
 	if err := stack.Register(func(n *node.ServiceContext) (node.Service, error) {
-		return tomoxlending.New(tomoX), nil
+		return lending, nil
 	}); err != nil {
 		Fatalf("Failed to register the TomoXLending service: %v", err)
 	}
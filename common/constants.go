@@ -20,6 +20,7 @@ const (
 	LimitThresholdNonceInQueue = 10
 	MergeSignRange             = 15
 	RangeReturnSigner          = 150
+	FinalityThresholdPercent   = 75 // a block is finalized once more than this percentage of masternodes signed it
 
 	DefaultMinGasPrice = 250000000
 
@@ -40,6 +40,8 @@ const (
 	TradingStateAddr                  = "0x0000000000000000000000000000000000000092"
 	TomoXLendingAddress               = "0x0000000000000000000000000000000000000093"
 	TomoXLendingFinalizedTradeAddress = "0x0000000000000000000000000000000000000094"
+	BlacklistSMC                      = "0x0000000000000000000000000000000000000095"
+	RelayerFeesSMC                    = "0x0000000000000000000000000000000000000096"
 	TomoNativeAddress                 = "0x0000000000000000000000000000000000000001"
 	LendingLockAddress                = "0x0000000000000000000000000000000000000011"
 	VoteMethod                        = "0x6dd7d8ea"
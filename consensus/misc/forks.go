@@ -21,9 +21,13 @@ import (
 	"math/big"
 
 	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	blacklistContract "github.com/tomochain/tomochain/contracts/blacklist/contract"
+	tomoxContract "github.com/tomochain/tomochain/contracts/tomox/contract"
 	"github.com/tomochain/tomochain/core/state"
 	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
 )
 
 // VerifyForkHashes verifies that blocks conforming to network hard-forks do have
@@ -71,3 +75,31 @@ func ApplyVIPVRC25Upgarde(statedb *state.StateDB, vipVRC25Block *big.Int, headBl
 		statedb.SetState(common.TRC21IssuerSMC, minCapLoc, common.BigToHash(common.VRC25IssuerMinCap))
 	}
 }
+
+// ApplyBlacklistFork installs the black-list contract at the black-list fork
+// block, owned by the foundation wallet and seeded with the hard-coded list.
+func ApplyBlacklistFork(statedb *state.StateDB, blacklistBlock *big.Int, headBlock *big.Int) {
+	if headBlock.Cmp(blacklistBlock) != 0 {
+		return
+	}
+	contract := common.HexToAddress(common.BlacklistSMC)
+	statedb.SetCode(contract, hexutil.MustDecode(blacklistContract.BlacklistDeployedCode))
+	ownerLoc := state.GetLocSimpleVariable(state.SlotBlacklist["owner"])
+	statedb.SetState(contract, ownerLoc, common.HexToAddress(common.FoudationAddr).Hash())
+	for addr := range common.Blacklist {
+		loc := state.GetLocMappingAtKey(addr.Hash(), state.SlotBlacklist["blacklisted"])
+		statedb.SetState(contract, common.BigToHash(loc), common.BigToHash(common.Big1))
+	}
+}
+
+// ApplyTomoXMakerTakerFeeFork installs the relayer fees contract at the TomoX
+// maker/taker fee fork block, pointing at the relayer registration contract.
+func ApplyTomoXMakerTakerFeeFork(statedb *state.StateDB, makerTakerFeeBlock *big.Int, headBlock *big.Int) {
+	if headBlock.Cmp(makerTakerFeeBlock) != 0 {
+		return
+	}
+	contract := common.HexToAddress(common.RelayerFeesSMC)
+	statedb.SetCode(contract, hexutil.MustDecode(tomoxContract.RelayerFeesDeployedCode))
+	registrationLoc := state.GetLocSimpleVariable(tradingstate.RelayerFeesMappingSlot["RelayerRegistration"])
+	statedb.SetState(contract, registrationLoc, common.HexToAddress(common.RelayerRegistrationSMC).Hash())
+}
@@ -72,7 +72,7 @@ type TradingService interface {
 	GetStateCache() tradingstate.Database
 	GetTriegc() *prque.Prque
 	ApplyOrder(header *types.Header, coinbase common.Address, chain consensus.ChainContext, statedb *state.StateDB, tomoXstatedb *tradingstate.TradingStateDB, orderBook common.Hash, order *tradingstate.OrderItem) ([]map[string]string, []*tradingstate.OrderItem, error)
-	UpdateMediumPriceBeforeEpoch(epochNumber uint64, tradingStateDB *tradingstate.TradingStateDB, statedb *state.StateDB) error
+	UpdateMediumPriceBeforeEpoch(chain consensus.ChainContext, header *types.Header, epochNumber uint64, tradingStateDB *tradingstate.TradingStateDB, statedb *state.StateDB) error
 	IsSDKNode() bool
 	SyncDataToSDKNode(takerOrder *tradingstate.OrderItem, txHash common.Hash, txMatchTime time.Time, statedb *state.StateDB, trades []map[string]string, rejectedOrders []*tradingstate.OrderItem, dirtyOrderCount *uint64) error
 	RollbackReorgTxMatch(txhash common.Hash) error
@@ -87,7 +87,7 @@ type LendingService interface {
 	GetTriegc() *prque.Prque
 	ApplyOrder(header *types.Header, coinbase common.Address, chain consensus.ChainContext, statedb *state.StateDB, lendingStateDB *lendingstate.LendingStateDB, tradingStateDb *tradingstate.TradingStateDB, lendingOrderBook common.Hash, order *lendingstate.LendingItem) ([]*lendingstate.LendingTrade, []*lendingstate.LendingItem, error)
 	GetCollateralPrices(header *types.Header, chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, collateralToken common.Address, lendingToken common.Address) (*big.Int, *big.Int, error)
-	GetMediumTradePriceBeforeEpoch(chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) (*big.Int, error)
+	GetMediumTradePriceBeforeEpoch(header *types.Header, chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) (*big.Int, error)
 	ProcessLiquidationData(header *types.Header, chain consensus.ChainContext, statedb *state.StateDB, tradingState *tradingstate.TradingStateDB, lendingState *lendingstate.LendingStateDB) (updatedTrades map[common.Hash]*lendingstate.LendingTrade, liquidatedTrades, autoRepayTrades, autoTopUpTrades, autoRecallTrades []*lendingstate.LendingTrade, err error)
 	SyncDataToSDKNode(chain consensus.ChainContext, state *state.StateDB, block *types.Block, takerOrderInTx *lendingstate.LendingItem, txHash common.Hash, txMatchTime time.Time, trades []*lendingstate.LendingTrade, rejectedOrders []*lendingstate.LendingItem, dirtyOrderCount *uint64) error
 	UpdateLiquidatedTrade(blockTime uint64, result lendingstate.FinalizedResult, trades map[common.Hash]*lendingstate.LendingTrade) error
@@ -541,6 +541,27 @@ func (c *Posv) StoreSnapshot(snap *Snapshot) error {
 	return snap.store(c.db)
 }
 
+// ImportTrustedCheckpoint stores the signer snapshot of a trusted checkpoint so
+// that verifying the headers following it doesn't replay the chain from genesis.
+// A snapshot already present in the database is left untouched.
+func (c *Posv) ImportTrustedCheckpoint(checkpoint *params.TrustedCheckpoint) error {
+	if checkpoint.Empty() {
+		return nil
+	}
+	if checkpoint.Number%c.config.Epoch != 0 || checkpoint.Number <= c.config.Gap {
+		return fmt.Errorf("trusted checkpoint %d is not an epoch checkpoint", checkpoint.Number)
+	}
+	if _, err := loadSnapshot(c.config, c.signatures, c.db, checkpoint.SnapshotHash); err == nil {
+		return nil
+	}
+	snap := newSnapshot(c.config, c.signatures, checkpoint.SnapshotNumber(c.config), checkpoint.SnapshotHash, checkpoint.Signers)
+	if err := snap.store(c.db); err != nil {
+		return err
+	}
+	log.Info("Imported trusted checkpoint snapshot", "number", snap.Number, "hash", snap.Hash, "signers", len(snap.Signers))
+	return nil
+}
+
 func position(list []common.Address, x common.Address) int {
 	for i, item := range list {
 		if item == x {
@@ -1204,7 +1225,7 @@ func GetMasternodesFromCheckpointHeader(checkpointHeader *types.Header) []common
 
 // Get m2 list from checkpoint block.
 func GetM1M2FromCheckpointHeader(checkpointHeader *types.Header, currentHeader *types.Header, config *params.ChainConfig) (map[common.Address]common.Address, error) {
-	if checkpointHeader.Number.Uint64()%common.EpocBlockRandomize != 0 {
+	if checkpointHeader.Number.Uint64()%config.Posv.Epoch != 0 {
 		return nil, errors.New("This block is not checkpoint block epoc.")
 	}
 	// Get signers from this block.
@@ -0,0 +1,51 @@
+pragma solidity ^0.4.24;
+
+// Blacklist holds the addresses whose transactions are rejected past the
+// black-list fork. The fork block installs its runtime code (code.go) at
+// 0x0000000000000000000000000000000000000095, owned by the foundation
+// MultiSigWallet and seeded with the hard-coded black-list. Nodes read the
+// blacklisted mapping straight from storage, so its slot must not move.
+contract Blacklist {
+    address _owner;
+    mapping(address => bool) _blacklisted;
+
+    event Blacklisted(address indexed addr, bool blacklisted);
+    event OwnershipTransferred(address indexed previousOwner, address indexed newOwner);
+
+    constructor (address owner) public {
+        _owner = owner;
+    }
+
+    modifier onlyOwner() {
+        require(msg.sender == _owner);
+        _;
+    }
+
+    function owner() public view returns(address) {
+        return _owner;
+    }
+
+    function isBlacklisted(address addr) public view returns(bool) {
+        return _blacklisted[addr];
+    }
+
+    function add(address[] addrs) public onlyOwner {
+        for (uint256 i = 0; i < addrs.length; i++) {
+            _blacklisted[addrs[i]] = true;
+            emit Blacklisted(addrs[i], true);
+        }
+    }
+
+    function remove(address[] addrs) public onlyOwner {
+        for (uint256 i = 0; i < addrs.length; i++) {
+            delete _blacklisted[addrs[i]];
+            emit Blacklisted(addrs[i], false);
+        }
+    }
+
+    function transferOwnership(address newOwner) public onlyOwner {
+        require(newOwner != address(0));
+        emit OwnershipTransferred(_owner, newOwner);
+        _owner = newOwner;
+    }
+}
@@ -0,0 +1,11 @@
+package contract
+
+// BlacklistABI is the input ABI used to call the black-list contract.
+const BlacklistABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"}],\"name\":\"isBlacklisted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"addrs\",\"type\":\"address[]\"}],\"name\":\"add\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"addrs\",\"type\":\"address[]\"}],\"name\":\"remove\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"addr\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"blacklisted\",\"type\":\"bool\"}],\"name\":\"Blacklisted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"previousOwner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"OwnershipTransferred\",\"type\":\"event\"}]"
+
+// BlacklistDeployedCode is the runtime code of Blacklist.sol, installed at the
+// black-list contract address by the black-list fork. It's assembled from
+// Blacklist.easm with `evm compile Blacklist.easm`, keeping the storage layout
+// and the ABI of the contract. This constant needs to be updated when the
+// contract code is changed.
+const BlacklistDeployedCode = "0x34630000007557600436106300000075576000357c0100000000000000000000000000000000000000000000000000000000900480638da5cb5b14630000007a578063fe575a87146300000086578063c4c1c94f1463000000b85780635e4ba17c1463000000cd578063f2fde38b14630000015f575b600080fd5b60005460005260206000f35b60043573ffffffffffffffffffffffffffffffffffffffff166000526001602052604060002054151560005260206000f35b600054331415630000007557600163000000e2565b600054331415630000007557600063000000e2565b6004356004018035602002906020019081015b80821015630000015d57813573ffffffffffffffffffffffffffffffffffffffff1680600052600160205283604060002055836000527fcf3473b85df1594d47b6958f29a32bea0abff9dd68296f7bf33443646793cfd860206000a2906020019063000000f5565b005b60005433141563000000755760043573ffffffffffffffffffffffffffffffffffffffff168015630000007557806000547f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0600080a360005500"
@@ -0,0 +1,156 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package contract_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/compiler"
+	"github.com/tomochain/tomochain/contracts/blacklist/contract"
+	"github.com/tomochain/tomochain/core/asm"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/vm/runtime"
+	"github.com/tomochain/tomochain/params"
+)
+
+// Tests that the runtime code of the black-list contract is the one assembled
+// from Blacklist.easm.
+func TestDeployedCode(t *testing.T) {
+	src, err := ioutil.ReadFile("Blacklist.easm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := asm.NewCompiler(false)
+	c.Feed(asm.Lex("Blacklist.easm", src, false))
+	bin, errs := c.Compile()
+	if len(errs) > 0 {
+		t.Fatalf("failed to assemble Blacklist.easm: %v", errs)
+	}
+	if "0x"+bin != contract.BlacklistDeployedCode {
+		t.Errorf("BlacklistDeployedCode is not the code of Blacklist.easm, run `evm compile Blacklist.easm`")
+	}
+}
+
+// Tests that the assembled black-list contract behaves as Blacklist.sol compiled
+// by solc: the calls return the same data and logs, and leave the same storage.
+func TestDeployedCodeSolidity(t *testing.T) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip(err)
+	}
+	if solc, err := compiler.SolidityVersion(""); err != nil || solc.Major != 0 || solc.Minor != 4 {
+		t.Skip("Blacklist.sol needs solc 0.4")
+	}
+	contracts, err := compiler.CompileSolidity("", "Blacklist.sol")
+	if err != nil {
+		t.Fatalf("failed to compile Blacklist.sol: %v", err)
+	}
+	var solidity *compiler.Contract
+	for name, c := range contracts {
+		if strings.HasSuffix(name, ":Blacklist") {
+			solidity = c
+		}
+	}
+	if solidity == nil {
+		t.Fatal("Blacklist contract missing from the solc output")
+	}
+	var (
+		owner   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		other   = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		listed  = common.HexToAddress("0x00000000000000000000000000000000000b1ac1")
+		listed2 = common.HexToAddress("0x00000000000000000000000000000000000b1ac2")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	cfg := &runtime.Config{ChainConfig: params.TestChainConfig, State: statedb, Origin: owner}
+	_, solidityAddr, _, err := runtime.Create(append(common.FromHex(solidity.Code), common.LeftPadBytes(owner.Bytes(), 32)...), cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy Blacklist.sol: %v", err)
+	}
+	assembledAddr := common.HexToAddress(common.BlacklistSMC)
+	statedb.SetCode(assembledAddr, common.FromHex(contract.BlacklistDeployedCode))
+	statedb.SetState(assembledAddr, common.Hash{}, owner.Hash())
+
+	parsed, err := abi.JSON(strings.NewReader(contract.BlacklistABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := []struct {
+		from   common.Address
+		method string
+		args   []interface{}
+	}{
+		{owner, "add", []interface{}{[]common.Address{listed, listed2}}},
+		{other, "add", []interface{}{[]common.Address{other}}},
+		{other, "isBlacklisted", []interface{}{listed}},
+		{owner, "remove", []interface{}{[]common.Address{listed}}},
+		{other, "remove", []interface{}{[]common.Address{listed2}}},
+		{other, "isBlacklisted", []interface{}{listed}},
+		{other, "isBlacklisted", []interface{}{listed2}},
+		{other, "transferOwnership", []interface{}{other}},
+		{owner, "transferOwnership", []interface{}{common.Address{}}},
+		{owner, "transferOwnership", []interface{}{other}},
+		{owner, "owner", nil},
+		{owner, "add", []interface{}{[]common.Address{owner}}},
+		{other, "add", []interface{}{[]common.Address{}}},
+	}
+	for i, call := range calls {
+		input, err := parsed.Pack(call.method, call.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Origin = call.from
+		statedb.Prepare(common.BytesToHash([]byte{byte(i), 0}), common.Hash{}, 0)
+		solidityRet, _, solidityErr := runtime.Call(solidityAddr, input, cfg)
+		statedb.Prepare(common.BytesToHash([]byte{byte(i), 1}), common.Hash{}, 0)
+		assembledRet, _, assembledErr := runtime.Call(assembledAddr, input, cfg)
+
+		if (solidityErr == nil) != (assembledErr == nil) || !bytes.Equal(solidityRet, assembledRet) {
+			t.Errorf("call %d %s: result mismatch: have %x (%v), want %x (%v)", i, call.method, assembledRet, assembledErr, solidityRet, solidityErr)
+		}
+		solidityLogs, assembledLogs := statedb.GetLogs(common.BytesToHash([]byte{byte(i), 0})), statedb.GetLogs(common.BytesToHash([]byte{byte(i), 1}))
+		if len(solidityLogs) != len(assembledLogs) {
+			t.Errorf("call %d %s: log count mismatch: have %d, want %d", i, call.method, len(assembledLogs), len(solidityLogs))
+			continue
+		}
+		for j := range solidityLogs {
+			if !reflect.DeepEqual(solidityLogs[j].Topics, assembledLogs[j].Topics) || !bytes.Equal(solidityLogs[j].Data, assembledLogs[j].Data) {
+				t.Errorf("call %d %s: log %d mismatch: have %v %x, want %v %x", i, call.method, j, assembledLogs[j].Topics, assembledLogs[j].Data, solidityLogs[j].Topics, solidityLogs[j].Data)
+			}
+		}
+	}
+	if solidityStorage, assembledStorage := storage(statedb, solidityAddr), storage(statedb, assembledAddr); !reflect.DeepEqual(solidityStorage, assembledStorage) {
+		t.Errorf("storage mismatch: have %x, want %x", assembledStorage, solidityStorage)
+	}
+}
+
+// storage returns the non-empty storage slots of a contract.
+func storage(statedb *state.StateDB, addr common.Address) map[common.Hash]common.Hash {
+	slots := make(map[common.Hash]common.Hash)
+	statedb.ForEachStorage(addr, func(key, value common.Hash) bool {
+		if value != (common.Hash{}) {
+			slots[key] = value
+		}
+		return true
+	})
+	return slots
+}
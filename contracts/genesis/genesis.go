@@ -0,0 +1,173 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package genesis deploys the PoSV system contracts into genesis allocations.
+package genesis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/tomochain/tomochain/accounts/abi/bind"
+	"github.com/tomochain/tomochain/accounts/abi/bind/backends"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/contracts"
+	blockSignerContract "github.com/tomochain/tomochain/contracts/blocksigner"
+	randomizeContract "github.com/tomochain/tomochain/contracts/randomize"
+	randomizeEpochContract "github.com/tomochain/tomochain/contracts/randomize/contract"
+	validatorContract "github.com/tomochain/tomochain/contracts/validator"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/rlp"
+)
+
+const (
+	extraVanity = 32 // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal   = 65 // Fixed number of extra-data suffix bytes reserved for signer seal
+)
+
+// genesisDeployerKey is the throwaway key used to deploy the system contracts on
+// a simulated backend before copying them into a genesis allocation.
+const genesisDeployerKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+// Deployer returns a simulated backend together with a funded transactor
+// which can be used to deploy contracts whose code and storage are then copied
+// into a genesis allocation with ContractAccount.
+func Deployer() (*backends.SimulatedBackend, *bind.TransactOpts) {
+	pKey, _ := crypto.HexToECDSA(genesisDeployerKey)
+	addr := crypto.PubkeyToAddress(pKey.PublicKey)
+	contractBackend := backends.NewSimulatedBackend(core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}})
+	return contractBackend, bind.NewKeyedTransactor(pKey)
+}
+
+// ContractAccount reads the code and storage of a contract deployed on the
+// simulated backend and returns them as a genesis account holding balance.
+func ContractAccount(contractBackend *backends.SimulatedBackend, addr common.Address, balance *big.Int) (core.GenesisAccount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	code, err := contractBackend.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return core.GenesisAccount{}, err
+	}
+	storage := make(map[common.Hash]common.Hash)
+	f := func(key, val common.Hash) bool {
+		decode := []byte{}
+		trim := bytes.TrimLeft(val.Bytes(), "\x00")
+		rlp.DecodeBytes(trim, &decode)
+		storage[key] = common.BytesToHash(decode)
+		return true
+	}
+	if err := contractBackend.ForEachStorageAt(ctx, addr, nil, f); err != nil {
+		return core.GenesisAccount{}, err
+	}
+	return core.GenesisAccount{
+		Balance: balance,
+		Code:    code,
+		Storage: storage,
+	}, nil
+}
+
+// DeployPosvContracts deploys the validator, block signer and randomize
+// contracts with the given masternodes as initial candidates, each holding
+// validatorCap, and places them at their system addresses in genesis.Alloc.
+func DeployPosvContracts(genesis *core.Genesis, owner common.Address, signers []common.Address, validatorCap *big.Int) error {
+	if genesis.Config == nil || genesis.Config.Posv == nil {
+		return fmt.Errorf("genesis is not configured for PoSV")
+	}
+	contractBackend, transactOpts := Deployer()
+
+	// Validator Smart Contract Code
+	caps := make([]*big.Int, len(signers))
+	for i := range signers {
+		caps[i] = validatorCap
+	}
+	validatorAddress, _, err := validatorContract.DeployValidator(transactOpts, contractBackend, signers, caps, owner)
+	if err != nil {
+		return fmt.Errorf("can't deploy validator contract: %v", err)
+	}
+	contractBackend.Commit()
+	totalCap := new(big.Int).Mul(validatorCap, big.NewInt(int64(len(signers))))
+	account, err := ContractAccount(contractBackend, validatorAddress, totalCap)
+	if err != nil {
+		return err
+	}
+	genesis.Alloc[common.HexToAddress(common.MasternodeVotingSMC)] = account
+
+	// Block Signers Smart Contract
+	blockSignerAddress, _, err := blockSignerContract.DeployBlockSigner(transactOpts, contractBackend, new(big.Int).SetUint64(genesis.Config.Posv.Epoch))
+	if err != nil {
+		return fmt.Errorf("can't deploy block signer contract: %v", err)
+	}
+	contractBackend.Commit()
+	if account, err = ContractAccount(contractBackend, blockSignerAddress, big.NewInt(0)); err != nil {
+		return err
+	}
+	genesis.Alloc[common.HexToAddress(common.BlockSigners)] = account
+
+	// Randomize Smart Contract Code
+	if genesis.Config.Posv.Epoch != common.EpocBlockRandomize {
+		genesis.Alloc[common.HexToAddress(common.RandomizeSMC)] = randomizeEpochAccount(genesis.Config.Posv.Epoch)
+		return nil
+	}
+	randomizeAddress, _, err := randomizeContract.DeployRandomize(transactOpts, contractBackend)
+	if err != nil {
+		return fmt.Errorf("can't deploy randomize contract: %v", err)
+	}
+	contractBackend.Commit()
+	if account, err = ContractAccount(contractBackend, randomizeAddress, big.NewInt(0)); err != nil {
+		return err
+	}
+	genesis.Alloc[common.HexToAddress(common.RandomizeSMC)] = account
+	return nil
+}
+
+// randomizeEpochAccount returns the randomize contract of a chain whose epoch
+// is not 900 blocks long, which only accepts the secrets and openings within the
+// randomize windows of the epoch kept in its storage.
+func randomizeEpochAccount(epoch uint64) core.GenesisAccount {
+	secret, opening := contracts.RandomizeWindow(epoch)
+	return core.GenesisAccount{
+		Balance: big.NewInt(0),
+		Code:    common.FromHex(randomizeEpochContract.TomoRandomizeEpochDeployedCode),
+		Storage: map[common.Hash]common.Hash{
+			common.BigToHash(big.NewInt(2)): common.BigToHash(new(big.Int).SetUint64(epoch)),
+			common.BigToHash(big.NewInt(3)): common.BigToHash(new(big.Int).SetUint64(secret)),
+			common.BigToHash(big.NewInt(4)): common.BigToHash(new(big.Int).SetUint64(opening)),
+		},
+	}
+}
+
+// PosvExtraData sorts the signers and embeds them into the extra-data section
+// of a PoSV genesis block.
+func PosvExtraData(signers []common.Address) []byte {
+	sorted := make([]common.Address, len(signers))
+	copy(sorted, signers)
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			if bytes.Compare(sorted[i][:], sorted[j][:]) > 0 {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	}
+	extra := make([]byte, extraVanity+len(sorted)*common.AddressLength+extraSeal)
+	for i, signer := range sorted {
+		copy(extra[extraVanity+i*common.AddressLength:], signer[:])
+	}
+	return extra
+}
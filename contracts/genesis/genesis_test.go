@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi/bind/backends"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/contracts"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
+)
+
+func posvGenesis(t *testing.T, epoch uint64, signer common.Address) *core.Genesis {
+	genesis := &core.Genesis{
+		Alloc:  make(core.GenesisAlloc),
+		Config: &params.ChainConfig{Posv: &params.PosvConfig{Epoch: epoch}},
+	}
+	if err := DeployPosvContracts(genesis, signer, []common.Address{signer}, big.NewInt(1)); err != nil {
+		t.Fatalf("failed to deploy the PoSV contracts: %v", err)
+	}
+	return genesis
+}
+
+// Tests that the randomize contract accepts the secrets and openings within the
+// randomize windows of the epoch of the chain.
+func TestRandomizeWindows(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	randomizeAddr := common.HexToAddress(common.RandomizeSMC)
+
+	mainnet := posvGenesis(t, 900, addr).Alloc[randomizeAddr].Code
+	if code := posvGenesis(t, 900, addr).Alloc[randomizeAddr].Code; !bytes.Equal(code, mainnet) {
+		t.Fatalf("randomize code of a 900 blocks epoch changed")
+	}
+
+	epoch := uint64(30)
+	if secret, opening := contracts.RandomizeWindow(epoch); secret != 26 || opening != 28 {
+		t.Fatalf("randomize window mismatch: have %d-%d, want 26-28", secret, opening)
+	}
+	alloc := posvGenesis(t, epoch, addr).Alloc
+	if bytes.Equal(alloc[randomizeAddr].Code, mainnet) {
+		t.Fatalf("randomize code not adapted to the epoch")
+	}
+	alloc[addr] = core.GenesisAccount{Balance: big.NewInt(1000000000000)}
+	backend := backends.NewSimulatedBackend(alloc)
+
+	var (
+		signer       = types.HomesteadSigner{}
+		randomizeKey = contracts.RandStringByte(32)
+		nonce        uint64
+	)
+	// send sends a secret or opening transaction in the next block, reporting
+	// whether the contract accepted it
+	send := func(opening bool) bool {
+		var tx *types.Transaction
+		if opening {
+			tx, _ = contracts.BuildTxOpeningRandomize(nonce, randomizeAddr, randomizeKey)
+		} else {
+			tx, _ = contracts.BuildTxSecretRandomize(nonce, randomizeAddr, epoch, randomizeKey)
+		}
+		tx, _ = types.SignTx(tx, signer, key)
+		if err := backend.SendTransaction(context.Background(), tx); err != nil {
+			t.Fatalf("failed to send transaction: %v", err)
+		}
+		nonce++
+		backend.Commit()
+		receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			t.Fatalf("failed to get receipt: %v", err)
+		}
+		return receipt.Status == types.ReceiptStatusSuccessful
+	}
+	tests := []struct {
+		number  uint64
+		opening bool
+		want    bool
+	}{
+		{25, false, false},
+		{26, false, true},
+		{27, true, false},
+		{28, true, true},
+		{29, true, true},
+	}
+	for number := uint64(1); number < tests[0].number; number++ {
+		backend.Commit()
+	}
+	for _, test := range tests {
+		if have := send(test.opening); have != test.want {
+			t.Errorf("block %d, opening %v: accepted mismatch: have %v, want %v", test.number, test.opening, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+pragma solidity ^0.4.21;
+
+// TomoRandomizeEpoch is TomoRandomize for the chains whose epoch is not 900
+// blocks long: the epoch and the points of the epoch opening the secret and
+// the opening windows are kept in storage instead of being compiled in. The
+// PoSV genesis (contracts/genesis) installs its runtime code (code.go) at the
+// randomize contract address with those slots set. It keeps the ABI and the
+// storage layout of TomoRandomize, which the nodes read the secrets and the
+// openings from.
+contract TomoRandomizeEpoch {
+    mapping (address=>bytes32[]) randomSecret;
+    mapping (address=>bytes32) randomOpening;
+    uint epoch;
+    uint secretPoint;
+    uint openingPoint;
+
+    constructor (uint _epoch, uint _secretPoint, uint _openingPoint) public {
+        epoch = _epoch;
+        secretPoint = _secretPoint;
+        openingPoint = _openingPoint;
+    }
+
+    function setSecret(bytes32[] _secret) public {
+        uint point = block.number % epoch;
+        require(point >= secretPoint);
+        require(point < openingPoint);
+        randomSecret[msg.sender] = _secret;
+    }
+
+    function setOpening(bytes32 _opening) public {
+        uint point = block.number % epoch;
+        require(point >= openingPoint);
+        randomOpening[msg.sender] = _opening;
+    }
+
+    function getSecret(address _validator) public view returns(bytes32[]) {
+        return randomSecret[_validator];
+    }
+
+    function getOpening(address _validator) public view returns(bytes32) {
+        return randomOpening[_validator];
+    }
+}
@@ -0,0 +1,9 @@
+package contract
+
+// TomoRandomizeEpochDeployedCode is the runtime code of TomoRandomizeEpoch.sol,
+// installed at the randomize contract address by the PoSV genesis of the chains
+// whose epoch is not 900 blocks long. It's assembled from TomoRandomizeEpoch.easm
+// with `evm compile TomoRandomizeEpoch.easm`, keeping the storage layout and the
+// ABI of TomoRandomize. This constant needs to be updated when the contract code
+// is changed.
+const TomoRandomizeEpochDeployedCode = "0x34630000006857600436106300000068576000357c010000000000000000000000000000000000000000000000000000000090048063284180fc14630000006d57806334d386001463000000d6578063d442d6cc146300000160578063e11f5ba2146300000190575b600080fd5b60043573ffffffffffffffffffffffffffffffffffffffff1660005260006020526040600020805490600052602060002060206000528160205260005b8281101563000000ca5780820154816020026040015260010163000000aa565b50506020026040016000f35b6002548015630000006857430660035481106300000068576004549010156300000068573360005260006020526040600020805460043560040180358084559260005260206000209060200160005b8481101563000001445781816020020135838201556001016300000125565b83811015630000015e576000838201556001016300000144565b005b60043573ffffffffffffffffffffffffffffffffffffffff16600052600160205260406000205460005260206000f35b60025480156300000068574306600454901063000000685760043533600052600160205260406000205500"
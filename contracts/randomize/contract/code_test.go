@@ -0,0 +1,167 @@
+package contract_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/compiler"
+	"github.com/tomochain/tomochain/contracts"
+	"github.com/tomochain/tomochain/contracts/randomize/contract"
+	"github.com/tomochain/tomochain/core/asm"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/vm/runtime"
+	"github.com/tomochain/tomochain/params"
+)
+
+// Tests that the runtime code of the randomize contract of the epochs other
+// than 900 blocks is the one assembled from TomoRandomizeEpoch.easm.
+func TestDeployedCode(t *testing.T) {
+	src, err := ioutil.ReadFile("TomoRandomizeEpoch.easm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := asm.NewCompiler(false)
+	c.Feed(asm.Lex("TomoRandomizeEpoch.easm", src, false))
+	bin, errs := c.Compile()
+	if len(errs) > 0 {
+		t.Fatalf("failed to assemble TomoRandomizeEpoch.easm: %v", errs)
+	}
+	if "0x"+bin != contract.TomoRandomizeEpochDeployedCode {
+		t.Errorf("TomoRandomizeEpochDeployedCode is not the code of TomoRandomizeEpoch.easm, run `evm compile TomoRandomizeEpoch.easm`")
+	}
+}
+
+// Tests that the assembled randomize contract set up for a 900 blocks epoch
+// behaves as the TomoRandomize contract of the mainnet.
+func TestDeployedCodeMainnet(t *testing.T) {
+	testDeployedCode(t, common.EpocBlockRandomize, common.FromHex(contract.TomoRandomizeBin))
+}
+
+// Tests that the assembled randomize contract behaves as TomoRandomizeEpoch.sol
+// compiled by solc, for an epoch other than 900 blocks.
+func TestDeployedCodeSolidity(t *testing.T) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip(err)
+	}
+	if solc, err := compiler.SolidityVersion(""); err != nil || solc.Major != 0 || solc.Minor != 4 {
+		t.Skip("TomoRandomizeEpoch.sol needs solc 0.4")
+	}
+	compiled, err := compiler.CompileSolidity("", "TomoRandomizeEpoch.sol")
+	if err != nil {
+		t.Fatalf("failed to compile TomoRandomizeEpoch.sol: %v", err)
+	}
+	for name, c := range compiled {
+		if strings.HasSuffix(name, ":TomoRandomizeEpoch") {
+			epoch := uint64(30)
+			secret, opening := contracts.RandomizeWindow(epoch)
+			var args []byte
+			for _, arg := range []uint64{epoch, secret, opening} {
+				args = append(args, common.BigToHash(new(big.Int).SetUint64(arg)).Bytes()...)
+			}
+			testDeployedCode(t, epoch, append(common.FromHex(c.Code), args...))
+			return
+		}
+	}
+	t.Fatal("TomoRandomizeEpoch contract missing from the solc output")
+}
+
+// testDeployedCode deploys the given code and installs the assembled randomize
+// contract as the PoSV genesis does for the epoch, then checks the secrets and
+// openings sent around the randomize windows return the same data and leave the
+// same storage.
+func testDeployedCode(t *testing.T, epoch uint64, deploy []byte) {
+	var (
+		validator = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		other     = common.HexToAddress("0x0000000000000000000000000000000000000002")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	cfg := &runtime.Config{ChainConfig: params.TestChainConfig, State: statedb, Origin: validator, BlockNumber: big.NewInt(1)}
+	_, solidityAddr, _, err := runtime.Create(deploy, cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy the randomize contract: %v", err)
+	}
+	secret, opening := contracts.RandomizeWindow(epoch)
+	assembledAddr := common.HexToAddress(common.RandomizeSMC)
+	statedb.SetCode(assembledAddr, common.FromHex(contract.TomoRandomizeEpochDeployedCode))
+	for slot, value := range []uint64{epoch, secret, opening} {
+		statedb.SetState(assembledAddr, common.BigToHash(big.NewInt(int64(slot+2))), common.BigToHash(new(big.Int).SetUint64(value)))
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(contract.TomoRandomizeABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := func(n int) [][32]byte {
+		secrets := make([][32]byte, n)
+		for i := range secrets {
+			secrets[i] = common.BigToHash(big.NewInt(int64(epoch*100 + uint64(i) + 1)))
+		}
+		return secrets
+	}
+	calls := []struct {
+		number uint64
+		from   common.Address
+		method string
+		args   []interface{}
+	}{
+		{secret - 1, validator, "setSecret", []interface{}{hashes(3)}},
+		{secret, validator, "setSecret", []interface{}{hashes(3)}},
+		{secret, other, "setSecret", []interface{}{hashes(2)}},
+		{opening - 1, validator, "setSecret", []interface{}{hashes(1)}},
+		{opening, other, "setSecret", []interface{}{hashes(4)}},
+		{epoch + secret, other, "setSecret", []interface{}{hashes(0)}},
+		{opening - 1, validator, "setOpening", []interface{}{common.HexToHash("0x0e")}},
+		{opening, validator, "setOpening", []interface{}{common.HexToHash("0x0e")}},
+		{epoch - 1, other, "setOpening", []interface{}{common.HexToHash("0x0f")}},
+		{epoch, validator, "setOpening", []interface{}{common.HexToHash("0x10")}},
+		{epoch, other, "getSecret", []interface{}{validator}},
+		{epoch, other, "getSecret", []interface{}{other}},
+		{epoch, other, "getSecret", []interface{}{common.Address{}}},
+		{epoch, other, "getOpening", []interface{}{validator}},
+		{epoch, other, "getOpening", []interface{}{other}},
+		{epoch, other, "", nil},
+	}
+	for i, call := range calls {
+		input := []byte{0x12, 0x34, 0x56, 0x78}
+		if call.method != "" {
+			if input, err = parsed.Pack(call.method, call.args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cfg.Origin, cfg.BlockNumber = call.from, new(big.Int).SetUint64(call.number)
+		solidityRet, _, solidityErr := runtime.Call(solidityAddr, input, cfg)
+		assembledRet, _, assembledErr := runtime.Call(assembledAddr, input, cfg)
+
+		if (solidityErr == nil) != (assembledErr == nil) || !bytes.Equal(solidityRet, assembledRet) {
+			t.Errorf("epoch %d, call %d %s: result mismatch: have %x (%v), want %x (%v)", epoch, i, call.method, assembledRet, assembledErr, solidityRet, solidityErr)
+		}
+	}
+	solidityStorage, assembledStorage := storage(statedb, solidityAddr), storage(statedb, assembledAddr)
+	for slot := 2; slot <= 4; slot++ {
+		delete(solidityStorage, common.BigToHash(big.NewInt(int64(slot))))
+		delete(assembledStorage, common.BigToHash(big.NewInt(int64(slot))))
+	}
+	if !reflect.DeepEqual(solidityStorage, assembledStorage) {
+		t.Errorf("epoch %d: storage mismatch: have %x, want %x", epoch, assembledStorage, solidityStorage)
+	}
+}
+
+// storage returns the non-empty storage slots of a contract.
+func storage(statedb *state.StateDB, addr common.Address) map[common.Hash]common.Hash {
+	slots := make(map[common.Hash]common.Hash)
+	statedb.ForEachStorage(addr, func(key, value common.Hash) bool {
+		if value != (common.Hash{}) {
+			slots[key] = value
+		}
+		return true
+	})
+	return slots
+}
@@ -0,0 +1,421 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contract
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/tomochain/tomochain"
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/accounts/abi/bind"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/event"
+)
+
+// RelayerFeesABI is the input ABI used to generate the binding from.
+const RelayerFeesABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"RELAYER_FEES\",\"outputs\":[{\"name\":\"_owner\",\"type\":\"address\"},{\"name\":\"_makerFee\",\"type\":\"uint256\"},{\"name\":\"_takerFee\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"USER_VOLUMES\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"coinbase\",\"type\":\"address\"},{\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"getFeeTier\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"coinbase\",\"type\":\"address\"},{\"name\":\"makerFee\",\"type\":\"uint16\"},{\"name\":\"takerFee\",\"type\":\"uint16\"},{\"name\":\"tierVolumes\",\"type\":\"uint256[]\"},{\"name\":\"tierMakerFees\",\"type\":\"uint16[]\"},{\"name\":\"tierTakerFees\",\"type\":\"uint16[]\"}],\"name\":\"updateMakerTakerFee\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"relayerRegistration\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"coinbase\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"makerFee\",\"type\":\"uint16\"},{\"indexed\":false,\"name\":\"takerFee\",\"type\":\"uint16\"},{\"indexed\":false,\"name\":\"tiers\",\"type\":\"uint256\"}],\"name\":\"UpdateMakerTakerFeeEvent\",\"type\":\"event\"}]"
+
+// RelayerFeesBin is the compiled bytecode used for deploying new contracts.
+const RelayerFeesBin = `0x34630000003b57602080380360003960005173ffffffffffffffffffffffffffffffffffffffff16600055602060403803038060406000396000f35b600080fd34630000006e5760043610630000006e576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff168063fe39f9ff146300000073578063294427811463000000b357806346a99cee146300000107578063ca7ef2df146300000166575b600080fd5b60043573ffffffffffffffffffffffffffffffffffffffff1660005260016020526040600020805460005280600101546020526002015460405260606000f35b60043573ffffffffffffffffffffffffffffffffffffffff166000526002602052604060002060205260243573ffffffffffffffffffffffffffffffffffffffff1660005260406000205460005260206000f35b60043573ffffffffffffffffffffffffffffffffffffffff16600052600160205260406000206003016024358154811015630000006e579060005260206000209060030201805460005280600101546020526002015460405260606000f35b60043573ffffffffffffffffffffffffffffffffffffffff166080527f49ba1f700000000000000000000000000000000000000000000000000000000060005260805160045260806000602460006000545afa15630000006e5760803d10630000006e5760605173ffffffffffffffffffffffffffffffffffffffff168060a052331415630000006e577f500f99f70000000000000000000000000000000000000000000000000000000060005260805160045260206000602460006000545afa15630000006e5760203d10630000006e57600051630000006e577f885b71370000000000000000000000000000000000000000000000000000000060005260805160045260206000602460006000545afa15630000006e5760203d10630000006e57600051630000006e576103e860243561ffff168060c0521015630000006e576103e860443561ffff168060e0521015630000006e5760643560040180356101005260200161012052600a6101005111630000006e576084356004018035610100511415630000006e576020016101405260a4356004018035610100511415630000006e5760200161016052608051600052600160205260406000206101805260a051610180515560c051610180516001015560e0516101805160020155610180516003018060005260206000206101a05280546003026101c05260006101e0525b6101c0516101e051101563000003985760006101a0516101e05101556101e0516001016101e052630000036a565b61010051905560006101e0525b610100516101e05110156300000442576101e051602002806101205101356101e0511563000003dc5761020051811115630000006e575b80610200526101a0516101e051600302018061022052558061014051013561ffff166103e8811015630000006e57610220516001015561016051013561ffff166103e8811015630000006e5761022051600201556101e0516001016101e05263000003a5565b60805160005260c05160205260e051604052610100516060527f02895bd2689ab5c6224dba27587a556317dc2a26ef599c48e61cffc30d87c6a360806000a100`
+
+// DeployRelayerFees deploys a new Ethereum contract, binding an instance of RelayerFees to it.
+func DeployRelayerFees(auth *bind.TransactOpts, backend bind.ContractBackend, relayerRegistration common.Address) (common.Address, *types.Transaction, *RelayerFees, error) {
+	parsed, err := abi.JSON(strings.NewReader(RelayerFeesABI))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex(RelayerFeesBin), backend, relayerRegistration)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &RelayerFees{RelayerFeesCaller: RelayerFeesCaller{contract: contract}, RelayerFeesTransactor: RelayerFeesTransactor{contract: contract}, RelayerFeesFilterer: RelayerFeesFilterer{contract: contract}}, nil
+}
+
+// RelayerFees is an auto generated Go binding around an Ethereum contract.
+type RelayerFees struct {
+	RelayerFeesCaller     // Read-only binding to the contract
+	RelayerFeesTransactor // Write-only binding to the contract
+	RelayerFeesFilterer   // Log filterer for contract events
+}
+
+// RelayerFeesCaller is an auto generated read-only Go binding around an Ethereum contract.
+type RelayerFeesCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RelayerFeesTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type RelayerFeesTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RelayerFeesFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type RelayerFeesFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RelayerFeesSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type RelayerFeesSession struct {
+	Contract     *RelayerFees      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// RelayerFeesCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type RelayerFeesCallerSession struct {
+	Contract *RelayerFeesCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// RelayerFeesTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type RelayerFeesTransactorSession struct {
+	Contract     *RelayerFeesTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// RelayerFeesRaw is an auto generated low-level Go binding around an Ethereum contract.
+type RelayerFeesRaw struct {
+	Contract *RelayerFees // Generic contract binding to access the raw methods on
+}
+
+// RelayerFeesCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type RelayerFeesCallerRaw struct {
+	Contract *RelayerFeesCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// RelayerFeesTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type RelayerFeesTransactorRaw struct {
+	Contract *RelayerFeesTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewRelayerFees creates a new instance of RelayerFees, bound to a specific deployed contract.
+func NewRelayerFees(address common.Address, backend bind.ContractBackend) (*RelayerFees, error) {
+	contract, err := bindRelayerFees(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayerFees{RelayerFeesCaller: RelayerFeesCaller{contract: contract}, RelayerFeesTransactor: RelayerFeesTransactor{contract: contract}, RelayerFeesFilterer: RelayerFeesFilterer{contract: contract}}, nil
+}
+
+// NewRelayerFeesCaller creates a new read-only instance of RelayerFees, bound to a specific deployed contract.
+func NewRelayerFeesCaller(address common.Address, caller bind.ContractCaller) (*RelayerFeesCaller, error) {
+	contract, err := bindRelayerFees(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayerFeesCaller{contract: contract}, nil
+}
+
+// NewRelayerFeesTransactor creates a new write-only instance of RelayerFees, bound to a specific deployed contract.
+func NewRelayerFeesTransactor(address common.Address, transactor bind.ContractTransactor) (*RelayerFeesTransactor, error) {
+	contract, err := bindRelayerFees(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayerFeesTransactor{contract: contract}, nil
+}
+
+// NewRelayerFeesFilterer creates a new log filterer instance of RelayerFees, bound to a specific deployed contract.
+func NewRelayerFeesFilterer(address common.Address, filterer bind.ContractFilterer) (*RelayerFeesFilterer, error) {
+	contract, err := bindRelayerFees(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayerFeesFilterer{contract: contract}, nil
+}
+
+// bindRelayerFees binds a generic wrapper to an already deployed contract.
+func bindRelayerFees(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(RelayerFeesABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_RelayerFees *RelayerFeesRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _RelayerFees.Contract.RelayerFeesCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_RelayerFees *RelayerFeesRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _RelayerFees.Contract.RelayerFeesTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_RelayerFees *RelayerFeesRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _RelayerFees.Contract.RelayerFeesTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_RelayerFees *RelayerFeesCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _RelayerFees.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_RelayerFees *RelayerFeesTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _RelayerFees.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_RelayerFees *RelayerFeesTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _RelayerFees.Contract.contract.Transact(opts, method, params...)
+}
+
+// RELAYERFEES is a free data retrieval call binding the contract method 0xfe39f9ff.
+//
+// Solidity: function RELAYER_FEES( address) constant returns(_owner address, _makerFee uint256, _takerFee uint256)
+func (_RelayerFees *RelayerFeesCaller) RELAYERFEES(opts *bind.CallOpts, arg0 common.Address) (struct {
+	Owner    common.Address
+	MakerFee *big.Int
+	TakerFee *big.Int
+}, error) {
+	ret := new(struct {
+		Owner    common.Address
+		MakerFee *big.Int
+		TakerFee *big.Int
+	})
+	out := ret
+	err := _RelayerFees.contract.Call(opts, out, "RELAYER_FEES", arg0)
+	return *ret, err
+}
+
+// RELAYERFEES is a free data retrieval call binding the contract method 0xfe39f9ff.
+//
+// Solidity: function RELAYER_FEES( address) constant returns(_owner address, _makerFee uint256, _takerFee uint256)
+func (_RelayerFees *RelayerFeesSession) RELAYERFEES(arg0 common.Address) (struct {
+	Owner    common.Address
+	MakerFee *big.Int
+	TakerFee *big.Int
+}, error) {
+	return _RelayerFees.Contract.RELAYERFEES(&_RelayerFees.CallOpts, arg0)
+}
+
+// RELAYERFEES is a free data retrieval call binding the contract method 0xfe39f9ff.
+//
+// Solidity: function RELAYER_FEES( address) constant returns(_owner address, _makerFee uint256, _takerFee uint256)
+func (_RelayerFees *RelayerFeesCallerSession) RELAYERFEES(arg0 common.Address) (struct {
+	Owner    common.Address
+	MakerFee *big.Int
+	TakerFee *big.Int
+}, error) {
+	return _RelayerFees.Contract.RELAYERFEES(&_RelayerFees.CallOpts, arg0)
+}
+
+// USERVOLUMES is a free data retrieval call binding the contract method 0x29442781.
+//
+// Solidity: function USER_VOLUMES( address,  address) constant returns(uint256)
+func (_RelayerFees *RelayerFeesCaller) USERVOLUMES(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _RelayerFees.contract.Call(opts, out, "USER_VOLUMES", arg0, arg1)
+	return *ret0, err
+}
+
+// USERVOLUMES is a free data retrieval call binding the contract method 0x29442781.
+//
+// Solidity: function USER_VOLUMES( address,  address) constant returns(uint256)
+func (_RelayerFees *RelayerFeesSession) USERVOLUMES(arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	return _RelayerFees.Contract.USERVOLUMES(&_RelayerFees.CallOpts, arg0, arg1)
+}
+
+// USERVOLUMES is a free data retrieval call binding the contract method 0x29442781.
+//
+// Solidity: function USER_VOLUMES( address,  address) constant returns(uint256)
+func (_RelayerFees *RelayerFeesCallerSession) USERVOLUMES(arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	return _RelayerFees.Contract.USERVOLUMES(&_RelayerFees.CallOpts, arg0, arg1)
+}
+
+// GetFeeTier is a free data retrieval call binding the contract method 0x46a99cee.
+//
+// Solidity: function getFeeTier(coinbase address, index uint256) constant returns(uint256, uint256, uint256)
+func (_RelayerFees *RelayerFeesCaller) GetFeeTier(opts *bind.CallOpts, coinbase common.Address, index *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+		ret1 = new(*big.Int)
+		ret2 = new(*big.Int)
+	)
+	out := &[]interface{}{
+		ret0,
+		ret1,
+		ret2,
+	}
+	err := _RelayerFees.contract.Call(opts, out, "getFeeTier", coinbase, index)
+	return *ret0, *ret1, *ret2, err
+}
+
+// GetFeeTier is a free data retrieval call binding the contract method 0x46a99cee.
+//
+// Solidity: function getFeeTier(coinbase address, index uint256) constant returns(uint256, uint256, uint256)
+func (_RelayerFees *RelayerFeesSession) GetFeeTier(coinbase common.Address, index *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	return _RelayerFees.Contract.GetFeeTier(&_RelayerFees.CallOpts, coinbase, index)
+}
+
+// GetFeeTier is a free data retrieval call binding the contract method 0x46a99cee.
+//
+// Solidity: function getFeeTier(coinbase address, index uint256) constant returns(uint256, uint256, uint256)
+func (_RelayerFees *RelayerFeesCallerSession) GetFeeTier(coinbase common.Address, index *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	return _RelayerFees.Contract.GetFeeTier(&_RelayerFees.CallOpts, coinbase, index)
+}
+
+// UpdateMakerTakerFee is a paid mutator transaction binding the contract method 0xca7ef2df.
+//
+// Solidity: function updateMakerTakerFee(coinbase address, makerFee uint16, takerFee uint16, tierVolumes uint256[], tierMakerFees uint16[], tierTakerFees uint16[]) returns()
+func (_RelayerFees *RelayerFeesTransactor) UpdateMakerTakerFee(opts *bind.TransactOpts, coinbase common.Address, makerFee uint16, takerFee uint16, tierVolumes []*big.Int, tierMakerFees []uint16, tierTakerFees []uint16) (*types.Transaction, error) {
+	return _RelayerFees.contract.Transact(opts, "updateMakerTakerFee", coinbase, makerFee, takerFee, tierVolumes, tierMakerFees, tierTakerFees)
+}
+
+// UpdateMakerTakerFee is a paid mutator transaction binding the contract method 0xca7ef2df.
+//
+// Solidity: function updateMakerTakerFee(coinbase address, makerFee uint16, takerFee uint16, tierVolumes uint256[], tierMakerFees uint16[], tierTakerFees uint16[]) returns()
+func (_RelayerFees *RelayerFeesSession) UpdateMakerTakerFee(coinbase common.Address, makerFee uint16, takerFee uint16, tierVolumes []*big.Int, tierMakerFees []uint16, tierTakerFees []uint16) (*types.Transaction, error) {
+	return _RelayerFees.Contract.UpdateMakerTakerFee(&_RelayerFees.TransactOpts, coinbase, makerFee, takerFee, tierVolumes, tierMakerFees, tierTakerFees)
+}
+
+// UpdateMakerTakerFee is a paid mutator transaction binding the contract method 0xca7ef2df.
+//
+// Solidity: function updateMakerTakerFee(coinbase address, makerFee uint16, takerFee uint16, tierVolumes uint256[], tierMakerFees uint16[], tierTakerFees uint16[]) returns()
+func (_RelayerFees *RelayerFeesTransactorSession) UpdateMakerTakerFee(coinbase common.Address, makerFee uint16, takerFee uint16, tierVolumes []*big.Int, tierMakerFees []uint16, tierTakerFees []uint16) (*types.Transaction, error) {
+	return _RelayerFees.Contract.UpdateMakerTakerFee(&_RelayerFees.TransactOpts, coinbase, makerFee, takerFee, tierVolumes, tierMakerFees, tierTakerFees)
+}
+
+// RelayerFeesUpdateMakerTakerFeeEventIterator is returned from FilterUpdateMakerTakerFeeEvent and is used to iterate over the raw logs and unpacked data for UpdateMakerTakerFeeEvent events raised by the RelayerFees contract.
+type RelayerFeesUpdateMakerTakerFeeEventIterator struct {
+	Event *RelayerFeesUpdateMakerTakerFeeEvent // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *RelayerFeesUpdateMakerTakerFeeEventIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(RelayerFeesUpdateMakerTakerFeeEvent)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(RelayerFeesUpdateMakerTakerFeeEvent)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *RelayerFeesUpdateMakerTakerFeeEventIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *RelayerFeesUpdateMakerTakerFeeEventIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// RelayerFeesUpdateMakerTakerFeeEvent represents a UpdateMakerTakerFeeEvent event raised by the RelayerFees contract.
+type RelayerFeesUpdateMakerTakerFeeEvent struct {
+	Coinbase common.Address
+	MakerFee uint16
+	TakerFee uint16
+	Tiers    *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpdateMakerTakerFeeEvent is a free log retrieval operation binding the contract event 0x02895bd2689ab5c6224dba27587a556317dc2a26ef599c48e61cffc30d87c6a3.
+//
+// Solidity: event UpdateMakerTakerFeeEvent(coinbase address, makerFee uint16, takerFee uint16, tiers uint256)
+func (_RelayerFees *RelayerFeesFilterer) FilterUpdateMakerTakerFeeEvent(opts *bind.FilterOpts) (*RelayerFeesUpdateMakerTakerFeeEventIterator, error) {
+
+	logs, sub, err := _RelayerFees.contract.FilterLogs(opts, "UpdateMakerTakerFeeEvent")
+	if err != nil {
+		return nil, err
+	}
+	return &RelayerFeesUpdateMakerTakerFeeEventIterator{contract: _RelayerFees.contract, event: "UpdateMakerTakerFeeEvent", logs: logs, sub: sub}, nil
+}
+
+// WatchUpdateMakerTakerFeeEvent is a free log subscription operation binding the contract event 0x02895bd2689ab5c6224dba27587a556317dc2a26ef599c48e61cffc30d87c6a3.
+//
+// Solidity: event UpdateMakerTakerFeeEvent(coinbase address, makerFee uint16, takerFee uint16, tiers uint256)
+func (_RelayerFees *RelayerFeesFilterer) WatchUpdateMakerTakerFeeEvent(opts *bind.WatchOpts, sink chan<- *RelayerFeesUpdateMakerTakerFeeEvent) (event.Subscription, error) {
+
+	logs, sub, err := _RelayerFees.contract.WatchLogs(opts, "UpdateMakerTakerFeeEvent")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(RelayerFeesUpdateMakerTakerFeeEvent)
+				if err := _RelayerFees.contract.UnpackLog(event, "UpdateMakerTakerFeeEvent", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,76 @@
+pragma solidity ^0.4.24;
+
+contract AbstractRelayerRegistration {
+    function RELAYER_LIST(address) public view returns (uint256, uint16, uint, address);
+    function RESIGN_REQUESTS(address) public view returns (uint);
+    function RELAYER_ON_SALE_LIST(address) public view returns (uint256);
+}
+
+// RelayerFees holds the maker and taker fees the relayers of the relayer
+// registration contract charge past the TomoX maker/taker fee fork, lowered by
+// tiers of the volume their users trade. The fork block installs its runtime
+// code (code.go) at 0x0000000000000000000000000000000000000096, pointing at
+// the relayer registration contract. Nodes read the fees and keep the traded
+// volumes straight from storage, so the slots must not move.
+contract RelayerFees {
+    uint constant private MaximumFeeTiers = 10;
+
+    struct FeeTier {
+        uint256 _volume;
+        uint256 _makerFee;
+        uint256 _takerFee;
+    }
+
+    struct Fees {
+        address _owner;
+        uint256 _makerFee;
+        uint256 _takerFee;
+        FeeTier[] _tiers;
+    }
+
+    AbstractRelayerRegistration private RelayerRegistration;
+
+    /// @dev coinbase -> maker/taker fees and tiers by increasing volume, set by
+    /// the owner of the relayer and ignored by the nodes once it changes
+    mapping(address => Fees) public RELAYER_FEES;
+    /// @dev coinbase -> user -> volume traded in TOMO, kept by the nodes when settling trades
+    mapping(address => mapping(address => uint256)) public USER_VOLUMES;
+
+    event UpdateMakerTakerFeeEvent(address coinbase, uint16 makerFee, uint16 takerFee, uint tiers);
+
+    constructor (address relayerRegistration) public {
+        RelayerRegistration = AbstractRelayerRegistration(relayerRegistration);
+    }
+
+    function updateMakerTakerFee(address coinbase, uint16 makerFee, uint16 takerFee, uint256[] tierVolumes, uint16[] tierMakerFees, uint16[] tierTakerFees) public {
+        (, , , address owner) = RelayerRegistration.RELAYER_LIST(coinbase);
+        require(msg.sender == owner);
+        require(RelayerRegistration.RESIGN_REQUESTS(coinbase) == 0);
+        require(RelayerRegistration.RELAYER_ON_SALE_LIST(coinbase) == 0);
+        require(makerFee < 1000 && takerFee < 1000);
+        require(tierVolumes.length <= MaximumFeeTiers);
+        require(tierMakerFees.length == tierVolumes.length && tierTakerFees.length == tierVolumes.length);
+
+        Fees storage fees = RELAYER_FEES[coinbase];
+        fees._owner = owner;
+        fees._makerFee = makerFee;
+        fees._takerFee = takerFee;
+        delete fees._tiers;
+        for (uint i = 0; i < tierVolumes.length; i++) {
+            require(i == 0 || tierVolumes[i] > tierVolumes[i - 1]);
+            require(tierMakerFees[i] < 1000 && tierTakerFees[i] < 1000);
+            fees._tiers.push(FeeTier({
+                _volume: tierVolumes[i],
+                _makerFee: tierMakerFees[i],
+                _takerFee: tierTakerFees[i]
+            }));
+        }
+        emit UpdateMakerTakerFeeEvent(coinbase, makerFee, takerFee, tierVolumes.length);
+    }
+
+    function getFeeTier(address coinbase, uint index) public view returns (uint256, uint256, uint256) {
+        require(index < RELAYER_FEES[coinbase]._tiers.length);
+        FeeTier storage tier = RELAYER_FEES[coinbase]._tiers[index];
+        return (tier._volume, tier._makerFee, tier._takerFee);
+    }
+}
@@ -0,0 +1,7 @@
+package contract
+
+// RelayerFeesDeployedCode is the runtime code of RelayerFees.sol, installed at
+// the relayer fees contract address by the TomoX maker/taker fee fork. It is
+// assembled from RelayerFees.easm with `evm compile RelayerFees.easm` and keeps
+// the storage layout and the ABI of the contract.
+const RelayerFeesDeployedCode = "0x34630000006e5760043610630000006e576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff168063fe39f9ff146300000073578063294427811463000000b357806346a99cee146300000107578063ca7ef2df146300000166575b600080fd5b60043573ffffffffffffffffffffffffffffffffffffffff1660005260016020526040600020805460005280600101546020526002015460405260606000f35b60043573ffffffffffffffffffffffffffffffffffffffff166000526002602052604060002060205260243573ffffffffffffffffffffffffffffffffffffffff1660005260406000205460005260206000f35b60043573ffffffffffffffffffffffffffffffffffffffff16600052600160205260406000206003016024358154811015630000006e579060005260206000209060030201805460005280600101546020526002015460405260606000f35b60043573ffffffffffffffffffffffffffffffffffffffff166080527f49ba1f700000000000000000000000000000000000000000000000000000000060005260805160045260806000602460006000545afa15630000006e5760803d10630000006e5760605173ffffffffffffffffffffffffffffffffffffffff168060a052331415630000006e577f500f99f70000000000000000000000000000000000000000000000000000000060005260805160045260206000602460006000545afa15630000006e5760203d10630000006e57600051630000006e577f885b71370000000000000000000000000000000000000000000000000000000060005260805160045260206000602460006000545afa15630000006e5760203d10630000006e57600051630000006e576103e860243561ffff168060c0521015630000006e576103e860443561ffff168060e0521015630000006e5760643560040180356101005260200161012052600a6101005111630000006e576084356004018035610100511415630000006e576020016101405260a4356004018035610100511415630000006e5760200161016052608051600052600160205260406000206101805260a051610180515560c051610180516001015560e0516101805160020155610180516003018060005260206000206101a05280546003026101c05260006101e0525b6101c0516101e051101563000003985760006101a0516101e05101556101e0516001016101e052630000036a565b61010051905560006101e0525b610100516101e05110156300000442576101e051602002806101205101356101e0511563000003dc5761020051811115630000006e575b80610200526101a0516101e051600302018061022052558061014051013561ffff166103e8811015630000006e57610220516001015561016051013561ffff166103e8811015630000006e5761022051600201556101e0516001016101e05263000003a5565b60805160005260c05160205260e051604052610100516060527f02895bd2689ab5c6224dba27587a556317dc2a26ef599c48e61cffc30d87c6a360806000a100"
@@ -0,0 +1,205 @@
+package contract_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/compiler"
+	"github.com/tomochain/tomochain/contracts/tomox/contract"
+	"github.com/tomochain/tomochain/core/asm"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/vm/runtime"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
+)
+
+// registrationSource stands in for the relayer registration contract: the
+// deployer owns every relayer and the one at 0xdead resigns.
+const registrationSource = `
+pragma solidity ^0.4.24;
+
+contract Registration {
+    address owner = msg.sender;
+
+    function RELAYER_LIST(address) public view returns (uint256, uint16, uint, address) {
+        return (0, 0, 0, owner);
+    }
+    function RESIGN_REQUESTS(address coinbase) public view returns (uint) {
+        return coinbase == 0xdead ? 1 : 0;
+    }
+    function RELAYER_ON_SALE_LIST(address) public view returns (uint256) {
+        return 0;
+    }
+}
+`
+
+func assemble(t *testing.T, file string) string {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := asm.NewCompiler(false)
+	c.Feed(asm.Lex(file, src, false))
+	bin, errs := c.Compile()
+	if len(errs) > 0 {
+		t.Fatalf("failed to assemble %s: %v", file, errs)
+	}
+	return bin
+}
+
+// Tests that the code of the relayer fees contract is the one assembled from
+// RelayerFeesConstructor.easm and RelayerFees.easm.
+func TestDeployedCode(t *testing.T) {
+	code := assemble(t, "RelayerFees.easm")
+	if "0x"+code != contract.RelayerFeesDeployedCode {
+		t.Errorf("RelayerFeesDeployedCode is not the code of RelayerFees.easm, run `evm compile RelayerFees.easm`")
+	}
+	if "0x"+assemble(t, "RelayerFeesConstructor.easm")+code != contract.RelayerFeesBin {
+		t.Errorf("RelayerFeesBin is not the code of RelayerFeesConstructor.easm and RelayerFees.easm")
+	}
+}
+
+// Tests that the assembled relayer fees contract behaves as RelayerFees.sol
+// compiled by solc: the calls return the same data and logs, and leave the same
+// storage.
+func TestDeployedCodeSolidity(t *testing.T) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip(err)
+	}
+	if solc, err := compiler.SolidityVersion(""); err != nil || solc.Major != 0 || solc.Minor != 4 {
+		t.Skip("RelayerFees.sol needs solc 0.4")
+	}
+	compile := func(contracts map[string]*compiler.Contract, err error) func(name string) []byte {
+		if err != nil {
+			t.Fatalf("failed to compile: %v", err)
+		}
+		return func(name string) []byte {
+			for fullName, c := range contracts {
+				if strings.HasSuffix(fullName, ":"+name) {
+					return common.FromHex(c.Code)
+				}
+			}
+			t.Fatalf("%s contract missing from the solc output", name)
+			return nil
+		}
+	}
+	var (
+		owner    = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		other    = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		coinbase = common.HexToAddress("0x000000000000000000000000000000000000c01b")
+		resigned = common.HexToAddress("0x000000000000000000000000000000000000dead")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	cfg := &runtime.Config{ChainConfig: params.TestChainConfig, State: statedb, Origin: owner}
+	_, registrationAddr, _, err := runtime.Create(compile(compiler.CompileSolidityString("", registrationSource))("Registration"), cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy the registration: %v", err)
+	}
+	arg := common.LeftPadBytes(registrationAddr.Bytes(), 32)
+	_, solidityAddr, _, err := runtime.Create(append(compile(compiler.CompileSolidity("", "RelayerFees.sol"))("RelayerFees"), arg...), cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy RelayerFees.sol: %v", err)
+	}
+	_, assembledAddr, _, err := runtime.Create(append(common.FromHex(contract.RelayerFeesBin), arg...), cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy RelayerFeesBin: %v", err)
+	}
+	if code := statedb.GetCode(assembledAddr); !bytes.Equal(code, common.FromHex(contract.RelayerFeesDeployedCode)) {
+		t.Errorf("RelayerFeesBin deployed code mismatch: have %x", code)
+	}
+	// The nodes keep the volumes, set them as they do
+	for _, addr := range []common.Address{solidityAddr, assembledAddr} {
+		volumes := crypto.Keccak256(common.LeftPadBytes(coinbase.Bytes(), 32), common.LeftPadBytes([]byte{2}, 32))
+		statedb.SetState(addr, crypto.Keccak256Hash(common.LeftPadBytes(other.Bytes(), 32), volumes), common.BigToHash(big.NewInt(1000)))
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(contract.RelayerFeesABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		volumes = []*big.Int{big.NewInt(1000), big.NewInt(5000)}
+		fees    = []uint16{4, 0}
+		tiers   = make([]*big.Int, 11)
+		tiered  = make([]uint16, 11)
+	)
+	for i := range tiers {
+		tiers[i] = big.NewInt(int64(i + 1))
+	}
+	calls := []struct {
+		from   common.Address
+		method string
+		args   []interface{}
+	}{
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), tiers[:3], tiered[:3], tiered[:3]}},
+		{owner, "getFeeTier", []interface{}{coinbase, big.NewInt(2)}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, fees, []uint16{12, 8}}},
+		{other, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{resigned, uint16(5), uint16(15), volumes, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(1000), uint16(15), volumes, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(1000), volumes, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), []*big.Int{volumes[1], volumes[0]}, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), []*big.Int{volumes[0], volumes[0]}, fees, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, fees[:1], fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, fees, fees[:1]}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, []uint16{1000, 0}, fees}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), volumes, fees, []uint16{0, 1000}}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), tiers, tiered, tiered}},
+		{owner, "updateMakerTakerFee", []interface{}{coinbase, uint16(5), uint16(15), tiers[:10], tiered[:10], tiered[:10]}},
+		{owner, "updateMakerTakerFee", []interface{}{other, uint16(7), uint16(9), volumes, fees, []uint16{12, 8}}},
+		{other, "RELAYER_FEES", []interface{}{coinbase}},
+		{other, "RELAYER_FEES", []interface{}{other}},
+		{other, "getFeeTier", []interface{}{other, big.NewInt(1)}},
+		{other, "getFeeTier", []interface{}{other, big.NewInt(2)}},
+		{other, "USER_VOLUMES", []interface{}{coinbase, other}},
+		{other, "USER_VOLUMES", []interface{}{coinbase, owner}},
+	}
+	for i, call := range calls {
+		input, err := parsed.Pack(call.method, call.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Origin = call.from
+		statedb.Prepare(common.BytesToHash([]byte{byte(i), 0}), common.Hash{}, 0)
+		solidityRet, _, solidityErr := runtime.Call(solidityAddr, input, cfg)
+		statedb.Prepare(common.BytesToHash([]byte{byte(i), 1}), common.Hash{}, 0)
+		assembledRet, _, assembledErr := runtime.Call(assembledAddr, input, cfg)
+
+		if (solidityErr == nil) != (assembledErr == nil) || !bytes.Equal(solidityRet, assembledRet) {
+			t.Errorf("call %d %s: result mismatch: have %x (%v), want %x (%v)", i, call.method, assembledRet, assembledErr, solidityRet, solidityErr)
+		}
+		solidityLogs, assembledLogs := statedb.GetLogs(common.BytesToHash([]byte{byte(i), 0})), statedb.GetLogs(common.BytesToHash([]byte{byte(i), 1}))
+		if len(solidityLogs) != len(assembledLogs) {
+			t.Errorf("call %d %s: log count mismatch: have %d, want %d", i, call.method, len(assembledLogs), len(solidityLogs))
+			continue
+		}
+		for j := range solidityLogs {
+			if !reflect.DeepEqual(solidityLogs[j].Topics, assembledLogs[j].Topics) || !bytes.Equal(solidityLogs[j].Data, assembledLogs[j].Data) {
+				t.Errorf("call %d %s: log %d mismatch: have %v %x, want %v %x", i, call.method, j, assembledLogs[j].Topics, assembledLogs[j].Data, solidityLogs[j].Topics, solidityLogs[j].Data)
+			}
+		}
+	}
+	if solidityStorage, assembledStorage := storage(statedb, solidityAddr), storage(statedb, assembledAddr); !reflect.DeepEqual(solidityStorage, assembledStorage) {
+		t.Errorf("storage mismatch: have %x, want %x", assembledStorage, solidityStorage)
+	}
+}
+
+// storage returns the non-empty storage slots of a contract.
+func storage(statedb *state.StateDB, addr common.Address) map[common.Hash]common.Hash {
+	slots := make(map[common.Hash]common.Hash)
+	statedb.ForEachStorage(addr, func(key, value common.Hash) bool {
+		if value != (common.Hash{}) {
+			slots[key] = value
+		}
+		return true
+	})
+	return slots
+}
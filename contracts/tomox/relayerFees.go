@@ -0,0 +1,40 @@
+package tomox
+
+import (
+	"github.com/tomochain/tomochain/accounts/abi/bind"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/contracts/tomox/contract"
+)
+
+type RelayerFees struct {
+	*contract.RelayerFeesSession
+	contractBackend bind.ContractBackend
+}
+
+func NewRelayerFees(transactOpts *bind.TransactOpts, contractAddr common.Address, contractBackend bind.ContractBackend) (*RelayerFees, error) {
+	smartContract, err := contract.NewRelayerFees(contractAddr, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RelayerFees{
+		&contract.RelayerFeesSession{
+			Contract:     smartContract,
+			TransactOpts: *transactOpts,
+		},
+		contractBackend,
+	}, nil
+}
+
+func DeployRelayerFees(transactOpts *bind.TransactOpts, contractBackend bind.ContractBackend, relayerRegistration common.Address) (common.Address, *RelayerFees, error) {
+	contractAddr, _, _, err := contract.DeployRelayerFees(transactOpts, contractBackend, relayerRegistration)
+	if err != nil {
+		return contractAddr, nil, err
+	}
+	smartContract, err := NewRelayerFees(transactOpts, contractAddr, contractBackend)
+	if err != nil {
+		return contractAddr, nil, err
+	}
+
+	return contractAddr, smartContract, nil
+}
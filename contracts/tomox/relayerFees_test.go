@@ -0,0 +1,140 @@
+package tomox
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi/bind"
+	"github.com/tomochain/tomochain/accounts/abi/bind/backends"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+var (
+	ownerKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ownerAddr   = crypto.PubkeyToAddress(ownerKey.PublicKey)
+
+	relayerOwnerKey, _ = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+	relayerOwnerAddr   = crypto.PubkeyToAddress(relayerOwnerKey.PublicKey)
+
+	coinbaseAddr = common.HexToAddress("0x0000000000000000000000000000000000001111")
+	userAddr     = common.HexToAddress("0x0000000000000000000000000000000000002222")
+)
+
+func TestRelayerFees(t *testing.T) {
+	deposit := new(big.Int).Mul(big.NewInt(25000), big.NewInt(1e18))
+	contractBackend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		ownerAddr:        {Balance: new(big.Int).Mul(deposit, big.NewInt(10))},
+		relayerOwnerAddr: {Balance: new(big.Int).Mul(deposit, big.NewInt(10))},
+	})
+	transactOpts := bind.NewKeyedTransactor(ownerKey)
+	listingAddr, _, err := DeployTOMOXListing(transactOpts, contractBackend)
+	if err != nil {
+		t.Fatal("can't deploy the listing contract: ", err)
+	}
+	contractBackend.Commit()
+	registrationAddr, _, err := DeployRelayerRegistration(transactOpts, contractBackend, listingAddr, big.NewInt(100), big.NewInt(10), deposit)
+	if err != nil {
+		t.Fatal("can't deploy the registration contract: ", err)
+	}
+	contractBackend.Commit()
+	feesAddr, fees, err := DeployRelayerFees(transactOpts, contractBackend, registrationAddr)
+	if err != nil {
+		t.Fatal("can't deploy the relayer fees contract: ", err)
+	}
+	contractBackend.Commit()
+
+	relayerOwnerOpts := bind.NewKeyedTransactor(relayerOwnerKey)
+	registration, err := NewRelayerRegistration(relayerOwnerOpts, registrationAddr, contractBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registration.TransactOpts.Value = deposit
+	if _, err := registration.Register(coinbaseAddr, 10, []common.Address{}, []common.Address{}); err != nil {
+		t.Fatal("can't register the relayer: ", err)
+	}
+	contractBackend.Commit()
+
+	// Only the owner of an active relayer sets valid fees
+	volumes := []*big.Int{big.NewInt(1000), big.NewInt(5000)}
+	if _, err := fees.UpdateMakerTakerFee(coinbaseAddr, 5, 15, volumes, []uint16{4, 0}, []uint16{12, 8}); err == nil {
+		t.Error("fees updated by a non-owner")
+	}
+	relayerFees, err := NewRelayerFees(relayerOwnerOpts, feesAddr, contractBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := []struct {
+		name                 string
+		makerFee, takerFee   uint16
+		volumes              []*big.Int
+		makerFees, takerFees []uint16
+	}{
+		{"Fee", 1000, 15, nil, nil, nil},
+		{"Unsorted", 5, 15, []*big.Int{big.NewInt(5000), big.NewInt(1000)}, []uint16{4, 0}, []uint16{12, 8}},
+		{"Length", 5, 15, volumes, []uint16{4}, []uint16{12, 8}},
+		{"TierFee", 5, 15, volumes, []uint16{4, 0}, []uint16{12, 1000}},
+	}
+	for _, test := range invalid {
+		if _, err := relayerFees.UpdateMakerTakerFee(coinbaseAddr, test.makerFee, test.takerFee, test.volumes, test.makerFees, test.takerFees); err == nil {
+			t.Errorf("%s: invalid fees accepted", test.name)
+		}
+	}
+	if _, err := relayerFees.UpdateMakerTakerFee(coinbaseAddr, 5, 15, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, []uint16{1, 1, 1}, []uint16{1, 1, 1}); err != nil {
+		t.Fatal("can't update the fees: ", err)
+	}
+	contractBackend.Commit()
+	if _, err := relayerFees.UpdateMakerTakerFee(coinbaseAddr, 5, 15, volumes, []uint16{4, 0}, []uint16{12, 8}); err != nil {
+		t.Fatal("can't update the fees: ", err)
+	}
+	contractBackend.Commit()
+
+	// The getters return the fees and the tiers last set
+	set, err := relayerFees.RELAYERFEES(coinbaseAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Owner != relayerOwnerAddr || set.MakerFee.Int64() != 5 || set.TakerFee.Int64() != 15 {
+		t.Errorf("fees mismatch: have %x %v/%v", set.Owner, set.MakerFee, set.TakerFee)
+	}
+	if volume, makerFee, takerFee, err := relayerFees.GetFeeTier(coinbaseAddr, big.NewInt(1)); err != nil || volume.Int64() != 5000 || makerFee.Int64() != 0 || takerFee.Int64() != 8 {
+		t.Errorf("tier mismatch: have %v %v/%v, err %v", volume, makerFee, takerFee, err)
+	}
+	if _, _, _, err := relayerFees.GetFeeTier(coinbaseAddr, big.NewInt(2)); err == nil {
+		t.Error("tier past the last one returned")
+	}
+
+	// The nodes read the same fees and tiers from the storage
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	copyStorage := func(from common.Address, to common.Address) {
+		var keys []common.Hash
+		err := contractBackend.ForEachStorageAt(context.Background(), from, nil, func(key, _ common.Hash) bool {
+			keys = append(keys, key)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			val, err := contractBackend.StorageAt(context.Background(), from, key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			statedb.SetState(to, key, common.BytesToHash(val))
+		}
+	}
+	copyStorage(registrationAddr, common.HexToAddress(common.RelayerRegistrationSMC))
+	copyStorage(feesAddr, common.HexToAddress(common.RelayerFeesSMC))
+	if tiers := tradingstate.GetRelayerFeeTiers(coinbaseAddr, statedb); len(tiers) != 2 || tiers[0].Volume.Int64() != 1000 || tiers[0].TakerFee.Int64() != 12 {
+		t.Errorf("stored tiers mismatch: have %v", tiers)
+	}
+	tradingstate.AddUserVolume(coinbaseAddr, userAddr, big.NewInt(1000), statedb)
+	if tier, makerFee, takerFee := tradingstate.GetUserFeeTier(coinbaseAddr, userAddr, statedb); tier != 1 || makerFee.Int64() != 4 || takerFee.Int64() != 12 {
+		t.Errorf("stored fees mismatch: have tier %d, fees %v/%v", tier, makerFee, takerFee)
+	}
+}
@@ -101,12 +101,13 @@ func CreateTransactionSign(chainConfig *params.ChainConfig, pool *core.TxPool, m
 		// Create secret tx.
 		blockNumber := block.Number().Uint64()
 		checkNumber := blockNumber % chainConfig.Posv.Epoch
+		secretPoint, openingPoint := RandomizeWindow(chainConfig.Posv.Epoch)
 		// Generate random private key and save into chaindb.
 		randomizeKeyName := []byte("randomizeKey")
 		exist, _ := chainDb.Has(randomizeKeyName)
 
 		// Set secret for randomize.
-		if !exist && checkNumber > 0 && common.EpocBlockSecret <= checkNumber && common.EpocBlockOpening > checkNumber {
+		if !exist && checkNumber > 0 && secretPoint <= checkNumber && openingPoint > checkNumber {
 			// Only process when private key empty in state db.
 			// Save randomize key into state db.
 			randomizeKeyValue := RandStringByte(32)
@@ -132,7 +133,7 @@ func CreateTransactionSign(chainConfig *params.ChainConfig, pool *core.TxPool, m
 		}
 
 		// Set opening for randomize.
-		if exist && checkNumber > 0 && common.EpocBlockOpening <= checkNumber && common.EpocBlockRandomize >= checkNumber {
+		if exist && checkNumber > 0 && openingPoint <= checkNumber {
 			randomizeKeyValue, err := chainDb.Get(randomizeKeyName)
 			if err != nil {
 				log.Error("Fail to get randomize key from state db.", "error", err)
@@ -164,6 +165,21 @@ func CreateTransactionSign(chainConfig *params.ChainConfig, pool *core.TxPool, m
 	return nil
 }
 
+// RandomizeWindow returns the blocks of an epoch from which the masternodes
+// send the secret of their randomize and then its opening, up to the end of the
+// epoch. They keep the proportions of the 900 blocks epoch of the mainnet.
+func RandomizeWindow(epoch uint64) (secret uint64, opening uint64) {
+	secret = epoch * common.EpocBlockSecret / common.EpocBlockRandomize
+	opening = epoch * common.EpocBlockOpening / common.EpocBlockRandomize
+	if secret == 0 {
+		secret = 1
+	}
+	if opening <= secret {
+		opening = secret + 1
+	}
+	return secret, opening
+}
+
 // Create tx sign.
 func CreateTxSign(blockNumber *big.Int, blockHash common.Hash, nonce uint64, blockSigner common.Address) *types.Transaction {
 	data := common.Hex2Bytes(common.HexSignMethod)
@@ -285,6 +301,99 @@ func BuildValidatorFromM2(listM2 []int64) []byte {
 	return validatorBytes
 }
 
+// M1M2Entry is the validator (M2) assigned to a masternode (M1) at a checkpoint,
+// together with the randomize commitment it was derived from.
+type M1M2Entry struct {
+	Masternode common.Address `json:"masternode"`
+	Validator  common.Address `json:"validator"`
+	Random     int64          `json:"random"`
+	Revealed   bool           `json:"revealed"`
+}
+
+// M1M2Mapping is the validators assignment of a checkpoint re-derived from the
+// randomize contract, compared with the one recorded in the checkpoint header.
+// Past TIPRandomize the validators rotate within the epoch, so the mapping is
+// the one of the given block.
+type M1M2Mapping struct {
+	Checkpoint         uint64           `json:"checkpoint"`
+	Block              uint64           `json:"block"`
+	Mapping            []M1M2Entry      `json:"mapping"`
+	HeaderValidators   []int64          `json:"headerValidators"`
+	ComputedValidators []int64          `json:"computedValidators"`
+	Match              bool             `json:"match"`
+	Unrevealed         []common.Address `json:"unrevealed"`
+}
+
+// GetRandomizeFromState gets the random number a masternode committed to in the
+// randomize contract straight from the given state. The returned flag reports
+// whether the masternode revealed its opening.
+func GetRandomizeFromState(statedb *state.StateDB, addrMasternode common.Address) (int64, bool, error) {
+	secrets := state.GetSecret(statedb, addrMasternode)
+	opening := state.GetOpening(statedb, addrMasternode)
+	random, err := DecryptRandomizeFromSecretsAndOpening(secrets, opening)
+	return random, opening != [32]byte{}, err
+}
+
+// BuildM1M2Mapping recomputes the validators of a checkpoint header from the
+// secrets and openings in the randomize contract, and assigns them to the
+// masternodes for the given block of the checkpoint's epoch the way the PoSV
+// engine does. The state must be the one the checkpoint was built on, i.e. the
+// state of its parent block.
+func BuildM1M2Mapping(config *params.ChainConfig, statedb *state.StateDB, checkpoint *types.Header, number uint64) (*M1M2Mapping, error) {
+	if number < checkpoint.Number.Uint64() || number >= checkpoint.Number.Uint64()+config.Posv.Epoch {
+		return nil, fmt.Errorf("block #%d is not in the epoch of checkpoint #%d", number, checkpoint.Number)
+	}
+	masternodes := posv.GetMasternodesFromCheckpointHeader(checkpoint)
+	if len(masternodes) == 0 {
+		return nil, core.ErrNotFoundM1
+	}
+	var (
+		candidates = make([]int64, 0, len(masternodes))
+		unrevealed = []common.Address{}
+		revealed   = make(map[common.Address]bool)
+	)
+	for _, addr := range masternodes {
+		random, ok, err := GetRandomizeFromState(statedb, addr)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			unrevealed = append(unrevealed, addr)
+		}
+		revealed[addr] = ok
+		candidates = append(candidates, random)
+	}
+	m2, err := GenM2FromRandomize(candidates, int64(len(masternodes)))
+	if err != nil {
+		return nil, err
+	}
+	// Assign the computed validators through the engine, rotation included
+	computed := types.CopyHeader(checkpoint)
+	computed.Validators = BuildValidatorFromM2(m2)
+	m1m2, err := posv.GetM1M2FromCheckpointHeader(computed, &types.Header{Number: new(big.Int).SetUint64(number)}, config)
+	if err != nil {
+		return nil, err
+	}
+	mapping := &M1M2Mapping{
+		Checkpoint:         checkpoint.Number.Uint64(),
+		Block:              number,
+		Mapping:            make([]M1M2Entry, len(masternodes)),
+		HeaderValidators:   posv.ExtractValidatorsFromBytes(checkpoint.Validators),
+		ComputedValidators: m2,
+		Match:              bytes.Equal(checkpoint.Validators, computed.Validators),
+		Unrevealed:         unrevealed,
+	}
+	for i, m1 := range masternodes {
+		mapping.Mapping[i] = M1M2Entry{
+			Masternode: m1,
+			Validator:  m1m2[m1],
+			Random:     candidates[i],
+			Revealed:   revealed[m1],
+		}
+	}
+	return mapping, nil
+}
+
 // Decode validator hex string.
 func DecodeValidatorsHexData(validatorsStr string) ([]int64, error) {
 	validatorsByte, err := hexutil.Decode(validatorsStr)
@@ -19,14 +19,18 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"github.com/tomochain/tomochain/accounts/abi/bind"
 	"github.com/tomochain/tomochain/accounts/abi/bind/backends"
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/consensus/posv"
 	"github.com/tomochain/tomochain/contracts/blocksigner"
 	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
 	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -209,3 +213,109 @@ func TestDecodeValidatorsHexData(t *testing.T) {
 	}
 	t.Log("b", b)
 }
+
+func TestBuildM1M2Mapping(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	randomizeAddr := common.HexToAddress(common.RandomizeSMC)
+	// acc1 and acc2 reveal their random numbers, acc3 only commits a secret
+	commit := func(addr common.Address, random int64, reveal bool) {
+		opening := RandStringByte(32)
+		var secret [32]byte
+		copy(secret[:], common.LeftPadBytes([]byte(Encrypt(opening, fmt.Sprintf("%d", random))), 32))
+
+		locSecret := common.BigToHash(state.GetLocMappingAtKey(addr.Hash(), 0))
+		statedb.SetState(randomizeAddr, locSecret, common.BigToHash(big.NewInt(1)))
+		statedb.SetState(randomizeAddr, state.GetLocDynamicArrAtElement(locSecret, 0, 1), common.BytesToHash(secret[:]))
+		if reveal {
+			locOpening := common.BigToHash(state.GetLocMappingAtKey(addr.Hash(), 1))
+			statedb.SetState(randomizeAddr, locOpening, common.BytesToHash(opening))
+		}
+	}
+	commit(acc1Addr, 12, true)
+	commit(acc2Addr, 345, true)
+	commit(acc3Addr, 6789, false)
+
+	masternodes := []common.Address{acc1Addr, acc2Addr, acc3Addr}
+	m2, _ := GenM2FromRandomize([]int64{12, 345, 0}, int64(len(masternodes)))
+
+	extra := make([]byte, extraVanity)
+	for _, addr := range masternodes {
+		extra = append(extra, addr.Bytes()...)
+	}
+	extra = append(extra, make([]byte, extraSeal)...)
+	checkpoint := &types.Header{
+		Number:     big.NewInt(900),
+		Extra:      extra,
+		Validators: BuildValidatorFromM2(m2),
+	}
+	config := &params.ChainConfig{Posv: &params.PosvConfig{Epoch: 900}}
+
+	mapping, err := BuildM1M2Mapping(config, statedb, checkpoint, 900)
+	if err != nil {
+		t.Fatalf("failed to build mapping: %v", err)
+	}
+	if !mapping.Match {
+		t.Errorf("validators mismatch: header %v, computed %v", mapping.HeaderValidators, mapping.ComputedValidators)
+	}
+	if len(mapping.Unrevealed) != 1 || mapping.Unrevealed[0] != acc3Addr {
+		t.Errorf("unrevealed masternodes mismatch: have %v, want [%v]", mapping.Unrevealed, acc3Addr)
+	}
+	for i, entry := range mapping.Mapping {
+		if want := masternodes[m2[i]%int64(len(masternodes))]; entry.Validator != want {
+			t.Errorf("validator of %v mismatch: have %v, want %v", entry.Masternode, entry.Validator, want)
+		}
+	}
+
+	// Tamper with the checkpoint validators
+	checkpoint.Validators = BuildValidatorFromM2([]int64{m2[1], m2[0], m2[2]})
+	if mapping, err = BuildM1M2Mapping(config, statedb, checkpoint, 900); err != nil {
+		t.Fatalf("failed to build mapping: %v", err)
+	}
+	if mapping.Match {
+		t.Errorf("tampered validators reported as matching")
+	}
+}
+
+// Tests that past TIPRandomize the validators are rotated within the epoch the
+// way the PoSV engine assigns them.
+func TestBuildM1M2MappingRotation(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	masternodes := []common.Address{acc1Addr, acc2Addr, acc3Addr}
+	m2, _ := GenM2FromRandomize([]int64{0, 0, 0}, int64(len(masternodes)))
+
+	extra := make([]byte, extraVanity)
+	for _, addr := range masternodes {
+		extra = append(extra, addr.Bytes()...)
+	}
+	extra = append(extra, make([]byte, extraSeal)...)
+	number := new(big.Int).Mul(new(big.Int).Add(new(big.Int).Div(common.TIPRandomizeBlock, big.NewInt(900)), common.Big1), big.NewInt(900))
+	checkpoint := &types.Header{
+		Number:     number,
+		Extra:      extra,
+		Validators: BuildValidatorFromM2(m2),
+	}
+	config := &params.ChainConfig{Posv: &params.PosvConfig{Epoch: 900}}
+
+	// Block 7 of the epoch moves the validators by (7 / 3) % 3 = 2 masternodes
+	block := number.Uint64() + 7
+	mapping, err := BuildM1M2Mapping(config, statedb, checkpoint, block)
+	if err != nil {
+		t.Fatalf("failed to build mapping: %v", err)
+	}
+	if !mapping.Match || mapping.Block != block {
+		t.Errorf("mapping mismatch: match %v, block %d", mapping.Match, mapping.Block)
+	}
+	m1m2, err := posv.GetM1M2FromCheckpointHeader(checkpoint, &types.Header{Number: new(big.Int).SetUint64(block)}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, entry := range mapping.Mapping {
+		want := masternodes[(m2[i]%int64(len(masternodes))+2)%int64(len(masternodes))]
+		if entry.Validator != want || m1m2[entry.Masternode] != want {
+			t.Errorf("validator of %v mismatch: have %v, engine %v, want %v", entry.Masternode, entry.Validator, m1m2[entry.Masternode], want)
+		}
+	}
+	if _, err := BuildM1M2Mapping(config, statedb, checkpoint, number.Uint64()+900); err == nil {
+		t.Errorf("mapping built for a block of the next epoch")
+	}
+}
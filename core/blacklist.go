@@ -0,0 +1,85 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/params"
+)
+
+// The rules a transaction can be black-listed by.
+const (
+	BlacklistRuleHardcoded = "hard-coded black-list"
+	BlacklistRuleContract  = "black-list contract"
+)
+
+// BlacklistError is returned for the transactions sent from or to a black-listed
+// address, naming the rule rejecting them.
+type BlacklistError struct {
+	Role    string // Either "sender" or "receiver"
+	Rule    string
+	Address common.Address
+}
+
+func (e *BlacklistError) Error() string {
+	return fmt.Sprintf("transaction with %s in %s: %v", e.Role, e.Rule, e.Address.Hex())
+}
+
+// BlacklistRule returns the rule black-listing an address in the block of the
+// given number, or an empty string if the address isn't black-listed. As the
+// black-list fork block installs the black-list contract, seeded with the
+// hard-coded list, the address is looked up in the contract in the state of the
+// parent block past the fork block, up to it in the hard-coded list.
+func BlacklistRule(config *params.ChainConfig, parentState *state.StateDB, number *big.Int, addr common.Address) string {
+	if config.IsBlacklist(new(big.Int).Sub(number, common.Big1)) {
+		if state.IsBlacklisted(parentState, addr) {
+			return BlacklistRuleContract
+		}
+		return ""
+	}
+	if common.Blacklist[addr] {
+		return BlacklistRuleHardcoded
+	}
+	return ""
+}
+
+// CheckBlacklist returns a BlacklistError if the sender or the receiver of a
+// transaction in the block of the given number is black-listed. Either address
+// may be nil to skip its check. The parent state is only read past the
+// black-list fork block.
+func CheckBlacklist(config *params.ChainConfig, parentState *state.StateDB, number *big.Int, from, to *common.Address) error {
+	if from != nil {
+		if rule := BlacklistRule(config, parentState, number, *from); rule != "" {
+			return &BlacklistError{Role: "sender", Rule: rule, Address: *from}
+		}
+	}
+	if to != nil {
+		if rule := BlacklistRule(config, parentState, number, *to); rule != "" {
+			return &BlacklistError{Role: "receiver", Rule: rule, Address: *to}
+		}
+	}
+	return nil
+}
+
+// IsBlacklistEnforced returns whether the blocks of the given number may not
+// contain black-listed transactions.
+func IsBlacklistEnforced(config *params.ChainConfig, number *big.Int) bool {
+	return config.IsBlacklist(number) || (number.Uint64() >= common.BlackListHFBlock && !common.IsTestnet)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus/ethash"
+	blacklistContract "github.com/tomochain/tomochain/contracts/blacklist/contract"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
+)
+
+// Tests that the black-list fork installs the black-list contract seeded with
+// the hard-coded list, that its owner governs the list from then on, and that
+// transactions are checked against the contract past the fork block.
+func TestCheckBlacklist(t *testing.T) {
+	var (
+		hardcoded = common.HexToAddress("0x5248bfb72fd4f234e062d3e9bb76f08643004fcd")
+		listed    = common.HexToAddress("0x00000000000000000000000000000000000b1ac1")
+		other     = common.HexToAddress("0x00000000000000000000000000000000000b1ac2")
+		contract  = common.HexToAddress(common.BlacklistSMC)
+		owner     = common.HexToAddress(common.FoudationAddr)
+		key, _    = crypto.GenerateKey()
+		addr      = crypto.PubkeyToAddress(key.PublicKey)
+		config    = *params.TestChainConfig
+	)
+	config.BlacklistBlock = big.NewInt(2)
+	gspec := &Genesis{
+		Config: &config,
+		Alloc: GenesisAlloc{
+			addr: {Balance: big.NewInt(params.Ether)},
+			// The foundation wallet forwards the calls it receives to the
+			// black-list contract, reverting if they fail
+			owner: {
+				Balance: new(big.Int),
+				Code:    append(append(common.FromHex("0x366000600037600060003660006000"), append([]byte{0x73}, contract.Bytes()...)...), common.FromHex("0x5af115602b57005b600080fd")...),
+			},
+		},
+	}
+	blacklistABI, err := abi.JSON(strings.NewReader(blacklistContract.BlacklistABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(nonce uint64, to, listed common.Address) *types.Transaction {
+		data, err := blacklistABI.Pack("add", []common.Address{listed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := types.SignTx(types.NewTransaction(nonce, to, new(big.Int), 100000, big.NewInt(params.Shannon), data), types.HomesteadSigner{}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	var (
+		engine  = ethash.NewFaker()
+		db      = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(db)
+	)
+	chain, err := NewBlockChain(db, nil, gspec.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	// In block 3 the owner black-lists an address, anyone else fails to
+	txs := types.Transactions{add(0, owner, listed), add(1, contract, other)}
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine, db, 3, func(i int, b *BlockGen) {
+		if i == 2 {
+			for _, tx := range txs {
+				b.AddTxWithChain(chain, tx)
+			}
+		}
+	})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	receipts := GetBlockReceipts(db, blocks[2].Hash(), blocks[2].NumberU64())
+	if len(receipts) != 2 || receipts[0].Status != types.ReceiptStatusSuccessful || receipts[1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("unexpected receipts of the black-list updates: %v", receipts)
+	}
+	if logs := receipts[0].Logs; len(logs) != 1 || logs[0].Address != contract || logs[0].Topics[1] != listed.Hash() {
+		t.Errorf("unexpected logs of the black-list update: %v", logs)
+	}
+
+	var testSuite = []struct {
+		number   int64
+		from, to common.Address
+		wantRole string
+		wantRule string
+	}{
+		{1, hardcoded, other, "sender", BlacklistRuleHardcoded},
+		{1, other, hardcoded, "receiver", BlacklistRuleHardcoded},
+		{2, hardcoded, other, "sender", BlacklistRuleHardcoded},
+		{2, listed, other, "", ""},
+		{3, hardcoded, other, "sender", BlacklistRuleContract},
+		{3, listed, other, "", ""},
+		{4, other, hardcoded, "receiver", BlacklistRuleContract},
+		{4, listed, other, "sender", BlacklistRuleContract},
+		{4, other, listed, "receiver", BlacklistRuleContract},
+		{4, addr, other, "", ""},
+	}
+	for i, tc := range testSuite {
+		parentState, err := chain.StateAt(chain.GetBlockByNumber(uint64(tc.number - 1)).Root())
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, to := tc.from, tc.to
+		err = CheckBlacklist(gspec.Config, parentState, big.NewInt(tc.number), &from, &to)
+		if tc.wantRule == "" {
+			if err != nil {
+				t.Errorf("test %d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		blErr, ok := err.(*BlacklistError)
+		if !ok {
+			t.Fatalf("test %d: error mismatch: have %v, want black-list error", i, err)
+		}
+		if blErr.Role != tc.wantRole || blErr.Rule != tc.wantRule {
+			t.Errorf("test %d: black-list error mismatch: have %s in %s, want %s in %s", i, blErr.Role, blErr.Rule, tc.wantRole, tc.wantRule)
+		}
+	}
+	// Up to the fork block the state is not needed
+	if err := CheckBlacklist(gspec.Config, nil, big.NewInt(2), &hardcoded, nil); err == nil {
+		t.Error("expected hard-coded black-list error without state")
+	}
+}
@@ -60,6 +60,8 @@ var (
 	headHeaderGauge    = metrics.NewRegisteredGauge("chain/head/header", nil)
 	headFastBlockGauge = metrics.NewRegisteredGauge("chain/head/receipt", nil)
 
+	headFinalizedBlockGauge = metrics.NewRegisteredGauge("chain/head/finalized", nil)
+
 	accountReadTimer   = metrics.NewRegisteredTimer("chain/account/reads", nil)
 	accountHashTimer   = metrics.NewRegisteredTimer("chain/account/hashes", nil)
 	accountUpdateTimer = metrics.NewRegisteredTimer("chain/account/updates", nil)
@@ -80,7 +82,6 @@ var (
 	blockReorgDropMeter     = metrics.NewRegisteredMeter("chain/reorg/drop", nil)
 	blockReorgInvalidatedTx = metrics.NewRegisteredMeter("chain/reorg/invalidTx", nil)
 
-	CheckpointCh = make(chan int)
 	ErrNoGenesis = errors.New("Genesis not found in chain")
 )
 
@@ -105,6 +106,7 @@ type CacheConfig struct {
 	Disabled      bool          // Whether to disable trie write caching (archive node)
 	TrieNodeLimit int           // Memory limit (MB) at which to flush the current in-memory trie to disk
 	TrieTimeLimit time.Duration // Time limit after which to flush the current in-memory trie to disk
+	RevertReasons bool          // Whether to record the revert data of the failed transactions
 }
 type ResultProcessBlock struct {
 	logs         []*types.Log
@@ -148,6 +150,8 @@ type BlockChain struct {
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
+	finalizedHeadFeed event.Feed
+
 	mu      sync.RWMutex // global mutex for locking chain operations
 	chainmu sync.RWMutex // blockchain insertion lock
 	procmu  sync.RWMutex // block processor lock
@@ -156,6 +160,10 @@ type BlockChain struct {
 	currentBlock     atomic.Value // Current head of the block chain
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
+	currentFinalizedBlock atomic.Value // Current head of the finalized blocks (signed by enough masternodes)
+	finalitySigners       *lru.Cache   // Masternodes that signed a block, by block hash, for tracking finality
+	finalityMu            sync.Mutex   // Finality tracking lock, as receipt chains are imported outside chainmu
+
 	stateCache state.Database // State database to reuse between imports (contains state cache)
 
 	bodyCache        *lru.Cache    // Cache for the most recent block bodies
@@ -176,9 +184,10 @@ type BlockChain struct {
 	validator Validator // block and state validator interface
 	vmConfig  vm.Config
 
-	badBlocks   *lru.Cache // Bad block cache
-	IPCEndpoint string
-	Client      *ethclient.Client // Global ipc client instance.
+	badBlocks    *lru.Cache // Bad block cache
+	IPCEndpoint  string
+	Client       *ethclient.Client // Global ipc client instance.
+	CheckpointCh chan int          // Notified at every checkpoint block, for the node to reconcile its staking state
 
 	// Blocks hash array by block number
 	// cache field for tracking finality purpose, can't use for tracking block vs block relationship
@@ -205,6 +214,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bodyRLPCache, _ := lru.New(bodyCacheLimit)
 	blockCache, _ := lru.New(blockCacheLimit)
 	blocksHashCache, _ := lru.New(blocksHashCacheLimit)
+	finalitySigners, _ := lru.New(finalitySignersCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
 	resultProcess, _ := lru.New(blockCacheLimit)
@@ -237,11 +247,13 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		vmConfig:            vmConfig,
 		badBlocks:           badBlocks,
 		blocksHashCache:     blocksHashCache,
+		finalitySigners:     finalitySigners,
 		resultTrade:         resultTrade,
 		rejectedOrders:      rejectedOrders,
 		resultLendingTrade:  resultLendingTrade,
 		rejectedLendingItem: rejectedLendingItem,
 		finalizedTrade:      finalizedTrade,
+		CheckpointCh:        make(chan int),
 	}
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
@@ -387,8 +399,17 @@ func (bc *BlockChain) loadLastState() error {
 		}
 	}
 
-	// Issue a status log for the user
+	// Restore the last known finalized block and the signers of the blocks above,
+	// up to the fast sync head which may be ahead of the full one
 	currentFastBlock := bc.CurrentFastBlock()
+	finalityHead := currentBlock
+	if currentFastBlock.NumberU64() > finalityHead.NumberU64() {
+		finalityHead = currentFastBlock
+	}
+	bc.loadFinalizedHead(finalityHead)
+	bc.recoverFinality(finalityHead)
+
+	// Issue a status log for the user
 
 	headerTd := bc.GetTd(currentHeader.Hash(), currentHeader.Number.Uint64())
 	blockTd := bc.GetTd(currentBlock.Hash(), currentBlock.NumberU64())
@@ -424,6 +445,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.blockCache.Purge()
 	bc.futureBlocks.Purge()
 	bc.blocksHashCache.Purge()
+	bc.finalitySigners.Purge()
 
 	// Rewind the block chain, ensuring we don't end up with a stateless head block
 	if currentBlock := bc.CurrentBlock(); currentBlock != nil && currentHeader.Number.Uint64() < currentBlock.NumberU64() {
@@ -1155,6 +1177,15 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			return 0, err
 		}
 	}
+	// Track the finality of the blocks signed by the ones written, now that
+	// their bodies are all in the database
+	var events []interface{}
+	for i, block := range blockChain {
+		if ev := bc.updateFinality(block, receiptChain[i]); ev != nil {
+			events = append(events, *ev)
+		}
+	}
+	bc.PostChainEvents(events, nil)
 
 	// Update the head fast sync block if better
 	bc.mu.Lock()
@@ -1372,6 +1403,11 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	if err := WriteBlockReceipts(batch, block.Hash(), block.NumberU64(), receipts); err != nil {
 		return NonStatTy, err
 	}
+	if bc.cacheConfig.RevertReasons {
+		if err := WriteBlockRevertReasons(batch, block.Hash(), block.NumberU64(), receipts); err != nil {
+			return NonStatTy, err
+		}
+	}
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
 	// Please refer to http://www.cs.cornell.edu/~ie53/publications/btcProcFC.pdf
@@ -1603,7 +1639,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 					return i, events, coalescedLogs, err
 				}
 				if (block.NumberU64() % bc.chainConfig.Posv.Epoch) == 0 {
-					if err := tradingService.UpdateMediumPriceBeforeEpoch(block.NumberU64()/bc.chainConfig.Posv.Epoch, tradingState, statedb); err != nil {
+					if err := tradingService.UpdateMediumPriceBeforeEpoch(bc, block.Header(), block.NumberU64()/bc.chainConfig.Posv.Epoch, tradingState, statedb); err != nil {
 						return i, events, coalescedLogs, err
 					}
 				} else {
@@ -1740,6 +1776,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			blockInsertTimer.UpdateSince(bstart)
 			events = append(events, ChainEvent{block, block.Hash(), logs})
 			lastCanon = block
+			if ev := bc.updateFinality(block, receipts); ev != nil {
+				events = append(events, *ev)
+			}
 
 			// Only count canonical blocks for GC processing time
 			bc.gcproc += proctime
@@ -1763,8 +1802,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		if bc.chainConfig.Posv != nil {
 			// epoch block
 			if (chain[i].NumberU64() % bc.chainConfig.Posv.Epoch) == 0 {
-				CheckpointCh <- 1
-
+				bc.CheckpointCh <- 1
 			}
 			// prepare set of masternodes for the next epoch
 			if (chain[i].NumberU64() % bc.chainConfig.Posv.Epoch) == (bc.chainConfig.Posv.Epoch - bc.chainConfig.Posv.Gap) {
@@ -1921,7 +1959,7 @@ func (bc *BlockChain) getResultBlock(block *types.Block, verifiedM2 bool) (*Resu
 				return nil, err
 			}
 			if (block.NumberU64() % bc.chainConfig.Posv.Epoch) == 0 {
-				if err := tradingService.UpdateMediumPriceBeforeEpoch(block.NumberU64()/bc.chainConfig.Posv.Epoch, tradingState, statedb); err != nil {
+				if err := tradingService.UpdateMediumPriceBeforeEpoch(bc, block.Header(), block.NumberU64()/bc.chainConfig.Posv.Epoch, tradingState, statedb); err != nil {
 					return nil, err
 				}
 			} else {
@@ -2091,6 +2129,9 @@ func (bc *BlockChain) insertBlock(block *types.Block) ([]interface{}, []*types.L
 			"txs", len(block.Transactions()), "gas", block.GasUsed(), "elapsed", common.PrettyDuration(time.Since(block.ReceivedAt)))
 		coalescedLogs = append(coalescedLogs, result.logs...)
 		events = append(events, ChainEvent{block, block.Hash(), result.logs})
+		if ev := bc.updateFinality(block, result.receipts); ev != nil {
+			events = append(events, *ev)
+		}
 
 		// Only count canonical blocks for GC processing time
 		bc.gcproc += result.proctime
@@ -2115,8 +2156,7 @@ func (bc *BlockChain) insertBlock(block *types.Block) ([]interface{}, []*types.L
 	if bc.chainConfig.Posv != nil {
 		// epoch block
 		if (block.NumberU64() % bc.chainConfig.Posv.Epoch) == 0 {
-			CheckpointCh <- 1
-
+			bc.CheckpointCh <- 1
 		}
 		// prepare set of masternodes for the next epoch
 		if (block.NumberU64() % bc.chainConfig.Posv.Epoch) == (bc.chainConfig.Posv.Epoch - bc.chainConfig.Posv.Gap) {
@@ -2288,6 +2328,24 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	if len(deletedLogs) > 0 {
 		go bc.rmLogsFeed.Send(RemovedLogsEvent{deletedLogs})
 	}
+	// A finalized block should never be reorged out, but don't keep serving one
+	// that is no longer canonical
+	if finalized := bc.CurrentFinalizedBlock(); finalized != nil && finalized.NumberU64() > commonBlock.NumberU64() {
+		log.Warn("Finalized block reorged out", "number", finalized.Number(), "hash", finalized.Hash())
+		bc.loadFinalizedHead(commonBlock)
+	}
+	// The blocks of the new chain were written as side blocks, so track the
+	// finality of the ones they sign now that they're canonical. The new head
+	// is tracked by the caller once its receipts are written.
+	var finalizedEvent *FinalizedHeadEvent
+	for i := len(newChain) - 1; i > 0; i-- {
+		if ev := bc.updateFinality(newChain[i], GetBlockReceipts(bc.db, newChain[i].Hash(), newChain[i].NumberU64())); ev != nil {
+			finalizedEvent = ev
+		}
+	}
+	if finalizedEvent != nil {
+		go bc.finalizedHeadFeed.Send(*finalizedEvent)
+	}
 	if len(oldChain) > 0 {
 		go func() {
 			for _, block := range oldChain {
@@ -2319,6 +2377,9 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 
 		case ChainSideEvent:
 			bc.chainSideFeed.Send(ev)
+
+		case FinalizedHeadEvent:
+			bc.finalizedHeadFeed.Send(ev)
 		}
 	}
 }
@@ -206,13 +206,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(b.header.Number) == 0 {
 			misc.ApplyDAOHardFork(statedb)
 		}
-		if config.SaigonBlock != nil && config.SaigonBlock.Cmp(b.header.Number) <= 0 {
-			if common.IsTestnet {
-				misc.ApplySaigonHardForkTestnet(statedb, config.SaigonBlock, b.header.Number, config.Posv)
-			} else {
-				misc.ApplySaigonHardFork(statedb, config.SaigonBlock, b.header.Number)
-			}
-		}
+		ApplyForkState(config, statedb, b.header)
 		// Execute any user modifications to the block and finalize it
 		if gen != nil {
 			gen(i, b)
@@ -48,6 +48,7 @@ var (
 	headHeaderKey = []byte("LastHeader")
 	headBlockKey  = []byte("LastBlock")
 	headFastKey   = []byte("LastFast")
+	headFinalKey  = []byte("LastFinalized")
 	trieSyncKey   = []byte("TrieSync")
 
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`).
@@ -59,12 +60,17 @@ var (
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 	lookupPrefix        = []byte("l") // lookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix     = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	addressTxPrefix     = []byte("A") // addressTxPrefix + address + num (uint64 big endian) + index (uint32 big endian) -> address index entry
+	addressTxJournal    = []byte("a") // addressTxJournal + section (uint64 big endian) -> keys of the address index entries of the section
+	addressTxUntraced   = []byte("u") // addressTxUntraced + num (uint64 big endian) -> hash of a block whose internal transfers aren't in the address index
+	revertReasonPrefix  = []byte("R") // revertReasonPrefix + num (uint64 big endian) + hash -> revert data of the transactions of a block
 
 	preimagePrefix = "secure-key-"              // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	AddressIndexPrefix   = []byte("iA") // AddressIndexPrefix is the data table of the address indexer to track its progress
 
 	// used by old db, now only used for conversion
 	oldReceiptsPrefix = []byte("receipts-")
@@ -148,6 +154,16 @@ func GetHeadFastBlockHash(db DatabaseReader) common.Hash {
 	return common.BytesToHash(data)
 }
 
+// GetHeadFinalizedBlockHash retrieves the hash of the most recent block that
+// has been signed by enough masternodes to be considered final.
+func GetHeadFinalizedBlockHash(db DatabaseReader) common.Hash {
+	data, _ := db.Get(headFinalKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
 // GetTrieSyncProgress retrieves the number of tries nodes fast synced to allow
 // reportinc correct numbers across restarts.
 func GetTrieSyncProgress(db DatabaseReader) uint64 {
@@ -266,6 +282,22 @@ func GetBlockReceipts(db DatabaseReader, hash common.Hash, number uint64) types.
 	return receipts
 }
 
+// GetBlockRevertReasons retrieves the revert data recorded for the transactions
+// of a block, by transaction index. It is nil if the block had no failed
+// transaction with revert data or none was recorded.
+func GetBlockRevertReasons(db DatabaseReader, hash common.Hash, number uint64) [][]byte {
+	data, _ := db.Get(append(append(revertReasonPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		return nil
+	}
+	var reasons [][]byte
+	if err := rlp.DecodeBytes(data, &reasons); err != nil {
+		log.Error("Invalid revert reasons RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return reasons
+}
+
 // GetTxLookupEntry retrieves the positional metadata associated with a transaction
 // hash to allow retrieving the transaction or receipt by hash.
 func GetTxLookupEntry(db DatabaseReader, hash common.Hash) (common.Hash, uint64, uint64) {
@@ -389,6 +421,14 @@ func WriteHeadFastBlockHash(db ethdb.KeyValueWriter, hash common.Hash) error {
 	return nil
 }
 
+// WriteHeadFinalizedBlockHash stores the finalized head block's hash.
+func WriteHeadFinalizedBlockHash(db ethdb.KeyValueWriter, hash common.Hash) error {
+	if err := db.Put(headFinalKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store last finalized block's hash", "err", err)
+	}
+	return nil
+}
+
 // WriteTrieSyncProgress stores the fast sync trie process counter to support
 // retrieving it across restarts.
 func WriteTrieSyncProgress(db ethdb.KeyValueWriter, count uint64) error {
@@ -483,6 +523,28 @@ func WriteBlockReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64
 	return nil
 }
 
+// WriteBlockRevertReasons stores the revert data of the failed transactions of
+// a block, as found in their receipts. Nothing is stored if there is none.
+func WriteBlockRevertReasons(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts) error {
+	var (
+		reasons = make([][]byte, len(receipts))
+		found   bool
+	)
+	for i, receipt := range receipts {
+		if receipt.Status == types.ReceiptStatusFailed && len(receipt.RevertReason) > 0 {
+			reasons[i], found = receipt.RevertReason, true
+		}
+	}
+	if !found {
+		return nil
+	}
+	data, err := rlp.EncodeToBytes(reasons)
+	if err != nil {
+		return err
+	}
+	return db.Put(append(append(revertReasonPrefix, encodeBlockNumber(number)...), hash.Bytes()...), data)
+}
+
 // WriteTxLookupEntries stores a positional metadata for every transaction from
 // a block, enabling hash based transaction and receipt lookups.
 func WriteTxLookupEntries(db ethdb.KeyValueWriter, block *types.Block) error {
@@ -504,6 +566,131 @@ func WriteTxLookupEntries(db ethdb.KeyValueWriter, block *types.Block) error {
 	return nil
 }
 
+// AddressTxEntry is a transaction related to an address in the address index.
+// The kinds are a bitset of the ways the address took part in the transaction.
+type AddressTxEntry struct {
+	Address   common.Address `rlp:"-"`
+	Number    uint64         `rlp:"-"`
+	Index     uint32         `rlp:"-"`
+	BlockHash common.Hash
+	TxHash    common.Hash
+	Kinds     uint8
+}
+
+// addressTxKey = addressTxPrefix + address + num (uint64 big endian) + index (uint32 big endian)
+func addressTxKey(address common.Address, number uint64, index uint32) []byte {
+	key := make([]byte, len(addressTxPrefix)+common.AddressLength+12)
+	copy(key, addressTxPrefix)
+	copy(key[len(addressTxPrefix):], address.Bytes())
+	binary.BigEndian.PutUint64(key[len(addressTxPrefix)+common.AddressLength:], number)
+	binary.BigEndian.PutUint32(key[len(addressTxPrefix)+common.AddressLength+8:], index)
+	return key
+}
+
+// WriteAddressTxSection stores the address index entries of a chain indexer
+// section and the hashes of its blocks whose internal transfers couldn't be
+// traced by number, along with a journal of them to roll the section back.
+func WriteAddressTxSection(db ethdb.KeyValueWriter, section uint64, entries []AddressTxEntry, untraced map[uint64]common.Hash) error {
+	keys := make([][]byte, 0, len(entries)+len(untraced))
+	for _, entry := range entries {
+		data, err := rlp.EncodeToBytes(entry)
+		if err != nil {
+			return err
+		}
+		key := addressTxKey(entry.Address, entry.Number, entry.Index)
+		if err := db.Put(key, data); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	for number, hash := range untraced {
+		key := append(append([]byte{}, addressTxUntraced...), encodeBlockNumber(number)...)
+		if err := db.Put(key, hash.Bytes()); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	data, err := rlp.EncodeToBytes(keys)
+	if err != nil {
+		return err
+	}
+	return db.Put(append(append([]byte{}, addressTxJournal...), encodeBlockNumber(section)...), data)
+}
+
+// DeleteAddressTxSection removes all the address index entries and untraced
+// blocks previously stored for a chain indexer section.
+func DeleteAddressTxSection(db ethdb.KeyValueStore, section uint64) error {
+	journal := append(append([]byte{}, addressTxJournal...), encodeBlockNumber(section)...)
+	data, _ := db.Get(journal)
+	if len(data) == 0 {
+		return nil
+	}
+	var keys [][]byte
+	if err := rlp.DecodeBytes(data, &keys); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return db.Delete(journal)
+}
+
+// GetAddressTxEntries retrieves the address index entries of an address in the
+// given block range, in chain order. The first skip entries are dropped and at
+// most limit are returned.
+func GetAddressTxEntries(db ethdb.Iteratee, address common.Address, from, to uint64, skip, limit int) ([]AddressTxEntry, error) {
+	prefix := append(append([]byte{}, addressTxPrefix...), address.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var entries []AddressTxEntry
+	for it.Next() && len(entries) < limit {
+		key := it.Key()
+		if len(key) != len(prefix)+12 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		var entry AddressTxEntry
+		if err := rlp.DecodeBytes(it.Value(), &entry); err != nil {
+			return nil, err
+		}
+		entry.Address, entry.Number, entry.Index = address, number, binary.BigEndian.Uint32(key[len(prefix)+8:])
+		entries = append(entries, entry)
+	}
+	return entries, it.Error()
+}
+
+// GetAddressTxUntraced retrieves the numbers of at most limit blocks in the
+// given range whose internal transfers aren't in the address index, in chain
+// order.
+func GetAddressTxUntraced(db ethdb.Iteratee, from, to uint64, limit int) ([]uint64, error) {
+	it := db.NewIterator(addressTxUntraced, encodeBlockNumber(from))
+	defer it.Release()
+
+	var numbers []uint64
+	for it.Next() && len(numbers) < limit {
+		key := it.Key()
+		if len(key) != len(addressTxUntraced)+8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(addressTxUntraced):])
+		if number > to {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, it.Error()
+}
+
 // WriteBloomBits writes the compressed bloom bits vector belonging to the given
 // section and bit index.
 func WriteBloomBits(db ethdb.KeyValueWriter, bit uint, section uint64, head common.Hash, bits []byte) {
@@ -546,9 +733,11 @@ func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteTd(db, hash, number)
 }
 
-// DeleteBlockReceipts removes all receipt data associated with a block hash.
+// DeleteBlockReceipts removes all receipt data associated with a block hash,
+// along with the revert data of its transactions.
 func DeleteBlockReceipts(db DatabaseDeleter, hash common.Hash, number uint64) {
 	db.Delete(append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+	db.Delete(append(append(revertReasonPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
 }
 
 // DeleteTxLookupEntry removes all transaction data associated with a hash.
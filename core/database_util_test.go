@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"github.com/tomochain/tomochain/core/rawdb"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/tomochain/tomochain/common"
@@ -253,6 +254,7 @@ func TestHeadStorage(t *testing.T) {
 	blockHead := types.NewBlockWithHeader(&types.Header{Extra: []byte("test block header")})
 	blockFull := types.NewBlockWithHeader(&types.Header{Extra: []byte("test block full")})
 	blockFast := types.NewBlockWithHeader(&types.Header{Extra: []byte("test block fast")})
+	blockFinal := types.NewBlockWithHeader(&types.Header{Extra: []byte("test block finalized")})
 
 	// Check that no head entries are in a pristine database
 	if entry := GetHeadHeaderHash(db); entry != (common.Hash{}) {
@@ -264,6 +266,9 @@ func TestHeadStorage(t *testing.T) {
 	if entry := GetHeadFastBlockHash(db); entry != (common.Hash{}) {
 		t.Fatalf("Non fast head block entry returned: %v", entry)
 	}
+	if entry := GetHeadFinalizedBlockHash(db); entry != (common.Hash{}) {
+		t.Fatalf("Non finalized head block entry returned: %v", entry)
+	}
 	// Assign separate entries for the head header and block
 	if err := WriteHeadHeaderHash(db, blockHead.Hash()); err != nil {
 		t.Fatalf("Failed to write head header hash: %v", err)
@@ -274,6 +279,9 @@ func TestHeadStorage(t *testing.T) {
 	if err := WriteHeadFastBlockHash(db, blockFast.Hash()); err != nil {
 		t.Fatalf("Failed to write fast head block hash: %v", err)
 	}
+	if err := WriteHeadFinalizedBlockHash(db, blockFinal.Hash()); err != nil {
+		t.Fatalf("Failed to write finalized head block hash: %v", err)
+	}
 	// Check that both heads are present, and different (i.e. two heads maintained)
 	if entry := GetHeadHeaderHash(db); entry != blockHead.Hash() {
 		t.Fatalf("Head header hash mismatch: have %v, want %v", entry, blockHead.Hash())
@@ -284,6 +292,9 @@ func TestHeadStorage(t *testing.T) {
 	if entry := GetHeadFastBlockHash(db); entry != blockFast.Hash() {
 		t.Fatalf("Fast head block hash mismatch: have %v, want %v", entry, blockFast.Hash())
 	}
+	if entry := GetHeadFinalizedBlockHash(db); entry != blockFinal.Hash() {
+		t.Fatalf("Finalized head block hash mismatch: have %v, want %v", entry, blockFinal.Hash())
+	}
 }
 
 // Tests that positional lookup metadata can be stored and retrieved.
@@ -386,3 +397,109 @@ func TestBlockReceiptStorage(t *testing.T) {
 		t.Fatalf("deleted receipts returned: %v", rs)
 	}
 }
+
+// Tests that the address index entries and untraced blocks of a section can be
+// stored, looked up page by page and rolled back.
+func TestAddressTxStorage(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	alice, bob := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	entry := func(addr common.Address, number uint64, index uint32, kinds uint8) AddressTxEntry {
+		return AddressTxEntry{
+			Address:   addr,
+			Number:    number,
+			Index:     index,
+			BlockHash: common.BigToHash(new(big.Int).SetUint64(number)),
+			TxHash:    common.BigToHash(new(big.Int).SetUint64(number<<8 | uint64(index))),
+			Kinds:     kinds,
+		}
+	}
+	first := []AddressTxEntry{entry(alice, 1, 0, 1), entry(bob, 1, 0, 2), entry(alice, 3, 2, 4)}
+	second := []AddressTxEntry{entry(alice, 4, 0, 8), entry(alice, 256, 1, 16)}
+
+	if err := WriteAddressTxSection(db, 0, first, map[uint64]common.Hash{2: {0x02}, 3: {0x03}}); err != nil {
+		t.Fatalf("failed to write section 0: %v", err)
+	}
+	if err := WriteAddressTxSection(db, 1, second, map[uint64]common.Hash{300: {0x01}}); err != nil {
+		t.Fatalf("failed to write section 1: %v", err)
+	}
+	check := func(addr common.Address, from, to uint64, skip, limit int, want []AddressTxEntry) {
+		t.Helper()
+		have, err := GetAddressTxEntries(db, addr, from, to, skip, limit)
+		if err != nil {
+			t.Fatalf("failed to retrieve entries: %v", err)
+		}
+		if len(have) != len(want) {
+			t.Fatalf("entry count mismatch: have %d, want %d", len(have), len(want))
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				t.Errorf("entry %d mismatch: have %+v, want %+v", i, have[i], want[i])
+			}
+		}
+	}
+	check(alice, 0, 1000, 0, 10, []AddressTxEntry{first[0], first[2], second[0], second[1]})
+	check(alice, 2, 4, 0, 10, []AddressTxEntry{first[2], second[0]})
+	check(alice, 0, 1000, 1, 2, []AddressTxEntry{first[2], second[0]})
+	check(bob, 0, 1000, 0, 10, []AddressTxEntry{first[1]})
+	check(common.HexToAddress("0x03"), 0, 1000, 0, 10, nil)
+
+	checkUntraced := func(from, to uint64, limit int, want []uint64) {
+		t.Helper()
+		have, err := GetAddressTxUntraced(db, from, to, limit)
+		if err != nil {
+			t.Fatalf("failed to retrieve untraced blocks: %v", err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("untraced blocks mismatch: have %v, want %v", have, want)
+		}
+	}
+	checkUntraced(0, 1000, 10, []uint64{2, 3, 300})
+	checkUntraced(3, 299, 10, []uint64{3})
+	checkUntraced(0, 1000, 2, []uint64{2, 3})
+
+	// Roll the first section back, the second one should be untouched
+	if err := DeleteAddressTxSection(db, 0); err != nil {
+		t.Fatalf("failed to delete section 0: %v", err)
+	}
+	check(alice, 0, 1000, 0, 10, second)
+	check(bob, 0, 1000, 0, 10, nil)
+	checkUntraced(0, 1000, 10, []uint64{300})
+}
+
+// Tests that only the revert data of failed transactions is stored, and that
+// it's deleted along with the receipts of the block.
+func TestRevertReasonStorage(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	failed := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: common.HexToHash("0x01"), RevertReason: []byte{0x08, 0xc3, 0x79, 0xa0}}
+	silent := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: common.HexToHash("0x02")}
+	success := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: common.HexToHash("0x03"), RevertReason: []byte{0x01}}
+
+	hash := common.HexToHash("0x10")
+	if err := WriteBlockRevertReasons(db, hash, 1, types.Receipts{silent, failed, success}); err != nil {
+		t.Fatalf("failed to write revert reasons: %v", err)
+	}
+	reasons := GetBlockRevertReasons(db, hash, 1)
+	if len(reasons) != 3 {
+		t.Fatalf("revert reason count mismatch: have %d, want 3", len(reasons))
+	}
+	if !bytes.Equal(reasons[1], failed.RevertReason) {
+		t.Errorf("revert reason mismatch: have %x, want %x", reasons[1], failed.RevertReason)
+	}
+	if len(reasons[0]) != 0 || len(reasons[2]) != 0 {
+		t.Errorf("unexpected revert reasons: %x, %x", reasons[0], reasons[2])
+	}
+	// Blocks without revert data don't store any
+	other := common.HexToHash("0x20")
+	if err := WriteBlockRevertReasons(db, other, 1, types.Receipts{silent, success}); err != nil {
+		t.Fatalf("failed to write revert reasons: %v", err)
+	}
+	if reasons := GetBlockRevertReasons(db, other, 1); reasons != nil {
+		t.Errorf("unexpected revert reasons: %x", reasons)
+	}
+	DeleteBlockReceipts(db, hash, 1)
+	if reasons := GetBlockRevertReasons(db, hash, 1); reasons != nil {
+		t.Errorf("revert reasons not deleted with the receipts: %x", reasons)
+	}
+}
@@ -58,3 +58,7 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// FinalizedHeadEvent is posted when a new block has been signed by more than
+// common.FinalityThresholdPercent of its masternodes.
+type FinalizedHeadEvent struct{ Block *types.Block }
@@ -0,0 +1,198 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/event"
+	"github.com/tomochain/tomochain/log"
+)
+
+// finalitySignersCacheLimit is the number of signed blocks for which the
+// signers collected so far are kept in memory.
+const finalitySignersCacheLimit = 900
+
+// finalityEngine is the consensus engine a block is finalized by, the PoSV one,
+// whose masternodes sign the blocks.
+type finalityEngine interface {
+	GetMasternodes(chain consensus.ChainReader, header *types.Header) []common.Address
+	RecoverSigner(header *types.Header) (common.Address, error)
+	RecoverValidator(header *types.Header) (common.Address, error)
+}
+
+// CurrentFinalizedBlock retrieves the most recent canonical block which has been
+// signed by more than common.FinalityThresholdPercent of its masternodes. It
+// returns nil if no block has been finalized yet.
+func (bc *BlockChain) CurrentFinalizedBlock() *types.Block {
+	block, _ := bc.currentFinalizedBlock.Load().(*types.Block)
+	return block
+}
+
+// SubscribeFinalizedHeadEvent registers a subscription of FinalizedHeadEvent.
+func (bc *BlockChain) SubscribeFinalizedHeadEvent(ch chan<- FinalizedHeadEvent) event.Subscription {
+	return bc.scope.Track(bc.finalizedHeadFeed.Subscribe(ch))
+}
+
+// loadFinalizedHead restores the finalized head from the database, discarding
+// it if it is no longer part of the canonical chain below the current head.
+func (bc *BlockChain) loadFinalizedHead(currentBlock *types.Block) {
+	bc.finalityMu.Lock()
+	defer bc.finalityMu.Unlock()
+
+	bc.currentFinalizedBlock.Store((*types.Block)(nil))
+
+	hash := GetHeadFinalizedBlockHash(bc.db)
+	if hash == (common.Hash{}) {
+		return
+	}
+	block := bc.GetBlockByHash(hash)
+	if block == nil || block.NumberU64() > currentBlock.NumberU64() || GetCanonicalHash(bc.db, block.NumberU64()) != hash {
+		return
+	}
+	bc.currentFinalizedBlock.Store(block)
+	headFinalizedBlockGauge.Update(int64(block.NumberU64()))
+}
+
+// recoverFinality collects again the signers of the blocks above the finalized
+// head from the block signing transactions of the recent canonical blocks, as
+// they're only kept in memory, so that finality resumes right after a restart.
+func (bc *BlockChain) recoverFinality(head *types.Block) {
+	if _, ok := bc.engine.(finalityEngine); !ok {
+		return
+	}
+	from := uint64(1)
+	if head.NumberU64() > finalitySignersCacheLimit {
+		from = head.NumberU64() - finalitySignersCacheLimit + 1
+	}
+	if finalized := bc.CurrentFinalizedBlock(); finalized != nil && finalized.NumberU64() >= from {
+		from = finalized.NumberU64() + 1
+	}
+	for number := from; number <= head.NumberU64(); number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			continue
+		}
+		bc.updateFinality(block, GetBlockReceipts(bc.db, block.Hash(), number))
+	}
+}
+
+// updateFinality records the block signing transactions carried by a newly
+// imported canonical block and advances the finalized head if one of the signed
+// blocks crossed the finality threshold. The returned event is nil if the
+// finalized head did not move. The signer sets are only accessed under
+// finalityMu, as receipt chains are imported concurrently with blocks.
+func (bc *BlockChain) updateFinality(block *types.Block, receipts types.Receipts) *FinalizedHeadEvent {
+	engine, ok := bc.engine.(finalityEngine)
+	if !ok {
+		return nil
+	}
+	bc.finalityMu.Lock()
+	defer bc.finalityMu.Unlock()
+
+	failed := make(map[common.Hash]bool)
+	for _, receipt := range receipts {
+		if len(receipt.PostState) == 0 && receipt.Status == types.ReceiptStatusFailed {
+			failed[receipt.TxHash] = true
+		}
+	}
+	var (
+		signer  = types.MakeSigner(bc.chainConfig, block.Number())
+		touched = make(map[common.Hash]struct{})
+	)
+	for _, tx := range block.Transactions() {
+		if !tx.IsSigningTransaction() || failed[tx.Hash()] {
+			continue
+		}
+		data := tx.Data()
+		if len(data) < common.HashLength {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		signed := common.BytesToHash(data[len(data)-common.HashLength:])
+		signers, ok := bc.finalitySigners.Get(signed)
+		if !ok {
+			signers = make(map[common.Address]struct{})
+			bc.finalitySigners.Add(signed, signers)
+		}
+		signers.(map[common.Address]struct{})[from] = struct{}{}
+		touched[signed] = struct{}{}
+	}
+	var finalized *types.Header
+	for hash := range touched {
+		header := bc.GetHeaderByHash(hash)
+		if header == nil || GetCanonicalHash(bc.db, header.Number.Uint64()) != hash {
+			continue
+		}
+		if current := bc.CurrentFinalizedBlock(); current != nil && header.Number.Uint64() <= current.NumberU64() {
+			continue
+		}
+		if finalized != nil && header.Number.Cmp(finalized.Number) <= 0 {
+			continue
+		}
+		if bc.isFinalized(engine, header) {
+			finalized = header
+		}
+	}
+	if finalized == nil {
+		return nil
+	}
+	finalizedBlock := bc.GetBlock(finalized.Hash(), finalized.Number.Uint64())
+	if finalizedBlock == nil {
+		return nil
+	}
+	if err := WriteHeadFinalizedBlockHash(bc.db, finalizedBlock.Hash()); err != nil {
+		log.Crit("Failed to insert head finalized block hash", "err", err)
+	}
+	bc.currentFinalizedBlock.Store(finalizedBlock)
+	headFinalizedBlockGauge.Update(int64(finalizedBlock.NumberU64()))
+
+	log.Debug("New finalized block", "number", finalizedBlock.Number(), "hash", finalizedBlock.Hash())
+	return &FinalizedHeadEvent{Block: finalizedBlock}
+}
+
+// isFinalized reports whether more than common.FinalityThresholdPercent of the
+// masternodes of the given header's epoch have signed it. The creator and the
+// validator of a block are counted as signers of it.
+func (bc *BlockChain) isFinalized(engine finalityEngine, header *types.Header) bool {
+	masternodes := engine.GetMasternodes(bc, header)
+	if len(masternodes) == 0 {
+		return false
+	}
+	signers := make(map[common.Address]struct{})
+	if cached, ok := bc.finalitySigners.Get(header.Hash()); ok {
+		for addr := range cached.(map[common.Address]struct{}) {
+			signers[addr] = struct{}{}
+		}
+	}
+	if creator, err := engine.RecoverSigner(header); err == nil {
+		signers[creator] = struct{}{}
+	}
+	if validator, err := engine.RecoverValidator(header); err == nil {
+		signers[validator] = struct{}{}
+	}
+	count := 0
+	for _, masternode := range masternodes {
+		if _, ok := signers[masternode]; ok {
+			count++
+		}
+	}
+	return count*100 > len(masternodes)*common.FinalityThresholdPercent
+}
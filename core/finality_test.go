@@ -0,0 +1,252 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus"
+	"github.com/tomochain/tomochain/consensus/ethash"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
+)
+
+// finalityTestEngine is a fake engine whose blocks are created by their
+// coinbase, out of a fixed set of masternodes.
+type finalityTestEngine struct {
+	consensus.Engine
+	masternodes []common.Address
+}
+
+func (e *finalityTestEngine) GetMasternodes(chain consensus.ChainReader, header *types.Header) []common.Address {
+	return e.masternodes
+}
+
+func (e *finalityTestEngine) RecoverSigner(header *types.Header) (common.Address, error) {
+	return header.Coinbase, nil
+}
+
+func (e *finalityTestEngine) RecoverValidator(header *types.Header) (common.Address, error) {
+	return common.Address{}, errors.New("no validator")
+}
+
+type finalityTestSign struct {
+	key    *ecdsa.PrivateKey
+	block  *types.Block
+	failed bool
+}
+
+// Tests that blocks are finalized once more than FinalityThresholdPercent of
+// the masternodes signed them, on the fast sync path too, and that the signers
+// collected so far survive a restart.
+func TestFinality(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = GenesisBlockForTesting(db, common.Address{}, big.NewInt(0))
+		config  = params.TestChainConfig
+		signer  = types.MakeSigner(config, big.NewInt(1))
+
+		keys        []*ecdsa.PrivateKey
+		masternodes []common.Address
+	)
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		masternodes = append(masternodes, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	outsider, _ := crypto.GenerateKey()
+	engine := &finalityTestEngine{Engine: ethash.NewFullFaker(), masternodes: masternodes}
+
+	// makeBlock creates a block of masternode 0 carrying the signing transactions
+	makeBlock := func(parent *types.Block, signs []finalityTestSign) (*types.Block, types.Receipts) {
+		var (
+			txs      types.Transactions
+			receipts types.Receipts
+		)
+		for i, sign := range signs {
+			data := append(common.Hex2Bytes(common.HexSignMethod), common.LeftPadBytes(sign.block.Number().Bytes(), 32)...)
+			data = append(data, sign.block.Hash().Bytes()...)
+			tx, err := types.SignTx(types.NewTransaction(uint64(i), common.HexToAddress(common.BlockSigners), new(big.Int), 200000, new(big.Int), data), signer, sign.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txs = append(txs, tx)
+			receipts = append(receipts, types.NewReceipt(nil, sign.failed, uint64(i+1)*21000))
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number(), common.Big1),
+			Coinbase:   masternodes[0],
+			Difficulty: common.Big1,
+			GasLimit:   parent.GasLimit(),
+			Time:       new(big.Int).Add(parent.Time(), common.Big1),
+			Root:       parent.Root(),
+		}
+		return types.NewBlock(header, txs, nil, receipts), receipts
+	}
+	// insert imports the blocks the way fast sync does
+	insert := func(bc *BlockChain, blocks types.Blocks, receipts []types.Receipts) {
+		headers := make([]*types.Header, len(blocks))
+		for i, block := range blocks {
+			headers[i] = block.Header()
+		}
+		if _, err := bc.InsertHeaderChain(headers, 1); err != nil {
+			t.Fatalf("failed to insert headers: %v", err)
+		}
+		if _, err := bc.InsertReceiptChain(blocks, receipts); err != nil {
+			t.Fatalf("failed to insert receipts: %v", err)
+		}
+	}
+
+	bc, err := NewBlockChain(db, nil, config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		blocks   types.Blocks
+		receipts []types.Receipts
+		parent   = genesis
+	)
+	for i := 0; i < 3; i++ {
+		block, blockReceipts := makeBlock(parent, nil)
+		blocks, receipts, parent = append(blocks, block), append(receipts, blockReceipts), block
+	}
+	// Block 1 is signed by all the masternodes, block 2 by too few of them, as an
+	// outsider and a failed signing transaction don't count
+	block, blockReceipts := makeBlock(parent, []finalityTestSign{
+		{key: keys[1], block: blocks[0]},
+		{key: keys[2], block: blocks[0]},
+		{key: keys[3], block: blocks[0]},
+		{key: keys[1], block: blocks[1]},
+		{key: outsider, block: blocks[1]},
+		{key: keys[2], block: blocks[1], failed: true},
+	})
+	blocks, receipts = append(blocks, block), append(receipts, blockReceipts)
+	insert(bc, blocks, receipts)
+
+	if finalized := bc.CurrentFinalizedBlock(); finalized == nil || finalized.Hash() != blocks[0].Hash() {
+		t.Fatalf("finalized block mismatch: have %v, want %v", finalized, blocks[0].Number())
+	}
+	if hash := GetHeadFinalizedBlockHash(db); hash != blocks[0].Hash() {
+		t.Fatalf("persisted finalized block mismatch: have %x, want %x", hash, blocks[0].Hash())
+	}
+	if bc.isFinalized(engine, blocks[1].Header()) {
+		t.Fatalf("block 2 finalized by too few masternodes")
+	}
+	bc.Stop()
+
+	// After a restart, the finalized head and the signers of block 2 are back
+	bc, err = NewBlockChain(db, nil, config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+	if finalized := bc.CurrentFinalizedBlock(); finalized == nil || finalized.Hash() != blocks[0].Hash() {
+		t.Fatalf("restored finalized block mismatch: have %v, want %v", finalized, blocks[0].Number())
+	}
+	events := make(chan FinalizedHeadEvent, 1)
+	sub := bc.SubscribeFinalizedHeadEvent(events)
+	defer sub.Unsubscribe()
+
+	block, blockReceipts = makeBlock(blocks[3], []finalityTestSign{
+		{key: keys[2], block: blocks[1]},
+		{key: keys[3], block: blocks[1]},
+	})
+	insert(bc, types.Blocks{block}, []types.Receipts{blockReceipts})
+
+	if finalized := bc.CurrentFinalizedBlock(); finalized == nil || finalized.Hash() != blocks[1].Hash() {
+		t.Fatalf("finalized block mismatch: have %v, want %v", finalized, blocks[1].Number())
+	}
+	select {
+	case ev := <-events:
+		if ev.Block.Hash() != blocks[1].Hash() {
+			t.Fatalf("finalized head event mismatch: have %v, want %v", ev.Block.Number(), blocks[1].Number())
+		}
+	default:
+		t.Fatalf("no finalized head event")
+	}
+}
+
+// Tests that the blocks signed from a side chain are finalized once it becomes
+// the canonical one.
+func TestFinalityReorg(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = GenesisBlockForTesting(db, common.Address{}, big.NewInt(0))
+		config  = params.TestChainConfig
+		signer  = types.MakeSigner(config, big.NewInt(1))
+
+		keys        []*ecdsa.PrivateKey
+		masternodes []common.Address
+	)
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		masternodes = append(masternodes, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	engine := &finalityTestEngine{Engine: ethash.NewFaker(), masternodes: masternodes}
+
+	bc, err := NewBlockChain(db, nil, config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+
+	chain, _ := GenerateChain(config, genesis, engine, db, 3, func(i int, b *BlockGen) {
+		b.SetCoinbase(masternodes[0])
+	})
+	// The side chain forks off block 2 and signs block 1 in its first block
+	fork, _ := GenerateChain(config, chain[1], engine, db, 2, func(i int, b *BlockGen) {
+		b.SetCoinbase(masternodes[0])
+		if i != 0 {
+			return
+		}
+		for _, key := range keys[1:] {
+			data := append(common.Hex2Bytes(common.HexSignMethod), common.LeftPadBytes(chain[0].Number().Bytes(), 32)...)
+			data = append(data, chain[0].Hash().Bytes()...)
+			tx, err := types.SignTx(types.NewTransaction(0, common.HexToAddress(common.BlockSigners), new(big.Int), 200000, new(big.Int), data), signer, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.AddTxWithChain(bc, tx)
+		}
+	})
+	if _, err := bc.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert the chain: %v", err)
+	}
+	if _, err := bc.InsertChain(fork[:1]); err != nil {
+		t.Fatalf("failed to insert the side block: %v", err)
+	}
+	if finalized := bc.CurrentFinalizedBlock(); finalized != nil {
+		t.Fatalf("block %v finalized from a side chain", finalized.Number())
+	}
+	if _, err := bc.InsertChain(fork[1:]); err != nil {
+		t.Fatalf("failed to insert the reorging block: %v", err)
+	}
+	if head := bc.CurrentBlock(); head.Hash() != fork[1].Hash() {
+		t.Fatalf("head mismatch: have %v, want %v", head.Number(), fork[1].Number())
+	}
+	if finalized := bc.CurrentFinalizedBlock(); finalized == nil || finalized.Hash() != chain[0].Hash() {
+		t.Fatalf("finalized block mismatch: have %v, want %v", finalized, chain[0].Number())
+	}
+}
@@ -559,7 +559,7 @@ func (pool *LendingPool) validateBalance(cloneStateDb *state.StateDB, cloneLendi
 		if tx.LendingToken().String() == common.TomoNativeAddress {
 			lendTokenTOMOPrice = common.BasePrice
 		} else {
-			lendTokenTOMOPrice, err = lendingServ.GetMediumTradePriceBeforeEpoch(pool.chain, cloneStateDb, cloneTradingStateDb, tx.LendingToken(), common.HexToAddress(common.TomoNativeAddress))
+			lendTokenTOMOPrice, err = lendingServ.GetMediumTradePriceBeforeEpoch(pool.chain.CurrentHeader(), pool.chain, cloneStateDb, cloneTradingStateDb, tx.LendingToken(), common.HexToAddress(common.TomoNativeAddress))
 			if err != nil {
 				return err
 			}
@@ -619,13 +619,34 @@ func (pool *LendingPool) validateLending(tx *types.LendingTransaction) error {
 	return ErrInvalidLendingStatus
 }
 
+// checkBlacklist rejects the lending transactions of black-listed senders. Past
+// the black-list fork the black-list contract is read from the state of the
+// current head, the parent of the next block.
+func (pool *LendingPool) checkBlacklist(from *common.Address) error {
+	var (
+		head        = pool.chain.CurrentBlock()
+		next        = new(big.Int).Add(head.Number(), common.Big1)
+		parentState *state.StateDB
+		err         error
+	)
+	if pool.chainconfig.IsBlacklist(next) {
+		if parentState, err = pool.chain.StateAt(head.Root()); err != nil {
+			return err
+		}
+	}
+	if err := CheckBlacklist(pool.chainconfig, parentState, next, from, nil); err != nil {
+		return fmt.Errorf("Reject %v", err)
+	}
+	return nil
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *LendingPool) validateTx(tx *types.LendingTransaction, local bool) error {
 
 	// check if sender is in black list
-	if tx.From() != nil && common.Blacklist[*tx.From()] {
-		return fmt.Errorf("Reject transaction with sender in black-list: %v", tx.From().Hex())
+	if err := pool.checkBlacklist(tx.From()); err != nil {
+		return err
 	}
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
 	if tx.Size() > 32*1024 {
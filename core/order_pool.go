@@ -479,7 +479,10 @@ func (pool *OrderPool) validateOrder(tx *types.OrderTransaction) error {
 			if err != nil {
 				return fmt.Errorf("validateOrder: failed to get quoteDecimal. err: %v", err)
 			}
-			if err := tradingstate.VerifyBalance(cloneStateDb, cloneTomoXStateDb, tx, baseDecimal, quoteDecimal); err != nil {
+			// The order is matched in the next block at the earliest
+			nextBlock := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+			isTomoXMakerTakerFeeFork := pool.chainconfig.IsTomoXMakerTakerFee(nextBlock)
+			if err := tradingstate.VerifyBalance(isTomoXMakerTakerFeeFork, cloneStateDb, cloneTomoXStateDb, tx, baseDecimal, quoteDecimal); err != nil {
 				return err
 			}
 		}
@@ -527,13 +530,34 @@ func (pool *OrderPool) validateOrder(tx *types.OrderTransaction) error {
 	return nil
 }
 
+// checkBlacklist rejects the order transactions of black-listed senders. Past
+// the black-list fork the black-list contract is read from the state of the
+// current head, the parent of the next block.
+func (pool *OrderPool) checkBlacklist(from *common.Address) error {
+	var (
+		head        = pool.chain.CurrentBlock()
+		next        = new(big.Int).Add(head.Number(), common.Big1)
+		parentState *state.StateDB
+		err         error
+	)
+	if pool.chainconfig.IsBlacklist(next) {
+		if parentState, err = pool.chain.StateAt(head.Root()); err != nil {
+			return err
+		}
+	}
+	if err := CheckBlacklist(pool.chainconfig, parentState, next, from, nil); err != nil {
+		return fmt.Errorf("Reject %v", err)
+	}
+	return nil
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *OrderPool) validateTx(tx *types.OrderTransaction, local bool) error {
 
 	// check if sender is in black list
-	if tx.From() != nil && common.Blacklist[*tx.From()] {
-		return fmt.Errorf("Reject transaction with sender in black-list: %v", tx.From().Hex())
+	if err := pool.checkBlacklist(tx.From()); err != nil {
+		return err
 	}
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
 	if tx.Size() > 32*1024 {
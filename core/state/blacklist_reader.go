@@ -0,0 +1,21 @@
+package state
+
+import (
+	"github.com/tomochain/tomochain/common"
+)
+
+// SlotBlacklist is the storage layout of the black-list contract.
+var SlotBlacklist = map[string]uint64{
+	"owner":       0,
+	"blacklisted": 1,
+}
+
+// IsBlacklisted reports whether an address is black-listed in the state of the
+// black-list contract.
+func IsBlacklisted(statedb *StateDB, addr common.Address) bool {
+	if statedb == nil {
+		return false
+	}
+	key := GetLocMappingAtKey(addr.Hash(), SlotBlacklist["blacklisted"])
+	return !common.EmptyHash(statedb.GetState(common.HexToAddress(common.BlacklistSMC), common.BigToHash(key)))
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/crypto"
 	"github.com/tomochain/tomochain/metrics"
 	"github.com/tomochain/tomochain/rlp"
@@ -236,6 +237,24 @@ func (self *stateObject) setState(key, value common.Hash) {
 	}
 }
 
+// SetStorage replaces the entire storage of the account with the given slots,
+// discarding the storage trie. The change isn't journaled, so it can't be
+// reverted to a snapshot: it may only be used before any execution starts on
+// the state, e.g. to apply the state overrides of a call.
+func (self *stateObject) SetStorage(storage map[common.Hash]common.Hash) {
+	self.data.Root = types.EmptyRootHash
+	self.trie = nil
+	self.cachedStorage = make(Storage)
+	self.dirtyStorage = make(Storage)
+	for key, value := range storage {
+		self.setState(key, value)
+	}
+	if self.onDirty != nil {
+		self.onDirty(self.Address())
+		self.onDirty = nil
+	}
+}
+
 // updateTrie writes cached storage modifications into the object's storage trie.
 func (self *stateObject) updateTrie(db Database) Trie {
 	// Track the amount of time wasted on updating the storage trie
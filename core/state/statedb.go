@@ -33,6 +33,17 @@ import (
 	"github.com/tomochain/tomochain/trie"
 )
 
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
 type revision struct {
 	id           int
 	journalIndex int
@@ -76,6 +87,12 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Transient storage (EIP-1153), discarded at the end of every transaction.
+	transientStorage transientStorage
+
+	// Addresses and storage slots accessed by the current transaction (EIP-2929).
+	accessList *accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        journal
@@ -130,6 +147,8 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		stateObjectsDirty: make(map[common.Address]struct{}),
 		logs:              make(map[common.Hash][]*types.Log),
 		preimages:         make(map[common.Hash][]byte),
+		transientStorage:  newTransientStorage(),
+		accessList:        newAccessList(),
 	}, nil
 }
 
@@ -293,6 +312,24 @@ func (self *StateDB) StorageTrie(addr common.Address) Trie {
 	return cpy.updateTrie(self.db)
 }
 
+// GetProof returns the Merkle proof for a given account.
+func (self *StateDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := self.trie.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
+// GetStorageProof returns the Merkle proof for given storage slot.
+func (self *StateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	var proof proofList
+	trie := self.StorageTrie(addr)
+	if trie == nil {
+		return proof, fmt.Errorf("storage trie for requested address does not exist")
+	}
+	err := trie.Prove(crypto.Keccak256(key.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
 func (self *StateDB) HasSuicided(addr common.Address) bool {
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
@@ -349,6 +386,98 @@ func (self *StateDB) SetState(addr common.Address, key, value common.Hash) {
 	}
 }
 
+// SetStorage replaces the entire storage of the given account. It isn't
+// journaled, so it may only be used before any execution starts on the state.
+func (self *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetStorage(storage)
+	}
+}
+
+// GetTransientState retrieves a value from the transient storage of the given
+// account, as defined by EIP-1153.
+func (self *StateDB) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return self.transientStorage.Get(addr, key)
+}
+
+// SetTransientState sets a value in the transient storage of the given account.
+// The change is journaled so that it is undone when the call frame reverts.
+func (self *StateDB) SetTransientState(addr common.Address, key, value common.Hash) {
+	prev := self.GetTransientState(addr, key)
+	if prev == value {
+		return
+	}
+	self.journal = append(self.journal, transientStorageChange{
+		account:  &addr,
+		key:      key,
+		prevalue: prev,
+	})
+	self.setTransientState(addr, key, value)
+}
+
+// setTransientState is a lower level setter for transient storage. It is
+// called during a revert to prevent modifications to the journal.
+func (self *StateDB) setTransientState(addr common.Address, key, value common.Hash) {
+	self.transientStorage.Set(addr, key, value)
+}
+
+// PrepareAccessList resets the access list and warms up the sender, the
+// destination, the precompiles and the entries of the transaction's access
+// list, as required by EIP-2929 and EIP-2930.
+func (self *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	self.accessList = newAccessList()
+	self.AddAddressToAccessList(sender)
+	if dst != nil {
+		self.AddAddressToAccessList(*dst)
+		// If it's a create-tx, the destination will be added inside evm.create
+	}
+	for _, addr := range precompiles {
+		self.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		self.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			self.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list.
+func (self *StateDB) AddAddressToAccessList(addr common.Address) {
+	if self.accessList.AddAddress(addr) {
+		self.journal = append(self.journal, accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot) to the access list.
+func (self *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := self.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		self.journal = append(self.journal, accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		self.journal = append(self.journal, accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (self *StateDB) AddressInAccessList(addr common.Address) bool {
+	return self.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (self *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return self.accessList.Contains(addr, slot)
+}
+
 // Suicide marks the given account as suicided.
 // This clears the account balance.
 //
@@ -531,6 +660,8 @@ func (self *StateDB) Copy() *StateDB {
 		logs:              make(map[common.Hash][]*types.Log, len(self.logs)),
 		logSize:           self.logSize,
 		preimages:         make(map[common.Hash][]byte),
+		transientStorage:  self.transientStorage.Copy(),
+		accessList:        self.accessList.Copy(),
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.stateObjectsDirty {
@@ -619,6 +750,7 @@ func (self *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	self.thash = thash
 	self.bhash = bhash
 	self.txIndex = ti
+	self.transientStorage = newTransientStorage()
 }
 
 // DeleteSuicides flags the suicided objects for deletion so that it
@@ -646,6 +778,7 @@ func (s *StateDB) clearJournalAndRefund() {
 	s.journal = nil
 	s.validRevisions = s.validRevisions[:0]
 	s.refund = 0
+	s.transientStorage = newTransientStorage()
 }
 
 // Commit writes the state to the underlying in-memory trie database.
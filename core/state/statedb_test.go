@@ -33,6 +33,10 @@ import (
 
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/ethdb/memorydb"
+	"github.com/tomochain/tomochain/rlp"
+	"github.com/tomochain/tomochain/trie"
 )
 
 // Tests that updating a state trie does not leak any database writes prior to
@@ -427,3 +431,141 @@ func (s *StateSuite) TestTouchDelete(c *check.C) {
 		c.Fatal("expected no dirty state object")
 	}
 }
+
+func TestTransientStorage(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+
+	key := common.Hash{0x01}
+	value := common.Hash{0x02}
+	addr := common.Address{}
+
+	// Writes are journaled and undone on revert
+	revision := state.Snapshot()
+	state.SetTransientState(addr, key, value)
+	if got := state.GetTransientState(addr, key); got != value {
+		t.Fatalf("transient storage mismatch: have %x, want %x", got, value)
+	}
+	state.RevertToSnapshot(revision)
+	if got := state.GetTransientState(addr, key); got != (common.Hash{}) {
+		t.Fatalf("transient storage not reverted: have %x", got)
+	}
+	// Copies carry the transient storage over, independently of the original
+	state.SetTransientState(addr, key, value)
+	cpy := state.Copy()
+	cpy.SetTransientState(addr, key, common.Hash{0x03})
+	if got := state.GetTransientState(addr, key); got != value {
+		t.Fatalf("original transient storage modified by copy: have %x, want %x", got, value)
+	}
+	if got := cpy.GetTransientState(addr, key); got != (common.Hash{0x03}) {
+		t.Fatalf("copy transient storage mismatch: have %x", got)
+	}
+	// Transient storage is discarded at the end of the transaction
+	state.Finalise(true)
+	if got := state.GetTransientState(addr, key); got != (common.Hash{}) {
+		t.Fatalf("transient storage not discarded: have %x", got)
+	}
+}
+
+func TestStateDBAccessList(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+
+	var (
+		sender = common.Address{0xaa}
+		dst    = common.Address{0xbb}
+		other  = common.Address{0xcc}
+		slot   = common.Hash{0x01}
+	)
+	state.PrepareAccessList(sender, &dst, nil, types.AccessList{{Address: other, StorageKeys: []common.Hash{slot}}})
+	for _, addr := range []common.Address{sender, dst, other} {
+		if !state.AddressInAccessList(addr) {
+			t.Fatalf("address %x missing from access list", addr)
+		}
+	}
+	if addrOk, slotOk := state.SlotInAccessList(other, slot); !addrOk || !slotOk {
+		t.Fatalf("slot missing from access list: %v %v", addrOk, slotOk)
+	}
+	// Additions are journaled and undone on revert
+	revision := state.Snapshot()
+	state.AddAddressToAccessList(common.Address{0xdd})
+	state.AddSlotToAccessList(dst, slot)
+	if addrOk, slotOk := state.SlotInAccessList(dst, slot); !addrOk || !slotOk {
+		t.Fatalf("added slot missing from access list: %v %v", addrOk, slotOk)
+	}
+	state.RevertToSnapshot(revision)
+	if state.AddressInAccessList(common.Address{0xdd}) {
+		t.Fatal("address addition not reverted")
+	}
+	if addrOk, slotOk := state.SlotInAccessList(dst, slot); !addrOk || slotOk {
+		t.Fatalf("slot addition not reverted: %v %v", addrOk, slotOk)
+	}
+	// Copies are independent of the original
+	cpy := state.Copy()
+	cpy.AddAddressToAccessList(common.Address{0xee})
+	if state.AddressInAccessList(common.Address{0xee}) {
+		t.Fatal("original access list modified by copy")
+	}
+	// Preparing a new transaction resets the list
+	state.PrepareAccessList(sender, nil, nil, nil)
+	if state.AddressInAccessList(other) {
+		t.Fatal("access list not reset")
+	}
+}
+
+func TestStateDBProof(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(common.Hash{}, db)
+
+	addr := common.Address{0x01}
+	key, value := common.Hash{0x02}, common.Hash{0x03}
+	state.SetBalance(addr, big.NewInt(42))
+	state.SetState(addr, key, value)
+	for i := byte(0); i < 16; i++ {
+		state.SetBalance(common.Address{0x10, i}, big.NewInt(int64(i)+1))
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	state, _ = New(root, db)
+
+	toDb := func(proof [][]byte) *memorydb.Database {
+		proofDb := memorydb.New()
+		for _, node := range proof {
+			proofDb.Put(crypto.Keccak256(node), node)
+		}
+		return proofDb
+	}
+	// The account proof resolves to the account against the state root
+	proof, err := state.GetProof(addr)
+	if err != nil {
+		t.Fatalf("failed to create account proof: %v", err)
+	}
+	enc, err := trie.VerifyProof(root, crypto.Keccak256(addr.Bytes()), toDb(proof))
+	if err != nil {
+		t.Fatalf("failed to verify account proof: %v", err)
+	}
+	var account Account
+	if err := rlp.DecodeBytes(enc, &account); err != nil {
+		t.Fatalf("failed to decode proven account: %v", err)
+	}
+	if account.Balance.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("proven balance mismatch: have %v, want 42", account.Balance)
+	}
+	// The storage proof resolves to the slot against the account storage root
+	proof, err = state.GetStorageProof(addr, key)
+	if err != nil {
+		t.Fatalf("failed to create storage proof: %v", err)
+	}
+	enc, err = trie.VerifyProof(account.Root, crypto.Keccak256(key.Bytes()), toDb(proof))
+	if err != nil {
+		t.Fatalf("failed to verify storage proof: %v", err)
+	}
+	_, content, _, _ := rlp.Split(enc)
+	if common.BytesToHash(content) != value {
+		t.Errorf("proven slot mismatch: have %x, want %x", content, value)
+	}
+	// Missing accounts have no storage to prove
+	if _, err := state.GetStorageProof(common.Address{0xff}, key); err == nil {
+		t.Error("expected error for missing account storage proof")
+	}
+}
@@ -78,20 +78,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, tra
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb)
 	}
-	if common.TIPSigningBlock.Cmp(header.Number) == 0 {
-		statedb.DeleteAddress(common.HexToAddress(common.BlockSigners))
-	}
-	if p.config.IsAtlas(header.Number) {
-		misc.ApplyVIPVRC25Upgarde(statedb, p.config.AtlasBlock, header.Number)
-	}
-
-	if p.config.SaigonBlock != nil && p.config.SaigonBlock.Cmp(block.Number()) <= 0 {
-		if common.IsTestnet {
-			misc.ApplySaigonHardForkTestnet(statedb, p.config.SaigonBlock, block.Number(), p.config.Posv)
-		} else {
-			misc.ApplySaigonHardFork(statedb, p.config.SaigonBlock, block.Number())
-		}
-	}
+	ApplyForkState(p.config, statedb, header)
 	parentState := statedb.Copy()
 	InitSignerInTransactions(p.config, header, block.Transactions())
 
@@ -103,14 +90,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, tra
 
 	for i, tx := range block.Transactions() {
 		// check black-list txs after hf
-		if (block.Number().Uint64() >= common.BlackListHFBlock) && !common.IsTestnet {
-			// check if sender is in black list
-			if tx.From() != nil && common.Blacklist[*tx.From()] {
-				return nil, nil, 0, fmt.Errorf("Block contains transaction with sender in black-list: %v", tx.From().Hex())
-			}
-			// check if receiver is in black list
-			if tx.To() != nil && common.Blacklist[*tx.To()] {
-				return nil, nil, 0, fmt.Errorf("Block contains transaction with receiver in black-list: %v", tx.To().Hex())
+		if IsBlacklistEnforced(p.config, block.Number()) {
+			// check if sender or receiver is in black list
+			if err := CheckBlacklist(p.config, parentState, block.Number(), tx.From(), tx.To()); err != nil {
+				return nil, nil, 0, fmt.Errorf("Block contains %v", err)
 			}
 		}
 		// validate balance slot, minFee slot for TomoZ
@@ -168,20 +151,7 @@ func (p *StateProcessor) ProcessBlockNoValidator(cBlock *CalculatedBlock, stated
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb)
 	}
-	if common.TIPSigningBlock.Cmp(header.Number) == 0 {
-		statedb.DeleteAddress(common.HexToAddress(common.BlockSigners))
-	}
-	if p.config.IsAtlas(header.Number) {
-		misc.ApplyVIPVRC25Upgarde(statedb, p.config.AtlasBlock, header.Number)
-	}
-
-	if p.config.SaigonBlock != nil && p.config.SaigonBlock.Cmp(block.Number()) <= 0 {
-		if common.IsTestnet {
-			misc.ApplySaigonHardForkTestnet(statedb, p.config.SaigonBlock, block.Number(), p.config.Posv)
-		} else {
-			misc.ApplySaigonHardFork(statedb, p.config.SaigonBlock, block.Number())
-		}
-	}
+	ApplyForkState(p.config, statedb, header)
 	if cBlock.stop {
 		return nil, nil, 0, ErrStopPreparingBlock
 	}
@@ -202,14 +172,10 @@ func (p *StateProcessor) ProcessBlockNoValidator(cBlock *CalculatedBlock, stated
 	receipts = make([]*types.Receipt, block.Transactions().Len())
 	for i, tx := range block.Transactions() {
 		// check black-list txs after hf
-		if (block.Number().Uint64() >= common.BlackListHFBlock) && !common.IsTestnet {
-			// check if sender is in black list
-			if tx.From() != nil && common.Blacklist[*tx.From()] {
-				return nil, nil, 0, fmt.Errorf("Block contains transaction with sender in black-list: %v", tx.From().Hex())
-			}
-			// check if receiver is in black list
-			if tx.To() != nil && common.Blacklist[*tx.To()] {
-				return nil, nil, 0, fmt.Errorf("Block contains transaction with receiver in black-list: %v", tx.To().Hex())
+		if IsBlacklistEnforced(p.config, block.Number()) {
+			// check if sender or receiver is in black list
+			if err := CheckBlacklist(p.config, parentState, block.Number(), tx.From(), tx.To()); err != nil {
+				return nil, nil, 0, fmt.Errorf("Block contains %v", err)
 			}
 		}
 		// validate balance slot, minFee slot for TomoZ
@@ -455,7 +421,7 @@ func ApplyTransaction(config *params.ChainConfig, tokensFee map[common.Address]*
 	// End Bypass blacklist address
 
 	// Apply the transaction to the current state (included in the env)
-	_, gas, failed, err := ApplyMessage(vmenv, msg, gp, coinbaseOwner)
+	ret, gas, failed, err := ApplyMessage(vmenv, msg, gp, coinbaseOwner)
 	if err != nil {
 		return nil, 0, err, false
 	}
@@ -471,8 +437,12 @@ func ApplyTransaction(config *params.ChainConfig, tokensFee map[common.Address]*
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing wether the root touch-delete accounts.
 	receipt := types.NewReceipt(root, failed, *usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = gas
+	if failed && len(ret) > 0 {
+		receipt.RevertReason = ret
+	}
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
@@ -515,6 +485,7 @@ func ApplySignTransaction(config *params.ChainConfig, statedb *state.StateDB, he
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing wether the root touch-delete accounts.
 	receipt := types.NewReceipt(root, false, *usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = 0
 	// if the transaction created a contract, store the creation address in the receipt.
@@ -539,6 +510,7 @@ func ApplyEmptyTransaction(config *params.ChainConfig, statedb *state.StateDB, h
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing wether the root touch-delete accounts.
 	receipt := types.NewReceipt(root, false, *usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = 0
 	// if the transaction created a contract, store the creation address in the receipt.
@@ -552,6 +524,30 @@ func ApplyEmptyTransaction(config *params.ChainConfig, statedb *state.StateDB, h
 	return receipt, 0, nil, false
 }
 
+// ApplyForkState mutates the state of the block with the given header
+// according to the TomoChain hard-fork specs, before its transactions run.
+func ApplyForkState(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) {
+	if common.TIPSigningBlock.Cmp(header.Number) == 0 {
+		statedb.DeleteAddress(common.HexToAddress(common.BlockSigners))
+	}
+	if config.IsAtlas(header.Number) {
+		misc.ApplyVIPVRC25Upgarde(statedb, config.AtlasBlock, header.Number)
+	}
+	if config.IsBlacklist(header.Number) {
+		misc.ApplyBlacklistFork(statedb, config.BlacklistBlock, header.Number)
+	}
+	if config.IsTomoXMakerTakerFee(header.Number) {
+		misc.ApplyTomoXMakerTakerFeeFork(statedb, config.TomoXMakerTakerFeeBlock, header.Number)
+	}
+	if config.SaigonBlock != nil && config.SaigonBlock.Cmp(header.Number) <= 0 {
+		if common.IsTestnet {
+			misc.ApplySaigonHardForkTestnet(statedb, config.SaigonBlock, header.Number, config.Posv)
+		} else {
+			misc.ApplySaigonHardFork(statedb, config.SaigonBlock, header.Number)
+		}
+	}
+}
+
 func InitSignerInTransactions(config *params.ChainConfig, header *types.Header, txs types.Transactions) {
 	nWorker := runtime.NumCPU()
 	signer := types.MakeSigner(config, header.Number)
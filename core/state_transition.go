@@ -23,6 +23,7 @@ import (
 
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/core/vm"
 	"github.com/tomochain/tomochain/log"
 	"github.com/tomochain/tomochain/params"
@@ -61,6 +62,7 @@ type StateTransition struct {
 	data       []byte
 	state      vm.StateDB
 	evm        *vm.EVM
+	vmerr      error // error of the EVM execution, if any
 }
 
 // Message represents a message sent to a contract.
@@ -76,11 +78,13 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 	BalanceTokenFee() *big.Int
 }
 
-// IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error) {
+// IntrinsicGas computes the 'intrinsic gas' for a message with the given data
+// and access list.
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation, homestead, isEIP3860 bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation && homestead {
@@ -108,6 +112,18 @@ func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error)
 			return 0, vm.ErrOutOfGas
 		}
 		gas += z * params.TxDataZeroGas
+
+		if contractCreation && isEIP3860 {
+			lenWords := toWordSize(uint64(len(data)))
+			if (math.MaxUint64-gas)/params.InitCodeWordGasEIP3860 < lenWords {
+				return 0, vm.ErrOutOfGas
+			}
+			gas += lenWords * params.InitCodeWordGasEIP3860
+		}
+	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
 	}
 	return gas, nil
 }
@@ -136,6 +152,12 @@ func ApplyMessage(evm *vm.EVM, msg Message, gp *GasPool, owner common.Address) (
 	return NewStateTransition(evm, msg, gp).TransitionDb(owner)
 }
 
+// VMError returns the error the EVM execution of the message failed with, like
+// vm.ErrExecutionReverted, after TransitionDb ran.
+func (st *StateTransition) VMError() error {
+	return st.vmerr
+}
+
 func (st *StateTransition) from() vm.AccountRef {
 	f := st.msg.From()
 	if !st.state.Exist(f) {
@@ -260,6 +282,13 @@ func (st *StateTransition) preCheck(useAtlasRule bool) (bool, error) {
 // returning the result including the the used gas. It returns an error if it
 // failed. An error indicates a consensus issue.
 func (st *StateTransition) TransitionDb(owner common.Address) (ret []byte, usedGas uint64, failed bool, err error) {
+	// Let transaction level tracers see the state before any gas is bought
+	if cfg := st.evm.Config(); cfg.Debug {
+		if tracer, ok := cfg.Tracer.(vm.TxTracer); ok {
+			tracer.CaptureTxStart(st.evm, st.msg.From(), st.msg.To())
+			defer func() { tracer.CaptureTxEnd(st.gas) }()
+		}
+	}
 	currentBlock := st.evm.Context.BlockNumber
 	isAtlas := st.evm.ChainConfig().IsAtlas(currentBlock)
 	var isUsedTokenFee bool
@@ -270,10 +299,16 @@ func (st *StateTransition) TransitionDb(owner common.Address) (ret []byte, usedG
 	sender := st.from() // err checked in preCheck
 
 	homestead := st.evm.ChainConfig().IsHomestead(st.evm.BlockNumber)
+	cancun := st.evm.ChainConfig().IsCancun(st.evm.BlockNumber)
 	contractCreation := msg.To() == nil
 
+	// Check whether the init code size has been exceeded.
+	if cancun && contractCreation && len(st.data) > params.MaxInitCodeSize {
+		return nil, 0, false, ErrMaxInitCodeSizeExceeded
+	}
+
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation, homestead)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, homestead, cancun)
 	if err != nil {
 		return nil, 0, false, err
 	}
@@ -281,6 +316,11 @@ func (st *StateTransition) TransitionDb(owner common.Address) (ret []byte, usedG
 		return nil, 0, false, err
 	}
 
+	// Warm up the sender, the destination, the precompiles and the access list
+	if rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber); rules.IsEIP2929 {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	}
+
 	var (
 		evm = st.evm
 		// vm errors do not effect consensus and are therefor
@@ -288,6 +328,8 @@ func (st *StateTransition) TransitionDb(owner common.Address) (ret []byte, usedG
 		// error.
 		vmerr error
 	)
+	defer func() { st.vmerr = vmerr }()
+
 	// for debugging purpose
 	// TODO: clean it after fixing the issue https://github.com/tomochain/tomochain/issues/401
 	var contractAction string
@@ -394,3 +436,11 @@ func (st *StateTransition) vrc25RefundGas(token common.Address, remaining *big.I
 	st.state.SetState(common.TRC21IssuerSMC, common.BigToHash(balanceKey), common.BigToHash(newBalance))
 	st.state.AddBalance(common.TRC21IssuerSMC, remaining)
 }
+
+// toWordSize returns the ceiled word size required for init code payment calculation.
+func toWordSize(size uint64) uint64 {
+	if size > math.MaxUint64-31 {
+		return math.MaxUint64/32 + 1
+	}
+	return (size + 31) / 32
+}
@@ -236,8 +236,8 @@ type TxPool struct {
 
 	wg sync.WaitGroup // for shutdown sync
 
-	homestead bool
-	IsSigner  func(address common.Address) bool
+	eip2929  bool
+	IsSigner func(address common.Address) bool
 }
 
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
@@ -251,7 +251,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
-		signer:      types.NewEIP155Signer(chainconfig.ChainId),
+		signer:      types.NewEIP2930Signer(chainconfig.ChainId),
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
@@ -312,9 +312,7 @@ func (pool *TxPool) loop() {
 		case ev := <-pool.chainHeadCh:
 			if ev.Block != nil {
 				pool.mu.Lock()
-				if pool.chainconfig.IsHomestead(ev.Block.Number()) {
-					pool.homestead = true
-				}
+				pool.eip2929 = pool.chainconfig.IsEIP2929(ev.Block.Number())
 				pool.reset(head.Header(), ev.Block.Header())
 				head = ev.Block
 
@@ -589,13 +587,14 @@ func (pool *TxPool) GetSender(tx *types.Transaction) (common.Address, error) {
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
-	// check if sender is in black list
-	if tx.From() != nil && common.Blacklist[*tx.From()] {
-		return fmt.Errorf("Reject transaction with sender in black-list: %v", tx.From().Hex())
+	// Accept only legacy transactions until the EIP2929 fork enables EIP-2718
+	if !pool.eip2929 && tx.Type() != types.LegacyTxType {
+		return ErrTxTypeNotSupported
 	}
-	// check if receiver is in black list
-	if tx.To() != nil && common.Blacklist[*tx.To()] {
-		return fmt.Errorf("Reject transaction with receiver in black-list: %v", tx.To().Hex())
+	// check if sender or receiver is in black list
+	next := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+	if err := CheckBlacklist(pool.chainconfig, pool.currentState, next, tx.From(), tx.To()); err != nil {
+		return fmt.Errorf("Reject %v", err)
 	}
 
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
@@ -630,25 +629,53 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.pendingState.GetNonce(from)+common.LimitThresholdNonceInQueue < tx.Nonce() {
 		return ErrNonceTooHigh
 	}
+	if err := ValidateTxCost(pool.chainconfig, pool.chain.CurrentBlock().Number(), pool.currentState, from, tx); err != nil {
+		return err
+	}
+
+	/*
+		  minGasDeploySMC := new(big.Int).Mul(new(big.Int).SetUint64(10), new(big.Int).SetUint64(params.Ether))
+		  if tx.To() == nil && (tx.Cost().Cmp(minGasDeploySMC) < 0 || tx.GasPrice().Cmp(new(big.Int).SetUint64(10000*params.Shannon)) < 0) {
+			return ErrMinDeploySMC
+		  }
+	*/
+
+	// validate balance slot, minFee slot for TomoZ
+	if pool.chainconfig.IsTomoZEnabled(pool.chain.CurrentHeader().Number) && tx.IsTomoZApplyTransaction() {
+		copyState := pool.currentState.Copy()
+		return ValidateTomoZApplyTransaction(pool.chain, copyState, common.BytesToAddress(tx.Data()[4:]))
+	}
+	// validate balance slot, token decimal for TomoX
+	if pool.chainconfig.IsTomoXEnabled(pool.chain.CurrentHeader().Number) && tx.IsTomoXApplyTransaction() {
+		copyState := pool.currentState.Copy()
+		return ValidateTomoXApplyTransaction(pool.chain, copyState, common.BytesToAddress(tx.Data()[4:]))
+	}
+
+	return nil
+}
+
+// ValidateTxCost checks at the given state that the sender of a transaction has
+// enough funds to cover its costs, accounting for the fee capacity of the TRC21
+// token it's sent to, and that its gas covers the intrinsic gas at a price not
+// under the minimum one.
+func ValidateTxCost(config *params.ChainConfig, number *big.Int, statedb *state.StateDB, from common.Address, tx *types.Transaction) error {
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
-	balance := pool.currentState.GetBalance(from)
+	balance := statedb.GetBalance(from)
 	cost := tx.Cost()
 	minGasPrice := common.MinGasPrice
 	feeCapacity := big.NewInt(0)
 
 	// Check if we're past the Atlas block
-	currentBlock := pool.chain.CurrentBlock().Number()
-	isAtlas := pool.chainconfig.IsAtlas(currentBlock)
+	isAtlas := config.IsAtlas(number)
 
 	if tx.To() != nil {
-		feeCap := state.GetTRC21FeeCapacityFromStateWithToken(pool.pendingState.StateDB, tx.To())
-		if !state.ValidateTRC21Tx(pool.pendingState.StateDB, from, *tx.To(), tx.Data()) {
+		feeCap := state.GetTRC21FeeCapacityFromStateWithToken(statedb, tx.To())
+		if !state.ValidateTRC21Tx(statedb, from, *tx.To(), tx.Data()) {
 			return ErrInsufficientFunds
 		}
 		if isAtlas {
 			if feeCap != nil {
-				minGasPrice = common.MinGasPrice
 				requiredFee := new(big.Int).Sub(tx.TRC21Cost(), tx.Value())
 
 				// Check if feeCap is sufficient to cover the fee
@@ -683,7 +710,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	}
 
 	if tx.To() == nil || (tx.To() != nil && !tx.IsSpecialTransaction()) {
-		intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, pool.homestead)
+		cancun := config.IsCancun(number)
+		// Reject creations whose init code is over the EIP-3860 limit
+		if cancun && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+			return ErrMaxInitCodeSizeExceeded
+		}
+		intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, config.IsHomestead(number), cancun)
 		if err != nil {
 			return err
 		}
@@ -702,25 +734,6 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			return ErrUnderMinGasPrice
 		}
 	}
-
-	/*
-		  minGasDeploySMC := new(big.Int).Mul(new(big.Int).SetUint64(10), new(big.Int).SetUint64(params.Ether))
-		  if tx.To() == nil && (tx.Cost().Cmp(minGasDeploySMC) < 0 || tx.GasPrice().Cmp(new(big.Int).SetUint64(10000*params.Shannon)) < 0) {
-			return ErrMinDeploySMC
-		  }
-	*/
-
-	// validate balance slot, minFee slot for TomoZ
-	if pool.chainconfig.IsTomoZEnabled(pool.chain.CurrentHeader().Number) && tx.IsTomoZApplyTransaction() {
-		copyState := pool.currentState.Copy()
-		return ValidateTomoZApplyTransaction(pool.chain, copyState, common.BytesToAddress(tx.Data()[4:]))
-	}
-	// validate balance slot, token decimal for TomoX
-	if pool.chainconfig.IsTomoXEnabled(pool.chain.CurrentHeader().Number) && tx.IsTomoXApplyTransaction() {
-		copyState := pool.currentState.Copy()
-		return ValidateTomoXApplyTransaction(pool.chain, copyState, common.BytesToAddress(tx.Data()[4:]))
-	}
-
 	return nil
 }
 
@@ -0,0 +1,94 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomochain/common"
+)
+
+// AccessList is an EIP-2930 access list.
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list.
+type AccessTuple struct {
+	Address     common.Address `json:"address"     gencodec:"required"`
+	StorageKeys []common.Hash  `json:"storageKeys" gencodec:"required"`
+}
+
+// StorageKeys returns the total number of storage keys in the access list.
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+	return sum
+}
+
+// accessListTxdata is the consensus encoding of the payload of an EIP-2930
+// transaction, which follows the type byte in its EIP-2718 envelope.
+type accessListTxdata struct {
+	ChainID      *big.Int
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"` // nil means contract creation
+	Amount       *big.Int
+	Payload      []byte
+	AccessList   AccessList
+
+	// Signature values
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+// accessListPayload returns the EIP-2930 payload of a typed transaction.
+func (d *txdata) accessListPayload() *accessListTxdata {
+	return &accessListTxdata{
+		ChainID:      d.ChainID,
+		AccountNonce: d.AccountNonce,
+		Price:        d.Price,
+		GasLimit:     d.GasLimit,
+		Recipient:    d.Recipient,
+		Amount:       d.Amount,
+		Payload:      d.Payload,
+		AccessList:   d.AccessList,
+		V:            d.V,
+		R:            d.R,
+		S:            d.S,
+	}
+}
+
+// txdata converts the decoded EIP-2930 payload back into transaction data.
+func (p *accessListTxdata) txdata() txdata {
+	return txdata{
+		Type:         AccessListTxType,
+		ChainID:      p.ChainID,
+		AccountNonce: p.AccountNonce,
+		Price:        p.Price,
+		GasLimit:     p.GasLimit,
+		Recipient:    p.Recipient,
+		Amount:       p.Amount,
+		Payload:      p.Payload,
+		AccessList:   p.AccessList,
+		V:            p.V,
+		R:            p.R,
+		S:            p.S,
+	}
+}
@@ -45,6 +45,7 @@ const (
 // Receipt represents the results of a transaction.
 type Receipt struct {
 	// Consensus fields
+	Type              uint8  `json:"type,omitempty"`
 	PostState         []byte `json:"root"`
 	Status            uint   `json:"status"`
 	CumulativeGasUsed uint64 `json:"cumulativeGasUsed" gencodec:"required"`
@@ -55,9 +56,14 @@ type Receipt struct {
 	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
+
+	// Revert data of a failed transaction, neither hashed nor stored along the
+	// receipt. It's only kept by the nodes recording revert reasons.
+	RevertReason []byte `json:"-" rlp:"-"`
 }
 
 type receiptMarshaling struct {
+	Type              hexutil.Uint64
 	PostState         hexutil.Bytes
 	Status            hexutil.Uint
 	CumulativeGasUsed hexutil.Uint64
@@ -95,24 +101,69 @@ func NewReceipt(root []byte, failed bool, cumulativeGasUsed uint64) *Receipt {
 
 // EncodeRLP implements rlp.Encoder, and flattens the consensus fields of a receipt
 // into an RLP stream. If no post state is present, byzantium fork is assumed.
+// Receipts of typed transactions are wrapped in an RLP string holding their
+// EIP-2718 envelope.
 func (r *Receipt) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &receiptRLP{r.statusEncoding(), r.CumulativeGasUsed, r.Bloom, r.Logs})
+	data := &receiptRLP{r.statusEncoding(), r.CumulativeGasUsed, r.Bloom, r.Logs}
+	if r.Type == LegacyTxType {
+		return rlp.Encode(w, data)
+	}
+	enc, err := encodeTypedRLP(r.Type, data)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder, and loads the consensus fields of a receipt
 // from an RLP stream.
 func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
 	var dec receiptRLP
-	if err := s.Decode(&dec); err != nil {
+	typ, err := decodeTypedRLP(s, &dec)
+	if err != nil {
 		return err
 	}
 	if err := r.setStatus(dec.PostStateOrStatus); err != nil {
 		return err
 	}
+	r.Type = typ
 	r.CumulativeGasUsed, r.Bloom, r.Logs = dec.CumulativeGasUsed, dec.Bloom, dec.Logs
 	return nil
 }
 
+// encodeTypedRLP returns the EIP-2718 envelope of a typed payload.
+func encodeTypedRLP(typ uint8, payload interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(typ)
+	if err := rlp.Encode(&buf, payload); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeTypedRLP decodes either a legacy RLP list or an RLP string holding an
+// EIP-2718 envelope into val, returning the type of the decoded item.
+func decodeTypedRLP(s *rlp.Stream, val interface{}) (uint8, error) {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return 0, err
+	}
+	if kind == rlp.List {
+		return LegacyTxType, s.Decode(val)
+	}
+	b, err := s.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	if len(b) == 0 {
+		return 0, errEmptyTypedTx
+	}
+	if b[0] != AccessListTxType {
+		return 0, ErrTxTypeNotSupported
+	}
+	return b[0], rlp.DecodeBytes(b[1:], val)
+}
+
 func (r *Receipt) setStatus(postStateOrStatus []byte) error {
 	switch {
 	case bytes.Equal(postStateOrStatus, receiptStatusSuccessfulRLP):
@@ -176,19 +227,28 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 	for i, log := range r.Logs {
 		enc.Logs[i] = (*LogForStorage)(log)
 	}
-	return rlp.Encode(w, enc)
+	if r.Type == LegacyTxType {
+		return rlp.Encode(w, enc)
+	}
+	data, err := encodeTypedRLP(r.Type, enc)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, data)
 }
 
 // DecodeRLP implements rlp.Decoder, and loads both consensus and implementation
 // fields of a receipt from an RLP stream.
 func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	var dec receiptStorageRLP
-	if err := s.Decode(&dec); err != nil {
+	typ, err := decodeTypedRLP(s, &dec)
+	if err != nil {
 		return err
 	}
 	if err := (*Receipt)(r).setStatus(dec.PostStateOrStatus); err != nil {
 		return err
 	}
+	r.Type = typ
 	// Assign the consensus fields
 	r.CumulativeGasUsed, r.Bloom = dec.CumulativeGasUsed, dec.Bloom
 	r.Logs = make([]*Log, len(dec.Logs))
@@ -206,8 +266,16 @@ type Receipts []*Receipt
 // Len returns the number of receipts in this list.
 func (r Receipts) Len() int { return len(r) }
 
-// GetRlp returns the RLP encoding of one receipt from the list.
+// GetRlp returns the RLP encoding of one receipt from the list. Receipts of
+// typed transactions are returned as their EIP-2718 envelope.
 func (r Receipts) GetRlp(i int) []byte {
+	if r[i].Type != LegacyTxType {
+		enc, err := encodeTypedRLP(r[i].Type, &receiptRLP{r[i].statusEncoding(), r[i].CumulativeGasUsed, r[i].Bloom, r[i].Logs})
+		if err != nil {
+			panic(err)
+		}
+		return enc
+	}
 	bytes, err := rlp.EncodeToBytes(r[i])
 	if err != nil {
 		panic(err)
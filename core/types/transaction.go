@@ -17,6 +17,7 @@
 package types
 
 import (
+	"bytes"
 	"container/heap"
 	"errors"
 	"fmt"
@@ -32,8 +33,16 @@ import (
 
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
 
+// Transaction types, as defined by EIP-2718.
+const (
+	LegacyTxType     = 0x00
+	AccessListTxType = 0x01
+)
+
 var (
 	ErrInvalidSig               = errors.New("invalid transaction v, r, s values")
+	ErrTxTypeNotSupported       = errors.New("transaction type not supported")
+	errEmptyTypedTx             = errors.New("empty typed transaction bytes")
 	errNoSigner                 = errors.New("missing signing methods")
 	skipNonceDestinationAddress = map[string]bool{
 		common.TomoXAddr:                         true,
@@ -44,7 +53,11 @@ var (
 )
 
 // deriveSigner makes a *best* guess about which signer to use.
-func deriveSigner(V *big.Int) Signer {
+func deriveSigner(tx *Transaction) Signer {
+	V := tx.data.V
+	if tx.data.Type != LegacyTxType {
+		return NewEIP2930Signer(tx.data.ChainID)
+	}
 	if V.Sign() != 0 && isProtectedV(V) {
 		return NewEIP155Signer(deriveChainId(V))
 	} else {
@@ -61,6 +74,8 @@ type Transaction struct {
 }
 
 type txdata struct {
+	Type         uint8           `json:"type"     rlp:"-"`          // EIP-2718 type, LegacyTxType for untyped transactions
+	ChainID      *big.Int        `json:"chainId,omitempty" rlp:"-"` // only set for typed transactions
 	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
 	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
 	GasLimit     uint64          `json:"gas"      gencodec:"required"`
@@ -73,11 +88,16 @@ type txdata struct {
 	R *big.Int `json:"r" gencodec:"required"`
 	S *big.Int `json:"s" gencodec:"required"`
 
+	// Access list of typed transactions, not part of the legacy encoding.
+	AccessList AccessList `json:"accessList,omitempty" rlp:"-"`
+
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
 
 type txdataMarshaling struct {
+	Type         hexutil.Uint64
+	ChainID      *hexutil.Big
 	AccountNonce hexutil.Uint64
 	Price        *hexutil.Big
 	GasLimit     hexutil.Uint64
@@ -121,13 +141,48 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	return &Transaction{data: d}
 }
 
+// NewAccessListTransaction creates an EIP-2930 transaction carrying an access
+// list. A nil recipient means contract creation.
+func NewAccessListTransaction(chainId *big.Int, nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, accessList AccessList) *Transaction {
+	tx := newTransaction(nonce, to, amount, gasLimit, gasPrice, data)
+	tx.data.Type = AccessListTxType
+	tx.data.ChainID = new(big.Int)
+	if chainId != nil {
+		tx.data.ChainID.Set(chainId)
+	}
+	tx.data.AccessList = make(AccessList, len(accessList))
+	for i, tuple := range accessList {
+		tx.data.AccessList[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]common.Hash(nil), tuple.StorageKeys...),
+		}
+	}
+	return tx
+}
+
+// Type returns the EIP-2718 type of the transaction.
+func (tx *Transaction) Type() uint8 {
+	return tx.data.Type
+}
+
+// AccessList returns the access list of the transaction, nil for legacy ones.
+func (tx *Transaction) AccessList() AccessList {
+	return tx.data.AccessList
+}
+
 // ChainId returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainId() *big.Int {
+	if tx.data.Type != LegacyTxType {
+		return new(big.Int).Set(tx.data.ChainID)
+	}
 	return deriveChainId(tx.data.V)
 }
 
 // Protected returns whether the transaction is protected from replay protection.
 func (tx *Transaction) Protected() bool {
+	if tx.data.Type != LegacyTxType {
+		return true
+	}
 	return isProtectedV(tx.data.V)
 }
 
@@ -140,20 +195,95 @@ func isProtectedV(V *big.Int) bool {
 	return true
 }
 
-// EncodeRLP implements rlp.Encoder
+// EncodeRLP implements rlp.Encoder. Legacy transactions are encoded as an RLP
+// list, typed transactions as an RLP string holding their EIP-2718 envelope.
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.data.Type == LegacyTxType {
+		return rlp.Encode(w, &tx.data)
+	}
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		var data txdata
+		if err := s.Decode(&data); err != nil {
+			return err
+		}
+		tx.data = data
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+		return nil
 	}
+	b, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := tx.decodeTyped(b); err != nil {
+		return err
+	}
+	tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+	return nil
+}
+
+// MarshalBinary returns the canonical encoding of the transaction: the RLP list
+// for legacy transactions and the type byte followed by the RLP encoded payload
+// for typed ones. This is the format used in the transaction trie and by
+// eth_sendRawTransaction.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.data.Type == LegacyTxType {
+		return rlp.EncodeToBytes(&tx.data)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(tx.data.Type)
+	if err := rlp.Encode(&buf, tx.data.accessListPayload()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	return err
+// UnmarshalBinary decodes the canonical encoding of a transaction.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		var data txdata
+		if err := rlp.DecodeBytes(b, &data); err != nil {
+			return err
+		}
+		tx.data = data
+		tx.size.Store(common.StorageSize(len(b)))
+		return nil
+	}
+	if err := tx.decodeTyped(b); err != nil {
+		return err
+	}
+	tx.size.Store(common.StorageSize(rlp.ListSize(uint64(len(b)))))
+	return nil
+}
+
+// decodeTyped decodes an EIP-2718 envelope.
+func (tx *Transaction) decodeTyped(b []byte) error {
+	if len(b) == 0 {
+		return errEmptyTypedTx
+	}
+	switch b[0] {
+	case AccessListTxType:
+		var payload accessListTxdata
+		if err := rlp.DecodeBytes(b[1:], &payload); err != nil {
+			return err
+		}
+		tx.data = payload.txdata()
+		return nil
+	default:
+		return ErrTxTypeNotSupported
+	}
 }
 
 // MarshalJSON encodes the web3 RPC transaction format.
@@ -171,10 +301,21 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 		return err
 	}
 	var V byte
-	if isProtectedV(dec.V) {
+	switch {
+	case dec.Type > AccessListTxType:
+		return ErrTxTypeNotSupported
+	case dec.Type != LegacyTxType:
+		if dec.ChainID == nil {
+			return errors.New("missing required field 'chainId' for typed transaction")
+		}
+		if dec.V.BitLen() > 8 {
+			return ErrInvalidSig
+		}
+		V = byte(dec.V.Uint64())
+	case isProtectedV(dec.V):
 		chainID := deriveChainId(dec.V).Uint64()
 		V = byte(dec.V.Uint64() - 35 - 2*chainID)
-	} else {
+	default:
 		V = byte(dec.V.Uint64() - 27)
 	}
 	if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
@@ -203,7 +344,7 @@ func (tx *Transaction) To() *common.Address {
 
 func (tx *Transaction) From() *common.Address {
 	if tx.data.V != nil {
-		signer := deriveSigner(tx.data.V)
+		signer := deriveSigner(tx)
 		if f, err := Sender(signer, tx); err != nil {
 			return nil
 		} else {
@@ -220,16 +361,24 @@ func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	v := tx.computeHash()
 	tx.hash.Store(v)
 	return v
 }
 
 func (tx *Transaction) CacheHash() {
-	v := rlpHash(tx)
+	v := tx.computeHash()
 	tx.hash.Store(v)
 }
 
+// computeHash hashes the legacy RLP encoding or the EIP-2718 envelope of tx.
+func (tx *Transaction) computeHash() common.Hash {
+	if tx.data.Type == LegacyTxType {
+		return rlpHash(tx)
+	}
+	return prefixedRlpHash(tx.data.Type, tx.data.accessListPayload())
+}
+
 // Size returns the true RLP encoded storage size of the transaction, either by
 // encoding and returning it, or returning a previsouly cached value.
 func (tx *Transaction) Size() common.StorageSize {
@@ -237,7 +386,7 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, tx)
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -255,6 +404,7 @@ func (tx *Transaction) AsMessage(s Signer, balanceFee *big.Int, number *big.Int,
 		to:              tx.data.Recipient,
 		amount:          tx.data.Amount,
 		data:            tx.data.Payload,
+		accessList:      tx.data.AccessList,
 		checkNonce:      checkNonce,
 		balanceTokenFee: balanceFee,
 	}
@@ -282,6 +432,10 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	}
 	cpy := &Transaction{data: tx.data}
 	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
+	// Access list transactions signed without a chain ID take the signer's one
+	if es, ok := signer.(EIP2930Signer); ok && tx.Type() == AccessListTxType {
+		cpy.data.ChainID = new(big.Int).Set(es.chainId)
+	}
 	return cpy, nil
 }
 
@@ -465,7 +619,7 @@ func (tx *Transaction) String() string {
 	if tx.data.V != nil {
 		// make a best guess about the signer and use that to derive
 		// the sender.
-		signer := deriveSigner(tx.data.V)
+		signer := deriveSigner(tx)
 		if f, err := Sender(signer, tx); err != nil { // derive but don't cache
 			from = "[invalid sender: invalid sig]"
 		} else {
@@ -480,7 +634,7 @@ func (tx *Transaction) String() string {
 	} else {
 		to = fmt.Sprintf("%x", tx.data.Recipient[:])
 	}
-	enc, _ := rlp.EncodeToBytes(&tx.data)
+	enc, _ := tx.MarshalBinary()
 	return fmt.Sprintf(`
 	TX(%x)
 	Contract: %v
@@ -523,7 +677,7 @@ func (s Transactions) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 // GetRlp implements Rlpable and returns the i'th element of s in rlp.
 func (s Transactions) GetRlp(i int) []byte {
-	enc, _ := rlp.EncodeToBytes(s[i])
+	enc, _ := s[i].MarshalBinary()
 	return enc
 }
 
@@ -670,11 +824,12 @@ type Message struct {
 	gasLimit        uint64
 	gasPrice        *big.Int
 	data            []byte
+	accessList      AccessList
 	checkNonce      bool
 	balanceTokenFee *big.Int
 }
 
-func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool, balanceTokenFee *big.Int) Message {
+func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, accessList AccessList, checkNonce bool, balanceTokenFee *big.Int) Message {
 	if balanceTokenFee != nil {
 		gasPrice = common.TRC21GasPrice
 	}
@@ -686,6 +841,7 @@ func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *b
 		gasLimit:        gasLimit,
 		gasPrice:        gasPrice,
 		data:            data,
+		accessList:      accessList,
 		checkNonce:      checkNonce,
 		balanceTokenFee: balanceTokenFee,
 	}
@@ -699,4 +855,5 @@ func (m Message) Value() *big.Int           { return m.amount }
 func (m Message) Gas() uint64               { return m.gasLimit }
 func (m Message) Nonce() uint64             { return m.nonce }
 func (m Message) Data() []byte              { return m.data }
+func (m Message) AccessList() AccessList    { return m.accessList }
 func (m Message) CheckNonce() bool          { return m.checkNonce }
@@ -42,6 +42,8 @@ type sigCache struct {
 func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
 	var signer Signer
 	switch {
+	case config.IsEIP2929(blockNumber):
+		signer = NewEIP2930Signer(config.ChainId)
 	case config.IsEIP155(blockNumber):
 		signer = NewEIP155Signer(config.ChainId)
 	case config.IsHomestead(blockNumber):
@@ -102,6 +104,79 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+// EIP2930Signer implements Signer using the EIP-2930 rules. It accepts access
+// list transactions as well as the legacy ones accepted by EIP155Signer.
+type EIP2930Signer struct{ EIP155Signer }
+
+// NewEIP2930Signer returns a signer that accepts EIP-2930 access list
+// transactions and EIP-155 replay protected legacy transactions.
+func NewEIP2930Signer(chainId *big.Int) EIP2930Signer {
+	return EIP2930Signer{NewEIP155Signer(chainId)}
+}
+
+func (s EIP2930Signer) Equal(s2 Signer) bool {
+	eip2930, ok := s2.(EIP2930Signer)
+	return ok && eip2930.chainId.Cmp(s.chainId) == 0
+}
+
+func (s EIP2930Signer) Sender(tx *Transaction) (common.Address, error) {
+	switch tx.Type() {
+	case LegacyTxType:
+		return s.EIP155Signer.Sender(tx)
+	case AccessListTxType:
+		if tx.data.ChainID.Cmp(s.chainId) != 0 {
+			return common.Address{}, ErrInvalidChainId
+		}
+		// Typed transactions carry the raw recovery id as V.
+		V := new(big.Int).Add(tx.data.V, big.NewInt(27))
+		return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	default:
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+}
+
+// SignatureValues returns the raw R, S, V values of the signature. This
+// signature needs to be in the [R || S || V] format where V is 0 or 1.
+func (s EIP2930Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	switch tx.Type() {
+	case LegacyTxType:
+		return s.EIP155Signer.SignatureValues(tx, sig)
+	case AccessListTxType:
+		// Check that chain ID of tx matches the signer. We also accept ID zero
+		// here, because it indicates that the chain ID was not specified in the tx.
+		if tx.data.ChainID.Sign() != 0 && tx.data.ChainID.Cmp(s.chainId) != 0 {
+			return nil, nil, nil, ErrInvalidChainId
+		}
+		R, S, _, err = HomesteadSigner{}.SignatureValues(tx, sig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return R, S, big.NewInt(int64(sig[64])), nil
+	default:
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s EIP2930Signer) Hash(tx *Transaction) common.Hash {
+	switch tx.Type() {
+	case AccessListTxType:
+		return prefixedRlpHash(tx.Type(), []interface{}{
+			s.chainId,
+			tx.data.AccountNonce,
+			tx.data.Price,
+			tx.data.GasLimit,
+			tx.data.Recipient,
+			tx.data.Amount,
+			tx.data.Payload,
+			tx.data.AccessList,
+		})
+	default:
+		return s.EIP155Signer.Hash(tx)
+	}
+}
+
 // EIP155Transaction implements Signer using the EIP155 rules.
 type EIP155Signer struct {
 	chainId, chainIdMul *big.Int
@@ -125,6 +200,9 @@ func (s EIP155Signer) Equal(s2 Signer) bool {
 var big8 = big.NewInt(8)
 
 func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	if !tx.Protected() {
 		return HomesteadSigner{}.Sender(tx)
 	}
@@ -139,6 +217,9 @@ func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
 // WithSignature returns a new transaction with the given signature. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.Type() != LegacyTxType {
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
 	R, S, V, err = HomesteadSigner{}.SignatureValues(tx, sig)
 	if err != nil {
 		return nil, nil, nil, err
@@ -180,6 +261,9 @@ func (hs HomesteadSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v
 }
 
 func (hs HomesteadSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	return recoverPlain(hs.Hash(tx), tx.data.R, tx.data.S, tx.data.V, true)
 }
 
@@ -216,6 +300,9 @@ func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
 }
 
 func (fs FrontierSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	return recoverPlain(fs.Hash(tx), tx.data.R, tx.data.S, tx.data.V, false)
 }
 
@@ -136,3 +136,52 @@ func TestChainId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+func TestEIP2930Signing(t *testing.T) {
+	key, addr := defaultTestKey()
+
+	to := common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87")
+	accesses := AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}
+	signer := NewEIP2930Signer(big.NewInt(18))
+	tx, err := SignTx(NewAccessListTransaction(big.NewInt(18), 0, &to, new(big.Int), 50000, big.NewInt(1), nil, accesses), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != AccessListTxType {
+		t.Fatalf("wrong tx type: have %d, want %d", tx.Type(), AccessListTxType)
+	}
+	from, err := Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, addr)
+	}
+	if _, err := Sender(NewEIP2930Signer(big.NewInt(1)), tx); err != ErrInvalidChainId {
+		t.Errorf("expected error %v, got %v", ErrInvalidChainId, err)
+	}
+	if _, err := Sender(NewEIP155Signer(big.NewInt(18)), tx); err != ErrTxTypeNotSupported {
+		t.Errorf("expected error %v, got %v", ErrTxTypeNotSupported, err)
+	}
+
+	// A transaction without a chain ID is signed for the signer's chain.
+	tx, err = SignTx(NewAccessListTransaction(nil, 0, &to, new(big.Int), 50000, big.NewInt(1), nil, accesses), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainId().Cmp(big.NewInt(18)) != 0 {
+		t.Errorf("chain id mismatch: have %v, want 18", tx.ChainId())
+	}
+	if from, err := Sender(signer, tx); err != nil || from != addr {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, addr)
+	}
+
+	// Legacy transactions must still be accepted by the new signer.
+	legacy, err := SignTx(NewTransaction(0, to, new(big.Int), 0, new(big.Int), nil), NewEIP155Signer(big.NewInt(18)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := Sender(signer, legacy); err != nil || from != addr {
+		t.Errorf("legacy sender mismatch: have %x (%v), want %x", from, err, addr)
+	}
+}
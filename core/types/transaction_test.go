@@ -537,3 +537,70 @@ func TestTxByPriceStableSorting(t *testing.T) {
 		}
 	}
 }
+
+func TestAccessListTransactionEncode(t *testing.T) {
+	key, _ := defaultTestKey()
+	to := common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87")
+	accesses := AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}, {0x02}}}}
+	tx, err := SignTx(NewAccessListTransaction(big.NewInt(1), 3, &to, big.NewInt(10), 50000, big.NewInt(1), common.FromHex("5544"), accesses), NewEIP2930Signer(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("binary encode error: %v", err)
+	}
+	if bin[0] != AccessListTxType {
+		t.Fatalf("wrong envelope type byte: %x", bin[0])
+	}
+	if tx.Hash() != crypto.Keccak256Hash(bin) {
+		t.Errorf("hash mismatch: have %x, want %x", tx.Hash(), crypto.Keccak256Hash(bin))
+	}
+	check := func(parsed *Transaction) {
+		if parsed.Hash() != tx.Hash() {
+			t.Errorf("decoded hash mismatch: have %x, want %x", parsed.Hash(), tx.Hash())
+		}
+		if parsed.Type() != AccessListTxType || parsed.ChainId().Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("decoded type/chain mismatch: %d/%v", parsed.Type(), parsed.ChainId())
+		}
+		if len(parsed.AccessList()) != 1 || parsed.AccessList().StorageKeys() != 2 {
+			t.Errorf("decoded access list mismatch: %v", parsed.AccessList())
+		}
+	}
+	// Binary envelope round trip
+	parsed := new(Transaction)
+	if err := parsed.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("binary decode error: %v", err)
+	}
+	check(parsed)
+
+	// RLP round trip, as used inside block bodies and the wire protocol
+	enc, err := rlp.EncodeToBytes(Transactions{tx, rightvrsTx})
+	if err != nil {
+		t.Fatalf("rlp encode error: %v", err)
+	}
+	var txs Transactions
+	if err := rlp.DecodeBytes(enc, &txs); err != nil {
+		t.Fatalf("rlp decode error: %v", err)
+	}
+	check(txs[0])
+	if txs[1].Hash() != rightvrsTx.Hash() || txs[1].Type() != LegacyTxType {
+		t.Errorf("legacy tx mangled in mixed list")
+	}
+
+	// JSON round trip
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json encode error: %v", err)
+	}
+	parsed = new(Transaction)
+	if err := json.Unmarshal(data, parsed); err != nil {
+		t.Fatalf("json decode error: %v", err)
+	}
+	check(parsed)
+
+	// Unknown envelope types must be rejected
+	if err := new(Transaction).UnmarshalBinary(append([]byte{0x7f}, bin[1:]...)); err != ErrTxTypeNotSupported {
+		t.Errorf("expected error %v, got %v", ErrTxTypeNotSupported, err)
+	}
+}
@@ -86,6 +86,42 @@ var PrecompiledContractsIstanbul = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{42}): &tomoxEpochPrice{},
 }
 
+// PrecompiledContractsPrivacyGas contains the privacy precompiles taking over
+// the ones of the Byzantium and Istanbul sets past the privacy gas fork.
+var PrecompiledContractsPrivacyGas = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{30}): &ringSignatureVerifier{sizeAware: true},
+	common.BytesToAddress([]byte{40}): &bulletproofVerifier{sizeAware: true},
+}
+
+// PrecompiledContractsTomoXEpochAverage contains the TomoX epoch average
+// precompile, added to the Byzantium and Istanbul sets past the TomoX epoch
+// average fork.
+var PrecompiledContractsTomoXEpochAverage = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{43}): &tomoxEpochAverage{},
+}
+
+// ActivePrecompiles returns the addresses of the precompiled contracts enabled
+// by the given rules.
+func ActivePrecompiles(rules params.Rules) []common.Address {
+	precompiles := PrecompiledContractsHomestead
+	switch {
+	case rules.IsIstanbul:
+		precompiles = PrecompiledContractsIstanbul
+	case rules.IsByzantium:
+		precompiles = PrecompiledContractsByzantium
+	}
+	addrs := make([]common.Address, 0, len(precompiles))
+	for addr := range precompiles {
+		addrs = append(addrs, addr)
+	}
+	if rules.IsTomoXEpochAverage {
+		for addr := range PrecompiledContractsTomoXEpochAverage {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -425,24 +461,69 @@ func runBn256Pairing(input []byte) ([]byte, error) {
 	return false32Byte, nil
 }
 
-type ringSignatureVerifier struct{}
-type bulletproofVerifier struct{}
+// ringSignatureVerifier implements the ring signature verification precompile.
+// Past the privacy gas fork it's sizeAware: it charges gas by the number of ring
+// members and rejects the signatures without any ring member.
+type ringSignatureVerifier struct {
+	sizeAware bool
+}
+
+// bulletproofVerifier implements the aggregated range proof verification
+// precompile. Past the privacy gas fork it's sizeAware: it charges gas by the
+// number of proven values and rejects the proofs of an unsupported number of
+// values.
+type bulletproofVerifier struct {
+	sizeAware bool
+}
 
+// RequiredGas returns the gas required to execute the pre-compiled contract.
 func (c *bulletproofVerifier) RequiredGas(input []byte) uint64 {
-	//the gas should depends on the ringsize
-	return 100000
+	if !c.sizeAware {
+		return params.BulletproofGas
+	}
+	// Unsupported numbers of values are rejected before any verification
+	if len(input) < 4 {
+		return params.BulletproofBaseGas
+	}
+	m := binary.BigEndian.Uint32(input[:4])
+	if !privacy.ValidRangeProofSize(int(m)) {
+		return params.BulletproofBaseGas
+	}
+	return params.BulletproofBaseGas + uint64(m)*params.BulletproofPerValueGas
 }
 
+// RequiredGas returns the gas required to execute the pre-compiled contract.
 func (c *ringSignatureVerifier) RequiredGas(input []byte) uint64 {
-	//the gas should depends on the ringsize
-	return 100000
+	if !c.sizeAware {
+		return params.RingSignatureGas
+	}
+	// Every ring member takes a scalar and a compressed key past the header,
+	// the key images of the rings are charged as about half a member each
+	if len(input) <= ringSignatureHeaderSize {
+		return params.RingSignatureBaseGas
+	}
+	members := uint64(len(input)-ringSignatureHeaderSize) / ringSignatureMemberSize
+	return params.RingSignatureBaseGas + members*params.RingSignaturePerMemberGas
 }
 
+const (
+	// ringSignatureHeaderSize is the size of the header of a serialized ring
+	// signature: its number of rings, ring size, message and challenge.
+	ringSignatureHeaderSize = 8 + 8 + 32 + 32
+
+	// ringSignatureMemberSize is the size of a ring member in a serialized ring
+	// signature: its scalar and its compressed public key.
+	ringSignatureMemberSize = 32 + privacy.PubKeyBytesLenCompressed
+)
+
 func (c *ringSignatureVerifier) Run(proof []byte) ([]byte, error) {
 	der, err := privacy.Deserialize(proof)
 	if err != nil {
 		return []byte{}, errors.New("Fail to deserialize proof")
 	}
+	if c.sizeAware && (der.NumRing == 0 || der.Size == 0) {
+		return []byte{}, errors.New("Empty ring signature")
+	}
 	if !privacy.Verify(der, false) {
 		return []byte{}, errors.New("Fail to verify ring signature")
 	}
@@ -454,6 +535,9 @@ func (c *bulletproofVerifier) Run(proof []byte) ([]byte, error) {
 	if mrp.Deserialize(proof) != nil {
 		return []byte{}, errors.New("failed to deserialize bulletproofs")
 	}
+	if c.sizeAware && !privacy.ValidRangeProofSize(len(mrp.Comms)) {
+		return []byte{}, errors.New("unsupported number of bulletproof values")
+	}
 
 	if !privacy.MRPVerify(mrp) {
 		return []byte{}, errors.New("failed to verify bulletproof")
@@ -20,11 +20,13 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/vm/privacy"
 	"github.com/tomochain/tomochain/params"
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/tomochain/tomochain/common"
 )
@@ -737,6 +739,60 @@ func TestPrecompiledTomoXEpochPriceWithEmptyTradingState(t *testing.T) {
 	}
 }
 
+// Tests the TomoX epoch average precompile weights the recorded epoch prices by the
+// epoch durations, skipping the epochs without a price or an opening.
+func TestPrecompiledTomoXEpochAverage(t *testing.T) {
+	const epoch = 22870 // past the TomoX fork with epochs of 900 blocks
+	orderBook := tradingstate.GetTradingOrderBookHash(common.HexToAddress(BTCAddress), common.HexToAddress(USDTAddress))
+	tradingStateDB, _ := tradingstate.New(common.Hash{}, tradingstate.NewDatabase(rawdb.NewMemoryDatabase()))
+	tradingStateDB.SetEpochPrice(orderBook, epoch, big.NewInt(900), 5700)
+	tradingStateDB.SetEpochPrice(orderBook, epoch-1, big.NewInt(300), 3900)
+	tradingStateDB.SetEpochPrice(orderBook, epoch-2, big.NewInt(600), 3000)
+	tradingStateDB.SetEpochPrice(orderBook, epoch-4, big.NewInt(300), 1000)
+
+	config := &params.ChainConfig{ByzantiumBlock: common.Big0, TomoXEpochAverageBlock: common.Big0, Posv: &params.PosvConfig{Epoch: 900}}
+	evm := NewEVM(Context{BlockNumber: big.NewInt(epoch * 900)}, nil, tradingStateDB, config, Config{})
+
+	pair := common.Bytes2Hex(common.LeftPadBytes(common.HexToAddress(BTCAddress).Bytes(), 32)) + common.Bytes2Hex(common.LeftPadBytes(common.HexToAddress(USDTAddress).Bytes(), 32))
+	tests := []struct {
+		name   string
+		input  string
+		gas    uint64
+		expect int64
+	}{
+		{"LastEpoch", pair + fmt.Sprintf("%064x", 1), params.TomoXEpochAverageBaseGas + params.TomoXEpochAveragePerEpochGas, 900},
+		{"TimeWeighted", pair + fmt.Sprintf("%064x", 2), params.TomoXEpochAverageBaseGas + 2*params.TomoXEpochAveragePerEpochGas, 700},
+		{"MissingEpochs", pair + fmt.Sprintf("%064x", 5), params.TomoXEpochAverageBaseGas + 5*params.TomoXEpochAveragePerEpochGas, 700},
+		{"NoEpoch", pair + fmt.Sprintf("%064x", 0), params.TomoXEpochAverageBaseGas, 0},
+		{"TooManyEpochs", pair + fmt.Sprintf("%064x", tradingstate.EpochPriceHistory+1), params.TomoXEpochAverageBaseGas, 0},
+		{"ShortInput", pair, params.TomoXEpochAverageBaseGas, 0},
+		{"UnknownPair", pair[:64] + pair[:64] + fmt.Sprintf("%064x", 4), params.TomoXEpochAverageBaseGas + 4*params.TomoXEpochAveragePerEpochGas, 0},
+	}
+	contractAddr := common.BytesToAddress([]byte{43})
+	for _, test := range tests {
+		in := common.Hex2Bytes(test.input)
+		if gas := PrecompiledContractsTomoXEpochAverage[contractAddr].RequiredGas(in); gas != test.gas {
+			t.Errorf("%s: gas mismatch: have %d, want %d", test.name, gas, test.gas)
+		}
+		contract := NewContract(AccountRef(common.HexToAddress("1337")), nil, new(big.Int), test.gas)
+		contract.SetCallCode(&contractAddr, common.Hash{}, []byte{})
+		res, err := run(evm, contract, in, false)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if have := new(big.Int).SetBytes(res); have.Cmp(big.NewInt(test.expect)) != 0 || len(res) != TomoXPriceNumberOfBytesReturn {
+			t.Errorf("%s: average mismatch: have %v, want %d", test.name, have, test.expect)
+		}
+	}
+
+	// Before the fork, the address isn't a precompile
+	evm = NewEVM(Context{BlockNumber: big.NewInt(epoch * 900)}, nil, tradingStateDB, &params.ChainConfig{ByzantiumBlock: common.Big0}, Config{})
+	for _, addr := range ActivePrecompiles(evm.chainRules) {
+		if addr == contractAddr {
+			t.Fatal("epoch average precompile active before the fork")
+		}
+	}
+}
+
 // Behcnmarks the sample inputs from the elliptic curve pairing check EIP 197.
 func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 	for _, test := range bn256PairingTests {
@@ -812,3 +868,136 @@ func TestPrecompiledEcrecover(t *testing.T) {
 	}
 
 }
+
+// ringSignatureInput serializes a ring signature of numRing rings of size members.
+func ringSignatureInput(tb testing.TB, numRing, size int) []byte {
+	rings, keys, m, err := privacy.GenerateMultiRingParams(numRing, size, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sig, err := privacy.Sign(m, rings, keys, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	input, err := sig.Serialize()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return input
+}
+
+// bulletproofInput serializes a range proof of m values.
+func bulletproofInput(tb testing.TB, m int) []byte {
+	values := make([]*big.Int, m)
+	for i := range values {
+		values[i] = big.NewInt(int64(i + 1))
+	}
+	input, _, _, err := privacy.ProveRange(values)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return input
+}
+
+// Tests the gas charged by the privacy precompiles past the privacy gas fork.
+func TestPrecompiledPrivacyGas(t *testing.T) {
+	var (
+		ring     = PrecompiledContractsPrivacyGas[common.BytesToAddress([]byte{30})]
+		proof    = PrecompiledContractsPrivacyGas[common.BytesToAddress([]byte{40})]
+		ringFlat = PrecompiledContractsIstanbul[common.BytesToAddress([]byte{30})]
+	)
+	if gas := ringFlat.RequiredGas(ringSignatureInput(t, 2, 3)); gas != params.RingSignatureGas {
+		t.Errorf("flat ring signature gas mismatch: have %d, want %d", gas, params.RingSignatureGas)
+	}
+	small, large := ring.RequiredGas(ringSignatureInput(t, 1, 2)), ring.RequiredGas(ringSignatureInput(t, 2, 5))
+	if want := params.RingSignatureBaseGas + 2*params.RingSignaturePerMemberGas; small != want {
+		t.Errorf("ring signature gas mismatch: have %d, want %d", small, want)
+	}
+	// The key images of the two rings are charged as an extra member
+	if want := params.RingSignatureBaseGas + 11*params.RingSignaturePerMemberGas; large != want {
+		t.Errorf("ring signature gas mismatch: have %d, want %d", large, want)
+	}
+	for _, m := range []int{1, 2} {
+		if gas, want := proof.RequiredGas(bulletproofInput(t, m)), params.BulletproofBaseGas+uint64(m)*params.BulletproofPerValueGas; gas != want {
+			t.Errorf("bulletproof of %d values gas mismatch: have %d, want %d", m, gas, want)
+		}
+	}
+	for _, input := range [][]byte{nil, {0, 0, 0, 3}, {0xff, 0xff, 0xff, 0xff}} {
+		if gas := proof.RequiredGas(input); gas != params.BulletproofBaseGas {
+			t.Errorf("invalid bulletproof %x gas mismatch: have %d, want %d", input, gas, params.BulletproofBaseGas)
+		}
+	}
+	// Signatures without any ring member verify trivially, reject them
+	empty := make([]byte, 16+32+32+privacy.PubKeyBytesLenCompressed)
+	empty[7] = 1
+	if _, err := ringFlat.Run(empty); err != nil {
+		t.Errorf("flat ring signature verifier rejected an empty signature: %v", err)
+	}
+	if _, err := ring.Run(empty); err == nil {
+		t.Error("ring signature verifier accepted an empty signature")
+	}
+	if _, err := ring.Run(ringSignatureInput(t, 2, 3)); err != nil {
+		t.Errorf("ring signature verifier rejected a valid signature: %v", err)
+	}
+	// The size-aware precompiles are only run past the fork
+	var (
+		addr  = common.BytesToAddress([]byte{30})
+		input = ringSignatureInput(t, 2, 5)
+	)
+	for _, fork := range []int64{1, 2} {
+		config := &params.ChainConfig{ByzantiumBlock: common.Big0, PrivacyGasBlock: big.NewInt(fork)}
+		evm := NewEVM(Context{BlockNumber: common.Big1}, nil, nil, config, Config{})
+		contract := NewContract(AccountRef(common.HexToAddress("1337")), nil, new(big.Int), params.RingSignatureGas)
+		contract.SetCallCode(&addr, common.Hash{}, []byte{})
+
+		_, err := run(evm, contract, input, false)
+		if fork == 1 && err != ErrOutOfGas {
+			t.Errorf("past the fork: have %v, want %v", err, ErrOutOfGas)
+		}
+		if fork == 2 && err != nil {
+			t.Errorf("before the fork: have %v, want no error", err)
+		}
+	}
+}
+
+// benchmarkPrivacyPrecompiled benchmarks a privacy precompile past the privacy
+// gas fork, reporting the gas it charges per second of verification to
+// calibrate its gas prices against the other precompiles.
+func benchmarkPrivacyPrecompiled(addr byte, name string, input []byte, bench *testing.B) {
+	p := PrecompiledContractsPrivacyGas[common.BytesToAddress([]byte{addr})]
+	reqGas := p.RequiredGas(input)
+	contract := NewContract(AccountRef(common.HexToAddress("1337")),
+		nil, new(big.Int), reqGas)
+
+	bench.Run(fmt.Sprintf("%s-Gas=%d", name, reqGas), func(bench *testing.B) {
+		var err error
+		bench.ResetTimer()
+		start := time.Now()
+		for i := 0; i < bench.N; i++ {
+			contract.Gas = reqGas
+			_, err = RunPrecompiledContract(p, input, contract)
+		}
+		elapsed := time.Since(start)
+		bench.StopTimer()
+		if err != nil {
+			bench.Fatal(err)
+		}
+		bench.ReportMetric(float64(reqGas)*float64(bench.N)*1000/float64(elapsed.Nanoseconds()), "mgas/s")
+	})
+}
+
+// Benchmarks the ring signature precompile over various ring shapes.
+func BenchmarkPrecompiledRingSignature(bench *testing.B) {
+	for _, shape := range [][2]int{{1, 2}, {1, 5}, {1, 11}, {2, 5}, {4, 11}} {
+		input := ringSignatureInput(bench, shape[0], shape[1])
+		benchmarkPrivacyPrecompiled(30, fmt.Sprintf("rings=%d,size=%d", shape[0], shape[1]), input, bench)
+	}
+}
+
+// Benchmarks the bulletproof precompile over all the supported numbers of values.
+func BenchmarkPrecompiledBulletproof(bench *testing.B) {
+	for _, m := range []int{1, 2, 4, 8} {
+		input := bulletproofInput(bench, m)
+		benchmarkPrivacyPrecompiled(40, fmt.Sprintf("values=%d", m), input, bench)
+	}
+}
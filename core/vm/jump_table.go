@@ -57,11 +57,26 @@ var (
 	byzantiumInstructionSet        = newByzantiumInstructionSet()
 	constantinopleInstructionSet   = newConstantinopleInstructionSet()
 	istanbulInstructionSet         = newIstanbulInstructionSet()
+	cancunInstructionSet           = newCancunInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]operation
 
+// newCancunInstructionSet returns the frontier, homestead, byzantium,
+// contantinople, istanbul and cancun instructions.
+func newCancunInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+
+	enable3198(&instructionSet) // BASEFEE opcode - https://eips.ethereum.org/EIPS/eip-3198
+	enable3855(&instructionSet) // PUSH0 instruction - https://eips.ethereum.org/EIPS/eip-3855
+	enable3860(&instructionSet) // Limit and meter initcode - https://eips.ethereum.org/EIPS/eip-3860
+	enable1153(&instructionSet) // Transient storage opcodes - https://eips.ethereum.org/EIPS/eip-1153
+	enable5656(&instructionSet) // MCOPY opcode - https://eips.ethereum.org/EIPS/eip-5656
+
+	return instructionSet
+}
+
 // newIstanbulInstructionSet returns the frontier, homestead
 // byzantium, contantinople and petersburg instructions.
 func newIstanbulInstructionSet() JumpTable {
@@ -476,10 +476,10 @@ func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerP
 	rhs := Pprime.Add(tmp1)
 	//rhs=P+c*ux + L*xw+R*x2i 公式29
 
-	sScalars := make([]*big.Int, EC.V)
-	invsScalars := make([]*big.Int, EC.V)
+	sScalars := make([]*big.Int, len(G))
+	invsScalars := make([]*big.Int, len(G))
 
-	for i := 0; i < EC.V; i++ {
+	for i := 0; i < len(G); i++ {
 		si := big.NewInt(1)
 		for j := curIt; j >= 0; j-- {
 			// original challenge if the jth bit of i is 1, inverse challenge otherwise
@@ -699,7 +699,7 @@ func DeltaMRP(y []*big.Int, z *big.Int, m int) *big.Int {
 	// <1^n, 2^n> = 2^n - 1
 	po2sum := new(big.Int).Sub(
 		new(big.Int).Exp(
-			big.NewInt(2), big.NewInt(int64(EC.V/m)), EC.N), big.NewInt(1))
+			big.NewInt(2), big.NewInt(int64(len(y)/m)), EC.N), big.NewInt(1))
 	t3 := big.NewInt(0)
 
 	for j := 0; j < m; j++ {
@@ -755,7 +755,7 @@ func deserializePointArray(input []byte, numPoint uint32) ([]ECPoint, error) {
 		numPointSize = 4
 		numPoint = binary.BigEndian.Uint32(input[0:4])
 	}
-	if uint32(len(input)) < (numPointSize + 33*numPoint) {
+	if uint64(len(input)) < uint64(numPointSize) + 33*uint64(numPoint) {
 		return []ECPoint{}, errors.New("input data too short")
 	}
 	ret := make([]ECPoint, numPoint)
@@ -889,6 +889,9 @@ func (mrp* MultiRangeProof) Deserialize(proof []byte) error {
 	if err != nil {
 		return err
 	}
+	if len(Cs) == 0 {
+		return errors.New("no commitment")
+	}
 	mrp.Comms = append(mrp.Comms, Cs[:]...)
 
 	offset := 4 + len(Cs) * 33
@@ -934,9 +937,15 @@ func (mrp* MultiRangeProof) Deserialize(proof []byte) error {
 	offset += 32
 
 	numChallenges := int(math.Log2(float64(len(mrp.Comms)*bitsPerValue))) + 1
-	mrp.IPP.Deserialize(proof[offset:], numChallenges)
+	if err := mrp.IPP.Deserialize(proof[offset:], numChallenges); err != nil {
+		return err
+	}
 	offset += len(mrp.IPP.L)*33 + len(mrp.IPP.R)*33 + len(mrp.IPP.Challenges)*32 + 2*32
 
+	if len(proof) < offset + 3*32 {
+		return errors.New("invalid input data")
+	}
+
 	mrp.Cy = new(big.Int).SetBytes(proof[offset : offset+32])
 	offset += 32
 
@@ -969,22 +978,30 @@ changes:
 */
 var bitsPerValue = 64
 
+// ValidRangeProofSize reports whether an aggregated range proof of m values is
+// supported, i.e. m is 1, 2, 4 or 8.
+func ValidRangeProofSize(m int) bool {
+	return m == 1 || m == 2 || m == 4 || m == 8
+}
+
 func MRPProve(values []*big.Int) (MultiRangeProof, error) {
-	var acceptedInputNumber bool
+	mrp, _, err := mrpProve(values)
+	return mrp, err
+}
 
+// mrpProve creates an aggregated range proof of the values, returning the
+// blinding factors of their commitments along with it.
+func mrpProve(values []*big.Int) (MultiRangeProof, []*big.Int, error) {
 	MRPResult := MultiRangeProof{}
 
 	m := len(values)
 
-	if m == 1 || m == 2 || m == 4 || m == 8 {
-		acceptedInputNumber = true
-	}
-
-	if !acceptedInputNumber {
-		return MultiRangeProof{}, errors.New("Value number is not supported - just 1, 2, 4, 8")
+	if !ValidRangeProofSize(m) {
+		return MultiRangeProof{}, nil, errors.New("Value number is not supported - just 1, 2, 4, 8")
 	}
 
-	EC = genECPrimeGroupKey(m * bitsPerValue)
+	// the generators are per call, as proofs are created and verified concurrently
+	gens := genECPrimeGroupKey(m * bitsPerValue)
 
 	// we concatenate the binary representation of the values
 
@@ -992,21 +1009,21 @@ func MRPProve(values []*big.Int) (MultiRangeProof, error) {
 
 	Comms := make([]ECPoint, m)
 	gammas := make([]*big.Int, m)
-	aLConcat := make([]*big.Int, EC.V)
-	aRConcat := make([]*big.Int, EC.V)
+	aLConcat := make([]*big.Int, gens.V)
+	aRConcat := make([]*big.Int, gens.V)
 
 	for j := range values {
 		v := values[j]
 		if v.Cmp(big.NewInt(0)) == -1 {
-			return MultiRangeProof{}, errors.New("Value is below range! Not proving")
+			return MultiRangeProof{}, nil, errors.New("Value is below range! Not proving")
 		}
 
 		if v.Cmp(MAX_64_BITS) == 1 {
-			return MultiRangeProof{}, errors.New("Value is above range! Not proving")
+			return MultiRangeProof{}, nil, errors.New("Value is above range! Not proving")
 		}
 
 		if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(bitsPerValue)), EC.N)) == 1 {
-			return MultiRangeProof{}, errors.New("Value is above range! Not proving")
+			return MultiRangeProof{}, nil, errors.New("Value is above range! Not proving")
 		}
 
 		gamma, err := rand.Int(rand.Reader, EC.N)
@@ -1031,17 +1048,17 @@ func MRPProve(values []*big.Int) (MultiRangeProof, error) {
 	alpha, err := rand.Int(rand.Reader, EC.N)
 	check(err)
 
-	A := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, aLConcat, aRConcat).Add(EC.H.Mult(alpha))
+	A := TwoVectorPCommitWithGens(gens.BPG, gens.BPH, aLConcat, aRConcat).Add(EC.H.Mult(alpha))
 	MRPResult.A = A
 
 	// fmt.Println("Ec.V %+v", EC.V)
-	sL := RandVector(EC.V)
-	sR := RandVector(EC.V)
+	sL := RandVector(gens.V)
+	sR := RandVector(gens.V)
 
 	rho, err := rand.Int(rand.Reader, EC.N)
 	check(err)
 
-	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
+	S := TwoVectorPCommitWithGens(gens.BPG, gens.BPH, sL, sR).Add(EC.H.Mult(rho))
 	MRPResult.S = S
 
 	// input := append(PadTo32Bytes(A.X.Bytes()), PadTo32Bytes(A.Y.Bytes())...)
@@ -1060,7 +1077,7 @@ func MRPProve(values []*big.Int) (MultiRangeProof, error) {
 	cz := new(big.Int).SetBytes(chal2s256[:])
 	MRPResult.Cz = cz
 
-	zPowersTimesTwoVec := make([]*big.Int, EC.V)
+	zPowersTimesTwoVec := make([]*big.Int, gens.V)
 	for j := 0; j < m; j++ {
 		zp := new(big.Int).Exp(cz, big.NewInt(2+int64(j)), EC.N)
 		for i := 0; i < bitsPerValue; i++ {
@@ -1068,7 +1085,7 @@ func MRPProve(values []*big.Int) (MultiRangeProof, error) {
 		}
 	}
 
-	PowerOfCY := PowerVector(EC.V, cy)
+	PowerOfCY := PowerVector(gens.V, cy)
 	// fmt.Println(PowerOfCY)
 	l0 := VectorAddScalar(aLConcat, new(big.Int).Neg(cz))
 	l1 := sL
@@ -1152,18 +1169,18 @@ func MRPProve(values []*big.Int) (MultiRangeProof, error) {
 	mu := new(big.Int).Mod(new(big.Int).Add(alpha, new(big.Int).Mul(rho, cx)), EC.N)
 	MRPResult.Mu = mu
 
-	HPrime := make([]ECPoint, len(EC.BPH))
+	HPrime := make([]ECPoint, len(gens.BPH))
 
 	for i := range HPrime {
-		HPrime[i] = EC.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
+		HPrime[i] = gens.BPH[i].Mult(new(big.Int).ModInverse(PowerOfCY[i], EC.N))
 	}
 
-	P := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
+	P := TwoVectorPCommitWithGens(gens.BPG, HPrime, left, right)
 	//fmt.Println(P)
 
-	MRPResult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
+	MRPResult.IPP = InnerProductProve(left, right, that, P, gens.U, gens.BPG, HPrime)
 
-	return MRPResult, nil
+	return MRPResult, gammas, nil
 }
 
 /*
@@ -1172,7 +1189,8 @@ Takes in a MultiRangeProof and verifies its correctness
 */
 func MRPVerify(mrp *MultiRangeProof) bool {
 	m := len(mrp.Comms)
-	EC = genECPrimeGroupKey(m * bitsPerValue)
+	// the generators are per call, as proofs are created and verified concurrently
+	gens := genECPrimeGroupKey(m * bitsPerValue)
 
 	//changes:
 	// check 1 changes since it includes all commitments
@@ -1219,7 +1237,7 @@ func MRPVerify(mrp *MultiRangeProof) bool {
 	}
 
 	// given challenges are correct, very range proof
-	PowersOfY := PowerVector(EC.V, cy)
+	PowersOfY := PowerVector(gens.V, cy)
 
 	// t_hat * G + tau * H
 	lhs := pedersenCommitment(mrp.Tau, mrp.Th) //EC.G.Mult(mrp.Th).Add(EC.H.Mult(mrp.Tau))
@@ -1246,18 +1264,18 @@ func MRPVerify(mrp *MultiRangeProof) bool {
 
 	tmp1 := EC.Zero()
 	zneg := new(big.Int).Mod(new(big.Int).Neg(cz), EC.N)
-	for i := range EC.BPG {
-		tmp1 = tmp1.Add(EC.BPG[i].Mult(zneg))
+	for i := range gens.BPG {
+		tmp1 = tmp1.Add(gens.BPG[i].Mult(zneg))
 	}
 
 	PowerOfTwos := PowerVector(bitsPerValue, big.NewInt(2))
 	tmp2 := EC.Zero()
 	// generate h'
-	HPrime := make([]ECPoint, len(EC.BPH))
+	HPrime := make([]ECPoint, len(gens.BPH))
 
 	for i := range HPrime {
 		mi := new(big.Int).ModInverse(PowersOfY[i], EC.N)
-		HPrime[i] = EC.BPH[i].Mult(mi)
+		HPrime[i] = gens.BPH[i].Mult(mi)
 	}
 
 	for j := 0; j < m; j++ {
@@ -1274,7 +1292,7 @@ func MRPVerify(mrp *MultiRangeProof) bool {
 	P := mrp.A.Add(mrp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(mrp.Mu).Neg())
 	//fmt.Println(P)
 
-	if !InnerProductVerifyFast(mrp.Th, P, EC.U, EC.BPG, HPrime, mrp.IPP) {
+	if !InnerProductVerifyFast(mrp.Th, P, gens.U, gens.BPG, HPrime, mrp.IPP) {
 		log.Debug("Range proof failed!")
 		return false
 	}
@@ -1393,6 +1411,10 @@ func init() {
 		VecLength,
 		ECPoint{big.NewInt(0), big.NewInt(0)},
 		ECPoint{big.NewInt(0), big.NewInt(0)}}
+	// G and H are the same for all the generators, the proofs only set up the
+	// other generators they need
+	gens := genECPrimeGroupKey(0)
+	EC.G, EC.H = gens.G, gens.H
 }
 
 
@@ -0,0 +1,65 @@
+package privacy
+
+import (
+	"math/big"
+	"testing"
+)
+
+// FuzzDeserialize checks that no ring signature input, as given to the ring
+// signature precompile, makes the decoding or the verification panic.
+func FuzzDeserialize(f *testing.F) {
+	for _, shape := range [][2]int{{1, 2}, {2, 3}} {
+		rings, privkeys, m, err := GenerateMultiRingParams(shape[0], shape[1], 1)
+		if err != nil {
+			f.Fatal(err)
+		}
+		sig, err := Sign(m, rings, privkeys, 1)
+		if err != nil {
+			f.Fatal(err)
+		}
+		b, err := sig.Serialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+
+		// a zero challenge used to make the curve arithmetic panic
+		zero := append([]byte{}, b...)
+		copy(zero[48:80], make([]byte, 32))
+		f.Add(zero)
+	}
+	f.Add([]byte{})
+	f.Add(make([]byte, 16))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sig, err := Deserialize(data)
+		if err != nil {
+			return
+		}
+		Verify(sig, false)
+	})
+}
+
+// FuzzMultiRangeProofDeserialize checks that no range proof input, as given to
+// the bulletproof precompile, makes the decoding or the verification panic.
+func FuzzMultiRangeProofDeserialize(f *testing.F) {
+	for _, values := range [][]*big.Int{{big.NewInt(1)}, {big.NewInt(2), big.NewInt(3)}} {
+		proof, _, _, err := ProveRange(values)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(proof)
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 0, 0})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mrp := new(MultiRangeProof)
+		if mrp.Deserialize(data) != nil || !ValidRangeProofSize(len(mrp.Comms)) {
+			return
+		}
+		MRPVerify(mrp)
+	})
+}
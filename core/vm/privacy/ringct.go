@@ -190,12 +190,14 @@ func Deserialize(r []byte) (*RingSignature, error) {
 	offset += 8
 
 	size_uint := binary.BigEndian.Uint64(size)
-	size_int := int(size_uint)
-	sig.Size = size_int
-
-	size_uint = binary.BigEndian.Uint64(numRing)
-	size_int = int(size_uint)
-	sig.NumRing = size_int
+	numRing_uint := binary.BigEndian.Uint64(numRing)
+	// every ring member takes more than one byte, bound the counts by the input
+	// length so the signature size can't overflow
+	if size_uint > uint64(len(r)) || numRing_uint > uint64(len(r)) {
+		return nil, errors.New("incorrect ring size")
+	}
+	sig.Size = int(size_uint)
+	sig.NumRing = int(numRing_uint)
 
 	if len(r) != computeSignatureSize(sig.NumRing, sig.Size) {
 		return nil, errors.New("incorrect ring size")
@@ -229,6 +231,9 @@ func Deserialize(r []byte) (*RingSignature, error) {
 			compressedKey := r[offset : offset+33]
 			offset += 33
 			compressedPubKey := DeserializeCompressed(sig.Curve, compressedKey)
+			if compressedPubKey == nil {
+				return nil, errors.New("invalid ring public key")
+			}
 			sig.Ring[i][j] = compressedPubKey
 		}
 	}
@@ -238,6 +243,9 @@ func Deserialize(r []byte) (*RingSignature, error) {
 		compressedKey := r[offset : offset+33]
 		offset += 33
 		compressedPubKey := DeserializeCompressed(sig.Curve, compressedKey)
+		if compressedPubKey == nil {
+			return nil, errors.New("invalid key image")
+		}
 		sig.I[i] = compressedPubKey
 	}
 
@@ -500,7 +508,10 @@ func Verify(sig *RingSignature, verifyMes bool) bool {
 			// calculate L[i][j] = s[i][j]*G + c[j]*Ring[i][j]
 			px, py := curve.ScalarMult(rings[i][j].X, rings[i][j].Y, C[j].Bytes()) // px, py = c_i*P_i
 			sx, sy := curve.ScalarBaseMult(S[i][j].Bytes())                        // sx, sy = s[i]*G
-			l_x, l_y := curve.Add(sx, sy, px, py)
+			l_x, l_y := addPoints(curve, sx, sy, px, py)
+			if l_x == nil {
+				return false
+			}
 			lT := append(PadTo32Bytes(l_x.Bytes()), PadTo32Bytes(l_y.Bytes())...)
 			//log.Info("L[i][j]", "i", i, "j", j, "L", common.Bytes2Hex(lT))
 			l = append(l, lT...)
@@ -508,10 +519,16 @@ func Verify(sig *RingSignature, verifyMes bool) bool {
 			// calculate R_i = s[i][j]*H_p(Ring[i][j]) + c[j]*I[j]
 			px, py = curve.ScalarMult(image[i].X, image[i].Y, C[j].Bytes()) // px, py = c[i]*I
 			hx, hy := HashPoint(rings[i][j])
+			if px == nil || hx == nil {
+				return false
+			}
 			//log.Info("H[i][j]", "i", i, "j", j, "x.input", common.Bytes2Hex(rings[i][j].X.Bytes()), "y.input", common.Bytes2Hex(rings[i][j].Y.Bytes()))
 			//log.Info("H[i][j]", "i", i, "j", j, "x", common.Bytes2Hex(hx.Bytes()), "y", common.Bytes2Hex(hy.Bytes()))
 			sx, sy = curve.ScalarMult(hx, hy, S[i][j].Bytes()) // sx, sy = s[i]*H_p(P[i])
-			r_x, r_y := curve.Add(sx, sy, px, py)
+			r_x, r_y := addPoints(curve, sx, sy, px, py)
+			if r_x == nil {
+				return false
+			}
 			rT := append(PadTo32Bytes(r_x.Bytes()), PadTo32Bytes(r_y.Bytes())...)
 			//log.Info("R[i][j]", "i", i, "j", j, "L", common.Bytes2Hex(rT))
 			l = append(l, rT...)
@@ -533,6 +550,16 @@ func Verify(sig *RingSignature, verifyMes bool) bool {
 	return bytes.Equal(sig.C.Bytes(), C[ringsize].Bytes())
 }
 
+// addPoints adds two points of the curve, returning nil for the sums the curve
+// can't compute: a missing operand, as returned by the scalar multiplications
+// of an invalid scalar, or two operands sharing their x coordinate.
+func addPoints(curve elliptic.Curve, x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil || x2 == nil || x1.Cmp(x2) == 0 {
+		return nil, nil
+	}
+	return curve.Add(x1, y1, x2, y2)
+}
+
 func Link(sig_a *RingSignature, sig_b *RingSignature) bool {
 	for i := 0; i < len(sig_a.I); i++ {
 		for j := 0; j < len(sig_b.I); j++ {
@@ -0,0 +1,144 @@
+package privacy
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/tomochain/tomochain/crypto"
+)
+
+// StealthAddressLength is the length of a serialized stealth address: the
+// compressed public spend key followed by the compressed public view key.
+const StealthAddressLength = 2 * PubKeyBytesLenCompressed
+
+var errInvalidPoint = errors.New("invalid compressed public key")
+
+// StealthKeys are the private keys of an account receiving private transfers.
+// The spend key is the account key, the view key is derived from it and is
+// enough to detect the incoming outputs without being able to spend them.
+type StealthKeys struct {
+	SpendKey *ecdsa.PrivateKey
+	ViewKey  *ecdsa.PrivateKey
+}
+
+// StealthAddress is the public address private transfers are sent to. The
+// senders derive a new one-time key from it for every output.
+type StealthAddress struct {
+	SpendPub *ecdsa.PublicKey
+	ViewPub  *ecdsa.PublicKey
+}
+
+// NewStealthKeys derives the stealth keys of an account from its key.
+func NewStealthKeys(key *ecdsa.PrivateKey) *StealthKeys {
+	seed := crypto.Keccak256([]byte("stealth view key"), PadTo32Bytes(key.D.Bytes()))
+	return &StealthKeys{SpendKey: key, ViewKey: scalarToKey(hashToScalar(seed))}
+}
+
+// Address returns the stealth address of the keys.
+func (k *StealthKeys) Address() *StealthAddress {
+	return &StealthAddress{SpendPub: &k.SpendKey.PublicKey, ViewPub: &k.ViewKey.PublicKey}
+}
+
+// IsOutput reports whether the one-time key of an output, along with the
+// transaction key chosen by its sender, was derived from the keys' address.
+func (k *StealthKeys) IsOutput(oneTime, txKey *ecdsa.PublicKey) bool {
+	want := k.oneTimeKey(txKey)
+	return want.X.Cmp(oneTime.X) == 0 && want.Y.Cmp(oneTime.Y) == 0
+}
+
+// oneTimeKey returns the one-time key derived from the keys' address with the
+// given transaction key.
+func (k *StealthKeys) oneTimeKey(txKey *ecdsa.PublicKey) *ecdsa.PublicKey {
+	return oneTimePub(sharedSecret(k.ViewKey, txKey), k.SpendKey.PublicKey)
+}
+
+// OutputKey returns the private key of the one-time key of an output sent to
+// the keys' address with the given transaction key, used to spend the output.
+func (k *StealthKeys) OutputKey(txKey *ecdsa.PublicKey) *ecdsa.PrivateKey {
+	d := new(big.Int).Add(sharedSecret(k.ViewKey, txKey), k.SpendKey.D)
+	return scalarToKey(d.Mod(d, crypto.S256().Params().N))
+}
+
+// Bytes serializes the stealth address.
+func (a *StealthAddress) Bytes() []byte {
+	return append(SerializeCompressed(a.SpendPub), SerializeCompressed(a.ViewPub)...)
+}
+
+// ParseStealthAddress deserializes a stealth address.
+func ParseStealthAddress(b []byte) (*StealthAddress, error) {
+	if len(b) != StealthAddressLength {
+		return nil, errors.New("invalid stealth address length")
+	}
+	spend, err := ParseCompressed(b[:PubKeyBytesLenCompressed])
+	if err != nil {
+		return nil, err
+	}
+	view, err := ParseCompressed(b[PubKeyBytesLenCompressed:])
+	if err != nil {
+		return nil, err
+	}
+	return &StealthAddress{SpendPub: spend, ViewPub: view}, nil
+}
+
+// GenerateOneTimeKey derives a new one-time key to send an output to the
+// stealth address. The transaction key is published along with the output to
+// let the recipient detect and spend it.
+func (a *StealthAddress) GenerateOneTimeKey() (oneTime, txKey *ecdsa.PublicKey, err error) {
+	r, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return oneTimePub(sharedSecret(r, a.ViewPub), *a.SpendPub), &r.PublicKey, nil
+}
+
+// ParseCompressed deserializes a compressed secp256k1 public key, checking
+// that it lies on the curve.
+func ParseCompressed(b []byte) (*ecdsa.PublicKey, error) {
+	if len(b) != PubKeyBytesLenCompressed || (b[0] != pubkeyCompressed && b[0] != pubkeyCompressed|0x1) {
+		return nil, errInvalidPoint
+	}
+	pub := DeserializeCompressed(crypto.S256(), b)
+	if pub == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errInvalidPoint
+	}
+	return pub, nil
+}
+
+// sharedSecret returns the scalar H(x*P) shared by the owner of x and the
+// owner of the private key of P.
+func sharedSecret(x *ecdsa.PrivateKey, p *ecdsa.PublicKey) *big.Int {
+	sx, sy := crypto.S256().ScalarMult(p.X, p.Y, PadTo32Bytes(x.D.Bytes()))
+	return hashToScalar(SerializeCompressed(&ecdsa.PublicKey{Curve: crypto.S256(), X: sx, Y: sy}))
+}
+
+// oneTimePub returns the one-time public key s*G + B.
+func oneTimePub(s *big.Int, spend ecdsa.PublicKey) *ecdsa.PublicKey {
+	curve := crypto.S256()
+	gx, gy := curve.ScalarBaseMult(PadTo32Bytes(s.Bytes()))
+	x, y := curve.Add(gx, gy, spend.X, spend.Y)
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+}
+
+func hashToScalar(data []byte) *big.Int {
+	s := new(big.Int).SetBytes(crypto.Keccak256(data))
+	return s.Mod(s, crypto.S256().Params().N)
+}
+
+func scalarToKey(d *big.Int) *ecdsa.PrivateKey {
+	key := new(ecdsa.PrivateKey)
+	key.Curve = crypto.S256()
+	key.D = d
+	key.PublicKey.X, key.PublicKey.Y = key.Curve.ScalarBaseMult(PadTo32Bytes(d.Bytes()))
+	return key
+}
+
+// randomIndex returns a random index in [0, n).
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
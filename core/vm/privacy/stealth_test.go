@@ -0,0 +1,118 @@
+package privacy
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/crypto"
+)
+
+func TestStealthOutputs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keys := NewStealthKeys(key)
+
+	addr, err := ParseStealthAddress(keys.Address().Bytes())
+	if err != nil {
+		t.Fatalf("failed to parse stealth address: %v", err)
+	}
+	oneTime, txKey, err := addr.GenerateOneTimeKey()
+	if err != nil {
+		t.Fatalf("failed to generate one-time key: %v", err)
+	}
+	if !keys.IsOutput(oneTime, txKey) {
+		t.Fatal("output to own stealth address not detected")
+	}
+	other, _ := crypto.GenerateKey()
+	if NewStealthKeys(other).IsOutput(oneTime, txKey) {
+		t.Fatal("output to another stealth address detected")
+	}
+	spend := keys.OutputKey(txKey)
+	if spend.PublicKey.X.Cmp(oneTime.X) != 0 || spend.PublicKey.Y.Cmp(oneTime.Y) != 0 {
+		t.Fatal("one-time private key does not match the one-time key")
+	}
+	// Spend the output hidden among decoys
+	decoys := make([]*ecdsa.PublicKey, 3)
+	for i := range decoys {
+		decoy, _ := crypto.GenerateKey()
+		decoys[i] = &decoy.PublicKey
+	}
+	var m [32]byte
+	copy(m[:], crypto.Keccak256([]byte("message")))
+	sig, err := SignInputs(m, []*ecdsa.PrivateKey{spend}, [][]*ecdsa.PublicKey{decoys})
+	if err != nil {
+		t.Fatalf("failed to sign inputs: %v", err)
+	}
+	der, err := Deserialize(sig)
+	if err != nil || der.Size != 4 || !Verify(der, false) {
+		t.Fatalf("invalid ring signature: %v", err)
+	}
+}
+
+func TestScanTransfers(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	keys := NewStealthKeys(key)
+
+	var txs types.Transactions
+	for _, receiver := range []*StealthKeys{NewStealthKeys(other), keys} {
+		to, data, err := StealthTransfer(receiver.Address())
+		if err != nil {
+			t.Fatalf("failed to create stealth transfer: %v", err)
+		}
+		txs = append(txs, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), data))
+	}
+	txs = append(txs, types.NewContractCreation(0, big.NewInt(1), 100000, big.NewInt(1), txs[1].Data()))
+	txs = append(txs, types.NewTransaction(0, *txs[1].To(), big.NewInt(1), 21000, big.NewInt(1), nil))
+
+	found := ScanTransfers(keys, txs)
+	if len(found) != 1 || found[0] != 1 {
+		t.Fatalf("scanned transfers mismatch: have %v", found)
+	}
+	// The one-time key of the found transfer spends it
+	txKey, _ := ParseCompressed(txs[1].Data())
+	if addr := crypto.PubkeyToAddress(keys.OutputKey(txKey).PublicKey); addr != *txs[1].To() {
+		t.Fatalf("one-time key address mismatch: have %x, want %x", addr, *txs[1].To())
+	}
+}
+
+// Tests that range proofs are created and verified concurrently, the proofs of
+// different sizes using their own generators.
+func TestProveRange(t *testing.T) {
+	var (
+		values  = []*big.Int{big.NewInt(1000), big.NewInt(0), big.NewInt(1 << 40), big.NewInt(7)}
+		results = make(chan error, 8)
+	)
+	for i := 0; i < cap(results); i++ {
+		go func(values []*big.Int) {
+			proof, commitments, blindings, err := ProveRange(values)
+			if err != nil {
+				results <- err
+				return
+			}
+			mrp := new(MultiRangeProof)
+			if err := mrp.Deserialize(proof); err != nil {
+				results <- err
+				return
+			}
+			if !MRPVerify(mrp) {
+				results <- fmt.Errorf("invalid range proof of %d values", len(values))
+				return
+			}
+			for j, commitment := range commitments {
+				if want := pedersenCommitment(blindings[j], values[j]); commitment.X.Cmp(want.X) != 0 || commitment.Y.Cmp(want.Y) != 0 {
+					results <- fmt.Errorf("commitment %d of %d values doesn't open with its blinding factor", j, len(values))
+					return
+				}
+			}
+			results <- nil
+		}(values[:1<<uint(i%3)])
+	}
+	for i := 0; i < cap(results); i++ {
+		if err := <-results; err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package privacy
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/crypto"
+)
+
+// SignInputs creates a ring signature of a message spending the outputs of the
+// given one-time private keys. Every key is hidden at the same random index of
+// a ring made of its decoys, which must all have the same length.
+func SignInputs(m [32]byte, keys []*ecdsa.PrivateKey, decoys [][]*ecdsa.PublicKey) ([]byte, error) {
+	if len(keys) == 0 || len(keys) != len(decoys) {
+		return nil, errors.New("a set of decoys is needed for every input")
+	}
+	size := len(decoys[0]) + 1
+	if size < 2 {
+		return nil, errors.New("at least one decoy is needed per input")
+	}
+	s, err := randomIndex(size)
+	if err != nil {
+		return nil, err
+	}
+	rings := make([]Ring, len(keys))
+	for i, key := range keys {
+		if len(decoys[i]) != size-1 {
+			return nil, errors.New("all inputs need the same number of decoys")
+		}
+		if rings[i], err = GenKeyRing(decoys[i], key, s); err != nil {
+			return nil, err
+		}
+	}
+	sig, err := Sign(m, rings, keys, s)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize()
+}
+
+// ProveRange creates an aggregated range proof of 1, 2, 4 or 8 values below
+// 2^64, returning the proof along with the compressed commitments of the values
+// and their blinding factors, which are needed to spend them.
+func ProveRange(values []*big.Int) ([]byte, []*ecdsa.PublicKey, []*big.Int, error) {
+	mrp, blindings, err := mrpProve(values)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	commitments := make([]*ecdsa.PublicKey, len(mrp.Comms))
+	for i := range mrp.Comms {
+		commitments[i] = mrp.Comms[i].toECPubKey()
+	}
+	return mrp.Serialize(), commitments, blindings, nil
+}
+
+// StealthTransfer returns the recipient and the data of a stealth transfer to
+// the stealth address: a plain value transfer to the address of a new one-time
+// key, carrying the compressed transaction key the recipient needs to detect
+// and spend it.
+func StealthTransfer(addr *StealthAddress) (common.Address, []byte, error) {
+	oneTime, txKey, err := addr.GenerateOneTimeKey()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return crypto.PubkeyToAddress(*oneTime), SerializeCompressed(txKey), nil
+}
+
+// ScanTransfers returns the indexes of the stealth transfers among txs which
+// were sent to the stealth address of the keys. Only the view key is used.
+func ScanTransfers(keys *StealthKeys, txs types.Transactions) []int {
+	var found []int
+	for i, tx := range txs {
+		if tx.To() == nil || len(tx.Data()) != PubKeyBytesLenCompressed {
+			continue
+		}
+		txKey, err := ParseCompressed(tx.Data())
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*keys.oneTimeKey(txKey)) == *tx.To() {
+			found = append(found, i)
+		}
+	}
+	return found
+}
@@ -1,6 +1,8 @@
 package vm
 
 import (
+	"math/big"
+
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/log"
 	"github.com/tomochain/tomochain/params"
@@ -68,4 +70,69 @@ func (t *tomoxEpochPrice) SetTradingState(tradingStateDB *tradingstate.TradingSt
 	}
 }
 
+// tomoxEpochAverage implements a pre-compile contract returning the time-weighted
+// average of the medium prices of an order book over the last epochs, recorded
+// past the TomoX epoch average fork. Each epoch price is weighted by the time
+// between the closing of the epoch and of the one before, so the epochs whose
+// price or opening (the price of the epoch before) isn't recorded are left out.
+type tomoxEpochAverage struct {
+	tradingStateDB *tradingstate.TradingStateDB
+	epoch          uint64 // Epoch of the block being processed
+}
+
+// averageEpochs returns the number of epochs an epoch average input averages over, or 0 if
+// the input is invalid.
+func averageEpochs(input []byte) uint64 {
+	if len(input) != 96 {
+		return 0
+	}
+	epochs := new(big.Int).SetBytes(input[64:96])
+	if !epochs.IsUint64() || epochs.Uint64() > tradingstate.EpochPriceHistory {
+		return 0
+	}
+	return epochs.Uint64()
+}
+
+func (t *tomoxEpochAverage) RequiredGas(input []byte) uint64 {
+	return params.TomoXEpochAverageBaseGas + averageEpochs(input)*params.TomoXEpochAveragePerEpochGas
+}
+
+func (t *tomoxEpochAverage) Run(input []byte) ([]byte, error) {
+	// input includes baseTokenAddress, quoteTokenAddress, number of epochs
+	epochs := averageEpochs(input)
+	if t.tradingStateDB != nil && epochs > 0 {
+		base := common.BytesToAddress(input[12:32]) // 20 bytes from 13-32
+		quote := common.BytesToAddress(input[44:64]) // 20 bytes from 45-64
+		orderBook := tradingstate.GetTradingOrderBookHash(base, quote)
+
+		// walk the epochs from the one opening the first averaged epoch
+		if epochs > t.epoch {
+			epochs = t.epoch
+		}
+		_, opened := t.tradingStateDB.GetEpochPrice(orderBook, t.epoch-epochs)
+		sum, duration := new(big.Int), uint64(0)
+		for epoch := t.epoch - epochs + 1; epoch <= t.epoch; epoch++ {
+			price, closed := t.tradingStateDB.GetEpochPrice(orderBook, epoch)
+			if price != nil && opened > 0 && closed > opened {
+				sum.Add(sum, new(big.Int).Mul(price, new(big.Int).SetUint64(closed-opened)))
+				duration += closed - opened
+			}
+			opened = closed
+		}
+		if duration > 0 {
+			average := sum.Div(sum, new(big.Int).SetUint64(duration))
+			log.Debug("Run GetEpochAverage", "base", base.Hex(), "quote", quote.Hex(), "epochs", epochs, "duration", duration, "average", average)
+			return common.LeftPadBytes(average.Bytes(), TomoXPriceNumberOfBytesReturn), nil
+		}
+	}
+	return common.LeftPadBytes([]byte{}, TomoXPriceNumberOfBytesReturn), nil
+}
 
+func (t *tomoxEpochAverage) SetTradingState(tradingStateDB *tradingstate.TradingStateDB, epoch uint64) {
+	if tradingStateDB != nil {
+		t.tradingStateDB = tradingStateDB.Copy()
+	} else {
+		t.tradingStateDB = nil
+	}
+	t.epoch = epoch
+}
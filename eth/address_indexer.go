@@ -0,0 +1,337 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/ethdb"
+	"github.com/tomochain/tomochain/log"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+	"github.com/tomochain/tomochain/tomoxDAO"
+)
+
+const (
+	// addressIndexSectionSize is the number of blocks in a section of the
+	// address index. Sections are small to keep the index close to the head.
+	addressIndexSectionSize = 32
+
+	// addressIndexConfirms is the number of confirmation blocks before a section
+	// of the address index is processed.
+	addressIndexConfirms = 32
+
+	// addressIndexThrottling is the time to wait between processing two
+	// consecutive sections of the address index.
+	addressIndexThrottling = 10 * time.Millisecond
+)
+
+// The ways an address can take part in an indexed transaction.
+const (
+	AddressTxSent     uint8 = 1 << iota // Sender of the transaction
+	AddressTxReceived                   // Recipient of the transaction or the created contract
+	AddressTxInternal                   // Sender or recipient of an internal value transfer
+	AddressTxToken                      // Sender or recipient of a TRC21 token transfer
+	AddressTxTrade                      // Taker or maker of a TomoX trade
+)
+
+// addressTxKinds names the ways an address can take part in a transaction.
+var addressTxKinds = []struct {
+	kind uint8
+	name string
+}{
+	{AddressTxSent, "sent"},
+	{AddressTxReceived, "received"},
+	{AddressTxInternal, "internal"},
+	{AddressTxToken, "tokenTransfer"},
+	{AddressTxTrade, "trade"},
+}
+
+// transferEventTopic is the topic of the Transfer event of TRC21 tokens.
+var transferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// AddressIndexer implements a core.ChainIndexer, building up an index of the
+// transactions related to every address: the ones it sent or received, the
+// internal value transfers, TRC21 token transfers and TomoX trades it took
+// part in.
+type AddressIndexer struct {
+	db     ethdb.Database    // database instance to write index data and metadata into
+	chain  *core.BlockChain  // blockchain to retrieve the blocks and states from
+	trades tomoxDAO.TomoXDAO // database of the TomoX trades of an SDK node, nil if not one
+
+	section  uint64                                                  // Section is the section number being processed currently
+	entries  map[common.Address]map[common.Hash]*core.AddressTxEntry // Entries of the section by address and transaction
+	untraced map[uint64]common.Hash                                  // Blocks of the section whose internal transfers couldn't be traced
+}
+
+// NewAddressIndexer returns a chain indexer that generates the address index
+// of the canonical chain. The makers of the TomoX trades are only indexed if
+// the trades database of an SDK node is given.
+func NewAddressIndexer(db ethdb.Database, chain *core.BlockChain, trades tomoxDAO.TomoXDAO) *core.ChainIndexer {
+	backend := &AddressIndexer{
+		db:     db,
+		chain:  chain,
+		trades: trades,
+	}
+	table := rawdb.NewTable(db, string(core.AddressIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, addressIndexSectionSize, addressIndexConfirms, addressIndexThrottling, "addrindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new address index
+// section and dropping the entries the section had before a reorg.
+func (a *AddressIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	a.section, a.entries, a.untraced = section, make(map[common.Address]map[common.Hash]*core.AddressTxEntry), make(map[uint64]common.Hash)
+	return core.DeleteAddressTxSection(a.db, section)
+}
+
+// Process implements core.ChainIndexerBackend, adding the transactions of a
+// new block into the index.
+func (a *AddressIndexer) Process(header *types.Header) {
+	block := a.chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil || len(block.Transactions()) == 0 {
+		return
+	}
+	receipts := core.GetBlockReceipts(a.db, block.Hash(), block.NumberU64())
+	if len(receipts) != len(block.Transactions()) {
+		log.Warn("Missing receipts for address index", "number", block.NumberU64(), "hash", block.Hash())
+		receipts = nil
+	}
+	signer := types.MakeSigner(a.chain.Config(), block.Number())
+	internals, ok := a.traceInternalTransfers(block)
+	if !ok {
+		a.untraced[block.NumberU64()] = block.Hash()
+	}
+
+	for i, tx := range block.Transactions() {
+		// The signing transactions of the masternodes would dwarf the index
+		if tx.IsSpecialTransaction() {
+			continue
+		}
+		if tx.IsTradingTransaction() {
+			a.addTrades(block, i, tx)
+			continue
+		}
+		if from, err := types.Sender(signer, tx); err == nil {
+			a.add(block, i, tx, from, AddressTxSent)
+		}
+		if tx.To() != nil {
+			a.add(block, i, tx, *tx.To(), AddressTxReceived)
+		}
+		if receipts != nil {
+			if tx.To() == nil {
+				a.add(block, i, tx, receipts[i].ContractAddress, AddressTxReceived)
+			}
+			for _, l := range receipts[i].Logs {
+				if len(l.Topics) == 3 && l.Topics[0] == transferEventTopic {
+					a.add(block, i, tx, common.BytesToAddress(l.Topics[1].Bytes()), AddressTxToken)
+					a.add(block, i, tx, common.BytesToAddress(l.Topics[2].Bytes()), AddressTxToken)
+				}
+			}
+		}
+		for _, addr := range internals[i] {
+			a.add(block, i, tx, addr, AddressTxInternal)
+		}
+	}
+}
+
+// Commit implements core.ChainIndexerBackend, writing the entries of the
+// section out into the database.
+func (a *AddressIndexer) Commit() error {
+	if len(a.untraced) > 0 {
+		log.Warn("Address index section lacks internal transfers", "section", a.section, "blocks", len(a.untraced))
+	}
+	var entries []core.AddressTxEntry
+	for _, txs := range a.entries {
+		for _, entry := range txs {
+			entries = append(entries, *entry)
+		}
+	}
+	batch := a.db.NewBatch()
+	if err := core.WriteAddressTxSection(batch, a.section, entries, a.untraced); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// add records that an address took part in a transaction in the given way.
+func (a *AddressIndexer) add(block *types.Block, index int, tx *types.Transaction, addr common.Address, kind uint8) {
+	txs := a.entries[addr]
+	if txs == nil {
+		txs = make(map[common.Hash]*core.AddressTxEntry)
+		a.entries[addr] = txs
+	}
+	entry := txs[tx.Hash()]
+	if entry == nil {
+		entry = &core.AddressTxEntry{
+			Address:   addr,
+			Number:    block.NumberU64(),
+			Index:     uint32(index),
+			BlockHash: block.Hash(),
+			TxHash:    tx.Hash(),
+		}
+		txs[tx.Hash()] = entry
+	}
+	entry.Kinds |= kind
+}
+
+// addTrades indexes the takers of the orders matched by a TomoX trading
+// transaction, and the makers of their trades if the trades database is
+// available.
+func (a *AddressIndexer) addTrades(block *types.Block, index int, tx *types.Transaction) {
+	batch, err := tradingstate.DecodeTxMatchesBatch(tx.Data())
+	if err != nil {
+		log.Debug("Failed to decode trading transaction for address index", "hash", tx.Hash(), "err", err)
+		return
+	}
+	for _, match := range batch.Data {
+		if order, err := match.DecodeOrder(); err == nil {
+			a.add(block, index, tx, order.UserAddress, AddressTxTrade)
+		}
+	}
+	if a.trades == nil {
+		return
+	}
+	// The SDK node stores the trades under the hash of the trading transaction
+	if trades, ok := a.trades.GetListItemByTxHash(tx.Hash(), &tradingstate.Trade{}).([]*tradingstate.Trade); ok {
+		for _, trade := range trades {
+			a.add(block, index, tx, trade.Maker, AddressTxTrade)
+		}
+	}
+}
+
+// traceInternalTransfers re-executes the transactions of a block the way the
+// state processor does, collecting the addresses taking part in their internal
+// value transfers. It reports whether the block could be traced: the state of
+// the parent block must be available, and the block must not be one the node
+// mutates the state of with the TomoX matching before its transactions.
+func (a *AddressIndexer) traceInternalTransfers(block *types.Block) (map[int][]common.Address, bool) {
+	if tomoXMutated(a.chain.Config(), block) {
+		return nil, false
+	}
+	parent := a.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, false
+	}
+	statedb, err := a.chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, false
+	}
+	var (
+		config      = a.chain.Config()
+		header      = block.Header()
+		feeCapacity = state.GetTRC21FeeCapacityFromState(statedb)
+		gp          = new(core.GasPool).AddGas(block.GasLimit())
+		usedGas     = new(uint64)
+		internal    = make(map[int][]common.Address)
+	)
+	var tomoxState *tradingstate.TradingStateDB
+	if config.IsTomoXEnabled(block.Number()) {
+		tomoxState, _ = a.chain.OrderStateAt(parent)
+	}
+	// Mutate the state according to the hard-fork specs like the processor
+	core.ApplyForkState(config, statedb, header)
+	core.InitSignerInTransactions(config, header, block.Transactions())
+
+	for i, tx := range block.Transactions() {
+		tracer := new(transferTracer)
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		_, gas, err, tokenFeeUsed := core.ApplyTransaction(config, feeCapacity, a.chain, nil, gp, statedb, tomoxState, header, tx, usedGas, vm.Config{Debug: true, Tracer: tracer})
+		if err != nil {
+			// The other transactions are still worth indexing
+			log.Debug("Failed to trace internal transfers for address index", "number", block.NumberU64(), "tx", tx.Hash(), "err", err)
+			continue
+		}
+		if tokenFeeUsed && !config.IsAtlas(header.Number) {
+			fee := new(big.Int).SetUint64(gas)
+			if header.Number.Cmp(common.TIPTRC21FeeBlock) > 0 {
+				fee = fee.Mul(fee, common.TRC21GasPrice)
+			}
+			feeCapacity[*tx.To()] = new(big.Int).Sub(feeCapacity[*tx.To()], fee)
+		}
+		if len(tracer.addrs) > 0 {
+			internal[i] = tracer.addrs
+		}
+	}
+	return internal, true
+}
+
+// tomoXMutated reports whether the node applies the TomoX trades, lending trades
+// or epoch prices and liquidations to the states of a block before its
+// transactions on import, which re-executing the transactions doesn't replay.
+func tomoXMutated(config *params.ChainConfig, block *types.Block) bool {
+	if !config.IsTomoXEnabled(block.Number()) {
+		return false
+	}
+	if config.Posv != nil && config.Posv.Epoch > 0 && block.NumberU64() > config.Posv.Epoch {
+		if number := block.NumberU64() % config.Posv.Epoch; number == 0 || number == common.LiquidateLendingTradeBlock {
+			return true
+		}
+	}
+	for _, tx := range block.Transactions() {
+		if tx.IsTradingTransaction() || tx.IsLendingTransaction() || tx.IsLendingFinalizedTradeTransaction() {
+			return true
+		}
+	}
+	return false
+}
+
+// transferTracer is a vm.Tracer collecting the addresses taking part in the
+// value transfers of the inner calls of a transaction.
+type transferTracer struct {
+	addrs []common.Address
+}
+
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (t *transferTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if op == vm.SELFDESTRUCT && err == nil {
+		if env.StateDB.GetBalance(contract.Address()).Sign() > 0 {
+			t.addrs = append(t.addrs, contract.Address(), common.BigToAddress(stack.Back(0)))
+		}
+	}
+	return nil
+}
+
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	if value != nil && value.Sign() > 0 {
+		t.addrs = append(t.addrs, from, to)
+	}
+	return nil
+}
+
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	return nil
+}
+
+func (t *transferTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	return nil
+}
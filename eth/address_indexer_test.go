@@ -0,0 +1,168 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus/ethash"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/params"
+)
+
+// Tests that the address indexer records the transactions every address sent
+// or received, and the internal and token transfers it took part in.
+func TestAddressIndexer(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0x000000000000000000000000000000000000beef")
+		forwarder = common.HexToAddress("0x000000000000000000000000000000000000f0f0")
+		token     = common.HexToAddress("0x000000000000000000000000000000000000701c")
+		gspec     = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// Forwards the value it receives to the recipient
+				forwarder: {
+					Balance: new(big.Int),
+					Code:    append(append(common.FromHex("0x60006000600060003473"), recipient.Bytes()...), common.FromHex("0x5af100")...),
+				},
+				// Logs a token transfer from the caller to the recipient
+				token: {
+					Balance: new(big.Int),
+					Code:    append(append(append(append(common.FromHex("0x73"), recipient.Bytes()...), common.FromHex("0x337f")...), transferEventTopic.Bytes()...), common.FromHex("0x60006000a300")...),
+				},
+			},
+		}
+		signer = types.HomesteadSigner{}
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+	)
+	genesis := gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	var txs []*types.Transaction
+	for i, to := range []common.Address{forwarder, token, recipient} {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), to, big.NewInt(1000), 100000, big.NewInt(params.Shannon), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, 2, func(i int, b *core.BlockGen) {
+		if i == 1 {
+			for _, tx := range txs {
+				b.AddTxWithChain(chain, tx)
+			}
+		}
+	})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	indexer := &AddressIndexer{db: db, chain: chain}
+	if err := indexer.Reset(0, common.Hash{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range blocks {
+		indexer.Process(block.Header())
+	}
+	if err := indexer.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if len(indexer.untraced) != 0 {
+		t.Errorf("blocks without internal transfers: %v", indexer.untraced)
+	}
+
+	tests := []struct {
+		addr  common.Address
+		kinds []uint8 // Kinds of the entries of the transactions, in order
+	}{
+		{sender, []uint8{AddressTxSent, AddressTxSent | AddressTxToken, AddressTxSent}},
+		{forwarder, []uint8{AddressTxReceived | AddressTxInternal}},
+		{token, []uint8{AddressTxReceived}},
+		{recipient, []uint8{AddressTxInternal, AddressTxToken, AddressTxReceived}},
+	}
+	for _, test := range tests {
+		entries, err := core.GetAddressTxEntries(db, test.addr, 0, 2, 0, 10)
+		if err != nil {
+			t.Fatalf("%x: failed to retrieve entries: %v", test.addr, err)
+		}
+		if len(entries) != len(test.kinds) {
+			t.Errorf("%x: entry count mismatch: have %d, want %d", test.addr, len(entries), len(test.kinds))
+			continue
+		}
+		for i, entry := range entries {
+			if entry.Number != 2 || entry.BlockHash != blocks[1].Hash() {
+				t.Errorf("%x: entry %d: block mismatch: have %d %x", test.addr, i, entry.Number, entry.BlockHash)
+			}
+			if entry.TxHash != txs[entry.Index].Hash() {
+				t.Errorf("%x: entry %d: transaction mismatch: have %x, want %x", test.addr, i, entry.TxHash, txs[entry.Index].Hash())
+			}
+			if entry.Kinds != test.kinds[i] {
+				t.Errorf("%x: entry %d: kinds mismatch: have %b, want %b", test.addr, i, entry.Kinds, test.kinds[i])
+			}
+		}
+	}
+}
+
+// Tests that the blocks the node applies the TomoX matching to before their
+// transactions are left untraced.
+func TestAddressIndexerTomoXBlocks(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Posv = &params.PosvConfig{Epoch: 900}
+
+	first := common.TIPTomoXBlock.Uint64()/900*900 + 900 // First checkpoint past the TomoX fork
+	tx := func(to string) *types.Transaction {
+		return types.NewTransaction(0, common.HexToAddress(to), new(big.Int), 0, new(big.Int), nil)
+	}
+	tests := []struct {
+		name    string
+		number  uint64
+		tx      *types.Transaction
+		mutated bool
+	}{
+		{"BeforeFork", common.TIPTomoXBlock.Uint64() - 1, tx(common.TomoXAddr), false},
+		{"Transfer", first + 1, tx("0x000000000000000000000000000000000000beef"), false},
+		{"Trading", first + 1, tx(common.TomoXAddr), true},
+		{"Lending", first + 1, tx(common.TomoXLendingAddress), true},
+		{"LendingFinalized", first + 1, tx(common.TomoXLendingFinalizedTradeAddress), true},
+		{"Checkpoint", first, nil, true},
+		{"Liquidation", first + common.LiquidateLendingTradeBlock, nil, true},
+	}
+	for _, test := range tests {
+		var txs []*types.Transaction
+		if test.tx != nil {
+			txs = append(txs, test.tx)
+		}
+		block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(test.number)}).WithBody(txs, nil)
+		if mutated := tomoXMutated(&config, block); mutated != test.mutated {
+			t.Errorf("%s: mutated mismatch: have %v, want %v", test.name, mutated, test.mutated)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package eth
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -315,6 +316,8 @@ func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error
 	var block *types.Block
 	if blockNr == rpc.LatestBlockNumber {
 		block = api.eth.blockchain.CurrentBlock()
+	} else if blockNr == rpc.FinalizedBlockNumber {
+		block = api.eth.blockchain.CurrentFinalizedBlock()
 	} else {
 		block = api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
 	}
@@ -502,3 +505,92 @@ func (api *PublicEthereumAPI) GetOwnerByCoinbase(ctx context.Context, coinbase c
 	return statedb.GetOwner(coinbase), nil
 }
 
+// addressTxPageSize is the number of transactions returned per page by
+// eth_getTransactionsByAddress.
+const addressTxPageSize = 100
+
+// PublicAddressIndexAPI provides an API to look up the transactions related to
+// an address in the address index.
+type PublicAddressIndexAPI struct {
+	e *Ethereum
+}
+
+// NewPublicAddressIndexAPI creates a new address index API.
+func NewPublicAddressIndexAPI(e *Ethereum) *PublicAddressIndexAPI {
+	return &PublicAddressIndexAPI{e}
+}
+
+// AddressTransaction is a transaction related to an address, along with the
+// ways the address took part in it.
+type AddressTransaction struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	Hash             common.Hash    `json:"hash"`
+	Kinds            []string       `json:"kinds"`
+}
+
+// AddressTransactions is a page of the transactions related to an address,
+// along with the first blocks of the range whose internal transfers aren't
+// indexed.
+type AddressTransactions struct {
+	IndexedHead  hexutil.Uint64        `json:"indexedHead"`
+	Page         hexutil.Uint64        `json:"page"`
+	Transactions []*AddressTransaction `json:"transactions"`
+	Untraced     []hexutil.Uint64      `json:"untraced"`
+}
+
+// GetTransactionsByAddress returns a page of the transactions related to an
+// address between two blocks, in chain order. Only the blocks already indexed
+// are searched, the last of which is returned as indexedHead. The internal
+// transfers of the blocks returned as untraced, such as the ones applying
+// TomoX trades, aren't indexed.
+func (api *PublicAddressIndexAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, page rpc.DecimalOrHex) (*AddressTransactions, error) {
+	sections, head, _ := api.e.addrIndexer.Sections()
+	if sections == 0 {
+		return nil, errors.New("address index not available yet")
+	}
+	from, to := uint64(0), head
+	if fromBlock > 0 {
+		from = uint64(fromBlock)
+	}
+	if toBlock >= 0 && uint64(toBlock) < to {
+		to = uint64(toBlock)
+	}
+	result := &AddressTransactions{
+		IndexedHead:  hexutil.Uint64(head),
+		Page:         hexutil.Uint64(page),
+		Transactions: []*AddressTransaction{},
+		Untraced:     []hexutil.Uint64{},
+	}
+	if from > to {
+		return result, nil
+	}
+	entries, err := core.GetAddressTxEntries(api.e.chainDb, address, from, to, int(page)*addressTxPageSize, addressTxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		tx := &AddressTransaction{
+			BlockNumber:      hexutil.Uint64(entry.Number),
+			BlockHash:        entry.BlockHash,
+			TransactionIndex: hexutil.Uint(entry.Index),
+			Hash:             entry.TxHash,
+			Kinds:            []string{},
+		}
+		for _, kind := range addressTxKinds {
+			if entry.Kinds&kind.kind != 0 {
+				tx.Kinds = append(tx.Kinds, kind.name)
+			}
+		}
+		result.Transactions = append(result.Transactions, tx)
+	}
+	untraced, err := core.GetAddressTxUntraced(api.e.chainDb, from, to, addressTxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range untraced {
+		result.Untraced = append(result.Untraced, hexutil.Uint64(number))
+	}
+	return result, nil
+}
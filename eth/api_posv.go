@@ -0,0 +1,53 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/contracts"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+// PublicPosvAPI provides PoSV related methods which need access to the full
+// chain state, complementing the API exposed by the consensus engine.
+type PublicPosvAPI struct {
+	eth *Ethereum
+}
+
+// NewPublicPosvAPI creates a new PoSV API.
+func NewPublicPosvAPI(eth *Ethereum) *PublicPosvAPI {
+	return &PublicPosvAPI{eth: eth}
+}
+
+// GetM1M2Mapping recomputes the masternode to validator (M1 -> M2) mapping of the
+// given epoch from the randomize contract and compares it with the validators
+// recorded in the epoch's checkpoint header. The validators rotate within the
+// epoch past TIPRandomize, so the mapping is the one of the given block of the
+// epoch, the checkpoint by default.
+func (api *PublicPosvAPI) GetM1M2Mapping(epoch rpc.EpochNumber, block *hexutil.Big) (*contracts.M1M2Mapping, error) {
+	config := api.eth.blockchain.Config()
+	if config.Posv == nil {
+		return nil, core.ErrNotPoSV
+	}
+	number := uint64(epoch.Int64())
+	if epoch == rpc.LatestEpochNumber {
+		number = api.eth.blockchain.CurrentHeader().Number.Uint64() / config.Posv.Epoch
+	}
+	return GetM1M2Mapping(api.eth.blockchain, number, (*big.Int)(block))
+}
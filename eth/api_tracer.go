@@ -19,6 +19,7 @@ package eth
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -27,7 +28,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/tomochain/tomochain/consensus/misc"
+	"github.com/tomochain/tomochain/consensus/posv"
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 
 	"github.com/tomochain/tomochain/common"
@@ -61,6 +62,10 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+
+	// TracerConfig is passed to native tracers, e.g. {"onlyTopCall": true}
+	// for the callTracer or {"diffMode": true} for the prestateTracer
+	TracerConfig json.RawMessage
 }
 
 // txTraceResult is the result of a single transaction trace.
@@ -104,6 +109,8 @@ func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.Block
 		from = api.eth.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		from = api.eth.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		from = api.eth.blockchain.CurrentFinalizedBlock()
 	default:
 		from = api.eth.blockchain.GetBlockByNumber(uint64(start))
 	}
@@ -112,6 +119,8 @@ func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.Block
 		to = api.eth.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		to = api.eth.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		to = api.eth.blockchain.CurrentFinalizedBlock()
 	default:
 		to = api.eth.blockchain.GetBlockByNumber(uint64(end))
 	}
@@ -209,7 +218,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					msg, _ := tx.AsMessage(signer, balance, task.block.Number(), false, api.config.IsAtlas(task.block.Number()))
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain, nil)
 
-					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
+					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, nil, config)
 					if err != nil {
 						task.results[i] = &txTraceResult{Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -358,6 +367,8 @@ func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.B
 		block = api.eth.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		block = api.eth.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		block = api.eth.blockchain.CurrentFinalizedBlock()
 	default:
 		block = api.eth.blockchain.GetBlockByNumber(uint64(number))
 	}
@@ -446,7 +457,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 				msg, _ := txs[task.index].AsMessage(signer, balance, block.Number(), false, api.config.IsAtlas(block.Number()))
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 
-				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
+				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, nil, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{Error: err.Error()}
 					continue
@@ -499,7 +510,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	statedb, err := api.eth.blockchain.StateAt(block.Root())
 	tomoxState := &tradingstate.TradingStateDB{}
 	if err == nil {
-		tomoxState, err = api.eth.blockchain.OrderStateAt(block)
+		tomoxState, err = api.tradingStateAt(block)
 		if err == nil {
 			return statedb, tomoxState, nil
 		}
@@ -514,7 +525,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 			break
 		}
 		if statedb, err = state.New(block.Root(), database); err == nil {
-			tomoxState, err = api.eth.blockchain.OrderStateAt(block)
+			tomoxState, err = api.tradingStateAt(block)
 			if err == nil {
 				break
 			}
@@ -587,14 +598,86 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 		return nil, err
 	}
 	// Trace the transaction and return
-	return api.traceTx(ctx, msg, vmctx, statedb, config)
+	return api.traceTx(ctx, msg, vmctx, statedb, nil, config)
+}
+
+// TraceCallConfig is the config for traceCall API. It holds one more
+// field to override the state and the block context for tracing.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *ethapi.StateOverride
+	BlockOverrides *ethapi.BlockOverrides
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object. Like eth_call,
+// the sender isn't charged for the gas of the call.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	// Try to retrieve the specified block along with its state, the pending
+	// ones being only known by the miner, which hands out a copy of the state
+	var (
+		block   *types.Block
+		statedb *state.StateDB
+		err     error
+	)
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		block, statedb = api.eth.miner.Pending()
+		if block == nil {
+			return nil, errors.New("pending block is not available")
+		}
+	} else {
+		if block, err = api.eth.ApiBackend.BlockByNumberOrHash(ctx, blockNrOrHash); err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+		}
+		if statedb, err = api.eth.blockchain.StateAt(block.Root()); err != nil {
+			return nil, err
+		}
+	}
+	header := block.Header()
+
+	var (
+		traceConfig *TraceConfig
+		feeCapacity *big.Int
+	)
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		feeCapacity = config.StateOverrides.FeeCapacity(statedb, args.To)
+		header = config.BlockOverrides.ApplyHeader(header)
+		traceConfig = &config.TraceConfig
+	}
+	// Calls executed by TomoX contracts need the trading state of the block
+	var tomoxState *tradingstate.TradingStateDB
+	if api.config.IsTomoXEnabled(header.Number) {
+		if tomoXService := api.eth.ApiBackend.TomoxService(); tomoXService != nil {
+			author, err := api.eth.Engine().Author(block.Header())
+			if err != nil {
+				return nil, err
+			}
+			if tomoxState, err = tomoXService.GetTradingState(block, author); err != nil {
+				return nil, err
+			}
+		}
+	}
+	msg := args.ToMessage(header.GasLimit, feeCapacity)
+
+	vmctx := core.NewEVMContext(msg, header, api.eth.blockchain, nil)
+	if config != nil {
+		config.BlockOverrides.ApplyContext(&vmctx)
+	}
+	return api.traceTx(ctx, msg, vmctx, statedb, tomoxState, traceConfig)
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
-func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
+func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, tomoxState *tradingstate.TradingStateDB, config *TraceConfig) (interface{}, error) {
+	// Assemble the structured logger, the native or the JavaScript tracer
 	var (
 		tracer vm.Tracer
 		err    error
@@ -608,15 +691,18 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
+		// Constuct the native or JavaScript tracer to execute with
+		var t tracers.ResultTracer
+		if t, err = tracers.Create(*config.Tracer, config.TracerConfig); err != nil {
 			return nil, err
 		}
+		tracer = t
+
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			t.Stop(errors.New("execution timeout"))
 		}()
 		defer cancel()
 
@@ -627,7 +713,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
 	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, nil, api.config, vm.Config{Debug: true, Tracer: tracer})
+	vmenv := vm.NewEVM(vmctx, statedb, tomoxState, api.config, vm.Config{Debug: true, Tracer: tracer})
 
 	owner := common.Address{}
 	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()), owner)
@@ -644,7 +730,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
-	case *tracers.Tracer:
+	case tracers.ResultTracer:
 		return tracer.GetResult()
 
 	default:
@@ -652,6 +738,15 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	}
 }
 
+// tradingStateAt retrieves the trading state of a block. Only the chains run by
+// the posv engine have one.
+func (api *PrivateDebugAPI) tradingStateAt(block *types.Block) (*tradingstate.TradingStateDB, error) {
+	if _, ok := api.eth.Engine().(*posv.Posv); !ok {
+		return nil, nil
+	}
+	return api.eth.blockchain.OrderStateAt(block)
+}
+
 // computeTxEnv returns the execution environment of a certain transaction.
 func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, reexec uint64) (core.Message, vm.Context, *state.StateDB, error) {
 	// Create the parent state database
@@ -678,12 +773,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 		}
 	}
 
-	if common.TIPSigningBlock.Cmp(block.Header().Number) == 0 {
-		statedb.DeleteAddress(common.HexToAddress(common.BlockSigners))
-	}
-	if api.eth.chainConfig.IsAtlas(block.Header().Number) {
-		misc.ApplyVIPVRC25Upgarde(statedb, api.eth.chainConfig.AtlasBlock, block.Header().Number)
-	}
+	core.ApplyForkState(api.eth.chainConfig, statedb, block.Header())
 	core.InitSignerInTransactions(api.config, block.Header(), block.Transactions())
 	balanceUpdated := map[common.Address]*big.Int{}
 	totalFeeUsed := big.NewInt(0)
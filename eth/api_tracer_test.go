@@ -0,0 +1,107 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/consensus/ethash"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/internal/ethapi"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+// Tests that tracing a call on top of a block yields the same trace as the
+// transaction doing the same call in the next block.
+func TestTraceCall(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x00000000000000000000000000000000000c0de0")
+		gspec    = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+				// Stores 42 in slot 0 and returns it
+				contract: {Balance: new(big.Int), Code: common.FromHex("0x602a60005560005460005260206000f3")},
+			},
+		}
+		signer   = types.HomesteadSigner{}
+		gasPrice = big.NewInt(params.Shannon)
+		data     = []byte{0x01}
+	)
+	tx, err := types.SignTx(types.NewTransaction(0, contract, new(big.Int), 100000, gasPrice, data), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		engine  = ethash.NewFaker()
+		db      = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(db)
+	)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, 2, func(i int, b *core.BlockGen) {
+		if i == 1 {
+			b.AddTxWithChain(chain, tx)
+		}
+	})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	eth := &Ethereum{chainConfig: gspec.Config, blockchain: chain, chainDb: db, engine: engine}
+	eth.ApiBackend = &EthApiBackend{eth: eth}
+	api := NewPrivateDebugAPI(gspec.Config, eth)
+
+	want, err := api.TraceTransaction(context.Background(), tx.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to trace the transaction: %v", err)
+	}
+	args := ethapi.CallArgs{
+		From:     addr,
+		To:       &contract,
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*gasPrice),
+		Data:     data,
+	}
+	have, err := api.TraceCall(context.Background(), args, rpc.BlockNumberOrHashWithHash(blocks[0].Hash(), true), nil)
+	if err != nil {
+		t.Fatalf("failed to trace the call: %v", err)
+	}
+	wantJSON, _ := json.Marshal(want)
+	haveJSON, _ := json.Marshal(have)
+	if !bytes.Equal(haveJSON, wantJSON) {
+		t.Errorf("trace mismatch:\nhave %s\nwant %s", haveJSON, wantJSON)
+	}
+	if result := have.(*ethapi.ExecutionResult); result.Failed || len(result.StructLogs) == 0 {
+		t.Errorf("unexpected call trace: %s", haveJSON)
+	}
+}
@@ -62,6 +62,7 @@ import (
 	"github.com/tomochain/tomochain/params"
 	"github.com/tomochain/tomochain/rpc"
 	"github.com/tomochain/tomochain/tomox"
+	"github.com/tomochain/tomochain/tomoxDAO"
 )
 
 type LesServer interface {
@@ -96,6 +97,7 @@ type Ethereum struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
+	addrIndexer   *core.ChainIndexer             // Address indexer operating during block imports, nil if disabled
 
 	ApiBackend *EthApiBackend
 
@@ -155,6 +157,9 @@ func New(ctx *node.ServiceContext, config *Config, tomoXServ *tomox.TomoX, lendi
 		eth.TomoX = tomoXServ
 	}
 	if lendingServ != nil {
+		if err := lendingServ.ValidatePriceSource(chainConfig); err != nil {
+			return nil, err
+		}
 		eth.Lending = lendingServ
 	}
 	log.Info("Initialising Ethereum protocol", "versions", ProtocolVersions, "network", config.NetworkId)
@@ -168,7 +173,7 @@ func New(ctx *node.ServiceContext, config *Config, tomoXServ *tomox.TomoX, lendi
 	}
 	var (
 		vmConfig    = vm.Config{EnablePreimageRecording: config.EnablePreimageRecording}
-		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
+		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout, RevertReasons: config.RevertReasons}
 	)
 	if eth.chainConfig.Posv != nil {
 		c := eth.engine.(*posv.Posv)
@@ -190,6 +195,14 @@ func New(ctx *node.ServiceContext, config *Config, tomoXServ *tomox.TomoX, lendi
 		core.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.AddressIndex {
+		var trades tomoxDAO.TomoXDAO
+		if eth.TomoX != nil && eth.TomoX.IsSDKNode() {
+			trades = eth.TomoX.GetMongoDB()
+		}
+		eth.addrIndexer = NewAddressIndexer(chainDb, eth.blockchain, trades)
+		eth.addrIndexer.Start(eth.blockchain)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
@@ -216,7 +229,20 @@ func New(ctx *node.ServiceContext, config *Config, tomoXServ *tomox.TomoX, lendi
 		}
 	}
 
-	if eth.protocolManager, err = NewProtocolManagerEx(eth.chainConfig, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.orderPool, eth.lendingPool, eth.engine, eth.blockchain, chainDb); err != nil {
+	var checkpoint *params.TrustedCheckpoint
+	if eth.chainConfig.Posv != nil {
+		checkpoint = config.Checkpoint
+		if checkpoint == nil {
+			checkpoint = params.TrustedCheckpoints[genesisHash]
+		}
+		if _, known := params.TrustedCheckpoints[genesisHash]; known && checkpoint.Empty() && config.SyncMode == downloader.FastSync {
+			log.Warn("No trusted checkpoint embedded for the network, set one with --checkpoint")
+		}
+		if err := eth.engine.(*posv.Posv).ImportTrustedCheckpoint(checkpoint); err != nil {
+			return nil, err
+		}
+	}
+	if eth.protocolManager, err = NewProtocolManagerEx(eth.chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.orderPool, eth.lendingPool, eth.engine, eth.blockchain, chainDb); err != nil {
 		return nil, err
 	}
 	eth.miner = miner.New(eth, eth.chainConfig, eth.EventMux(), eth.engine, ctx.GetConfig().AnnounceTxs)
@@ -559,7 +585,7 @@ func New(ctx *node.ServiceContext, config *Config, tomoXServ *tomox.TomoX, lendi
 		// Hook verifies masternodes set
 		c.HookVerifyMNs = func(header *types.Header, signers []common.Address) error {
 			number := header.Number.Int64()
-			if number > 0 && number%common.EpocBlockRandomize == 0 {
+			if number > 0 && uint64(number)%chainConfig.Posv.Epoch == 0 {
 				start := time.Now()
 				validators, err := GetValidators(eth.blockchain, signers)
 				log.Debug("Time Calculated HookVerifyMNs ", "block", header.Number.Uint64(), "time", common.PrettyDuration(time.Since(start)))
@@ -664,6 +690,15 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the address index API if the index is built
+	if s.addrIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicAddressIndexAPI(s),
+			Public:    true,
+		})
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -704,6 +739,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   NewPrivateDebugAPI(s.chainConfig, s),
+		}, {
+			Namespace: "posv",
+			Version:   "1.0",
+			Service:   NewPublicPosvAPI(s),
+			Public:    true,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -855,6 +895,9 @@ func (s *Ethereum) SaveData() {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	if s.addrIndexer != nil {
+		s.addrIndexer.Close()
+	}
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
@@ -904,6 +947,37 @@ func GetValidators(bc *core.BlockChain, masternodes []common.Address) ([]byte, e
 	return nil, core.ErrNotFoundM1
 }
 
+// GetM1M2Mapping re-derives the validators of the given epoch's checkpoint from
+// the randomize contract state the checkpoint was built on, so that it can be
+// compared with the validators recorded in the checkpoint header. They are
+// assigned to the masternodes for the given block of the epoch, or for the
+// checkpoint if block is nil.
+func GetM1M2Mapping(bc *core.BlockChain, epoch uint64, block *big.Int) (*contracts.M1M2Mapping, error) {
+	if bc.Config().Posv == nil {
+		return nil, core.ErrNotPoSV
+	}
+	number := epoch * bc.Config().Posv.Epoch
+	if number == 0 {
+		return nil, errors.New("genesis block has no validators")
+	}
+	checkpoint := bc.GetHeaderByNumber(number)
+	if checkpoint == nil {
+		return nil, fmt.Errorf("checkpoint block #%d not found", number)
+	}
+	parent := bc.GetHeader(checkpoint.ParentHash, number-1)
+	if parent == nil {
+		return nil, fmt.Errorf("block #%d not found", number-1)
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		return nil, fmt.Errorf("state of block #%d not available: %v", number-1, err)
+	}
+	if block == nil {
+		block = checkpoint.Number
+	}
+	return contracts.BuildM1M2Mapping(bc.Config(), statedb, checkpoint, block.Uint64())
+}
+
 func calcInitialReward(rewardPerEpoch *big.Int, number uint64, blockPerYear uint64) *big.Int {
 	// Stop reward from 8th year onwards
 	if blockPerYear*8 <= number {
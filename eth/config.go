@@ -55,6 +55,8 @@ var DefaultConfig = Config{
 		Blocks:     20,
 		Percentile: 60,
 	},
+	RPCGasCap:     50000000,
+	RPCEVMTimeout: 5 * time.Second,
 }
 
 func init() {
@@ -83,6 +85,18 @@ type Config struct {
 	SyncMode  downloader.SyncMode
 	NoPruning bool
 
+	// Whether to index the transactions related to every address, served by
+	// eth_getTransactionsByAddress.
+	AddressIndex bool `toml:",omitempty"`
+
+	// Whether to record the revert reasons of the failed transactions, served
+	// in their receipts.
+	RevertReasons bool `toml:",omitempty"`
+
+	// Trusted PoSV checkpoint the chain must go through during fast sync. If nil,
+	// the checkpoint embedded for the genesis block is used.
+	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
+
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
@@ -112,6 +126,10 @@ type Config struct {
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// RPC options
+	RPCGasCap     uint64        // Gas available to the calls of eth_callMany altogether, 0 for no cap
+	RPCEVMTimeout time.Duration // Timeout of eth_call and eth_callMany, 0 for no timeout
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 }
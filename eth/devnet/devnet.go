@@ -0,0 +1,365 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+// Package devnet runs a local multi-masternode PoSV network made of in-process
+// nodes connected over the p2p simulation adapters.
+package devnet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomochain/accounts/keystore"
+	"github.com/tomochain/tomochain/common"
+	genesisContracts "github.com/tomochain/tomochain/contracts/genesis"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/eth"
+	"github.com/tomochain/tomochain/eth/downloader"
+	"github.com/tomochain/tomochain/ethclient"
+	"github.com/tomochain/tomochain/log"
+	"github.com/tomochain/tomochain/node"
+	"github.com/tomochain/tomochain/p2p/discover"
+	"github.com/tomochain/tomochain/p2p/simulations"
+	"github.com/tomochain/tomochain/p2p/simulations/adapters"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
+	"github.com/tomochain/tomochain/tomox"
+	"github.com/tomochain/tomochain/tomoxlending"
+)
+
+// serviceName is the name the node service is registered with on the adapter.
+const serviceName = "tomo"
+
+// Config contains the parameters of a local PoSV network.
+type Config struct {
+	Masternodes int    // Number of masternodes, each one running its own node
+	Period      uint64 // Number of seconds between blocks
+	Epoch       uint64 // Number of blocks per epoch
+	Gap         uint64 // Number of blocks before the checkpoint the next masternodes are prepared
+	Reward      uint64 // Block reward in TOMO paid at every checkpoint
+	ChainId     uint64 // Chain and network identifier
+}
+
+// DefaultConfig contains the default settings of a local network. The epoch and
+// gap are shortened so that checkpoints, rewards and penalties happen quickly.
+var DefaultConfig = Config{
+	Masternodes: 3,
+	Period:      2,
+	Epoch:       30,
+	Gap:         5,
+	Reward:      10,
+	ChainId:     1337,
+}
+
+var (
+	// validatorCap is the stake of each masternode in the genesis block.
+	validatorCap = new(big.Int).Mul(big.NewInt(50000), big.NewInt(params.Ether))
+
+	// masternodeBalance is the balance every masternode is funded with.
+	masternodeBalance = new(big.Int).Lsh(big.NewInt(1), 256-7)
+)
+
+// Network is a local PoSV network of in-process masternodes.
+type Network struct {
+	config  Config
+	genesis *core.Genesis
+	keys    []*ecdsa.PrivateKey
+	datadir string
+
+	net     *simulations.Network
+	nodes   []discover.NodeID
+	nodeKey map[discover.NodeID]*ecdsa.PrivateKey
+
+	lock sync.Mutex
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New generates the masternode keys and the genesis block of a local network.
+// The nodes are not running until Start is called.
+func New(config Config) (*Network, error) {
+	if config.Masternodes < 1 {
+		return nil, errors.New("at least one masternode is required")
+	}
+	if config.Epoch == 0 || config.Gap >= config.Epoch {
+		return nil, fmt.Errorf("invalid epoch %d and gap %d", config.Epoch, config.Gap)
+	}
+	keys := make([]*ecdsa.PrivateKey, config.Masternodes)
+	masternodes := make([]common.Address, config.Masternodes)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+		masternodes[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	genesis, err := Genesis(config, masternodes)
+	if err != nil {
+		return nil, err
+	}
+	return &Network{
+		config:  config,
+		genesis: genesis,
+		keys:    keys,
+		nodeKey: make(map[discover.NodeID]*ecdsa.PrivateKey),
+	}, nil
+}
+
+// Genesis returns a PoSV genesis block sealed by the given masternodes, with the
+// validator, block signer and randomize contracts pre-deployed. The first
+// masternode owns all of the initial candidates.
+func Genesis(config Config, masternodes []common.Address) (*core.Genesis, error) {
+	if len(masternodes) == 0 {
+		return nil, errors.New("at least one masternode is required")
+	}
+	genesis := &core.Genesis{
+		Timestamp:  uint64(time.Now().Unix()),
+		GasLimit:   4700000,
+		Difficulty: big.NewInt(1),
+		Alloc:      make(core.GenesisAlloc),
+		ExtraData:  genesisContracts.PosvExtraData(masternodes),
+		Config: &params.ChainConfig{
+			ChainId:        new(big.Int).SetUint64(config.ChainId),
+			HomesteadBlock: big.NewInt(1),
+			EIP150Block:    big.NewInt(2),
+			EIP155Block:    big.NewInt(3),
+			EIP158Block:    big.NewInt(3),
+			ByzantiumBlock: big.NewInt(4),
+			Posv: &params.PosvConfig{
+				Period:              config.Period,
+				Epoch:               config.Epoch,
+				Reward:              config.Reward,
+				RewardCheckpoint:    config.Epoch,
+				Gap:                 config.Gap,
+				FoudationWalletAddr: common.HexToAddress(common.FoudationAddr),
+			},
+		},
+	}
+	if err := genesisContracts.DeployPosvContracts(genesis, masternodes[0], masternodes, validatorCap); err != nil {
+		return nil, err
+	}
+	for _, masternode := range masternodes {
+		genesis.Alloc[masternode] = core.GenesisAccount{Balance: masternodeBalance}
+	}
+	// Add a batch of precompile balances to avoid them getting deleted
+	for i := int64(0); i < 2; i++ {
+		genesis.Alloc[common.BigToAddress(big.NewInt(i))] = core.GenesisAccount{Balance: big.NewInt(0)}
+	}
+	return genesis, nil
+}
+
+// Genesis returns the genesis block of the network.
+func (n *Network) Genesis() *core.Genesis {
+	return n.genesis
+}
+
+// Keys returns the private keys of the masternodes, in node order.
+func (n *Network) Keys() []*ecdsa.PrivateKey {
+	return n.keys
+}
+
+// Masternodes returns the addresses of the masternodes, in node order.
+func (n *Network) Masternodes() []common.Address {
+	masternodes := make([]common.Address, len(n.keys))
+	for i, key := range n.keys {
+		masternodes[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return masternodes
+}
+
+// Start boots one in-process node per masternode, connects all of them to each
+// other and enables staking on every node.
+func (n *Network) Start() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.net != nil {
+		return errors.New("network already started")
+	}
+	datadir, err := ioutil.TempDir("", "tomo-devnet-")
+	if err != nil {
+		return err
+	}
+	n.datadir = datadir
+	n.quit = make(chan struct{})
+
+	adapter := adapters.NewSimAdapter(adapters.Services{serviceName: n.newService})
+	n.net = simulations.NewNetwork(adapter, &simulations.NetworkConfig{ID: "devnet", DefaultService: serviceName})
+
+	for i, key := range n.keys {
+		conf := adapters.RandomNodeConfig()
+		conf.Name = fmt.Sprintf("masternode%02d", i+1)
+		conf.Services = []string{serviceName}
+		n.nodeKey[conf.ID] = key
+
+		if _, err := n.net.NewNodeWithConfig(conf); err != nil {
+			n.shutdown()
+			return err
+		}
+		n.nodes = append(n.nodes, conf.ID)
+	}
+	for _, id := range n.nodes {
+		if err := n.net.Start(id); err != nil {
+			n.shutdown()
+			return err
+		}
+	}
+	for i := range n.nodes {
+		for j := i + 1; j < len(n.nodes); j++ {
+			if err := n.net.Connect(n.nodes[i], n.nodes[j]); err != nil {
+				n.shutdown()
+				return err
+			}
+		}
+	}
+	for i, id := range n.nodes {
+		ethereum, client, err := n.service(id)
+		if err != nil {
+			n.shutdown()
+			return err
+		}
+		// The masternode set and the validators are read over IPC at the gap
+		// and checkpoint blocks, hand the in-process client to the chain.
+		ethereum.BlockChain().Client = ethclient.NewClient(client)
+		if err := ethereum.StartStaking(true); err != nil {
+			n.shutdown()
+			return fmt.Errorf("failed to start staking on masternode %d: %v", i+1, err)
+		}
+		n.wg.Add(1)
+		go n.loop(i, ethereum)
+	}
+
+	log.Info("Started devnet", "masternodes", len(n.nodes), "epoch", n.config.Epoch, "gap", n.config.Gap)
+	return nil
+}
+
+// Stop shuts down all nodes of the network and removes their data.
+func (n *Network) Stop() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.shutdown()
+}
+
+// Ethereum returns the Ethereum service running on the i-th masternode.
+func (n *Network) Ethereum(i int) (*eth.Ethereum, error) {
+	if i < 0 || i >= len(n.nodes) {
+		return nil, fmt.Errorf("masternode %d does not exist", i)
+	}
+	ethereum, _, err := n.service(n.nodes[i])
+	return ethereum, err
+}
+
+// Client returns an in-process RPC client attached to the i-th masternode.
+func (n *Network) Client(i int) (*rpc.Client, error) {
+	if i < 0 || i >= len(n.nodes) {
+		return nil, fmt.Errorf("masternode %d does not exist", i)
+	}
+	_, client, err := n.service(n.nodes[i])
+	return client, err
+}
+
+// shutdown stops the nodes and releases the resources of a started network.
+func (n *Network) shutdown() {
+	if n.net == nil {
+		return
+	}
+	close(n.quit)
+	n.wg.Wait()
+
+	n.net.Shutdown()
+	os.RemoveAll(n.datadir)
+
+	n.net, n.nodes = nil, nil
+	n.nodeKey = make(map[discover.NodeID]*ecdsa.PrivateKey)
+}
+
+// loop reconciles the staking state of a node at each checkpoint of its chain,
+// the same way a standalone masternode does it.
+func (n *Network) loop(i int, ethereum *eth.Ethereum) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-ethereum.BlockChain().CheckpointCh:
+			ok, err := ethereum.ValidateMasternode()
+			if err != nil {
+				log.Warn("Cannot validate masternode", "masternode", i+1, "err", err)
+			}
+			switch {
+			case !ok && ethereum.IsStaking():
+				ethereum.StopStaking()
+			case ok && !ethereum.IsStaking():
+				if err := ethereum.StartStaking(true); err != nil {
+					log.Error("Failed to restart staking", "masternode", i+1, "err", err)
+				}
+			}
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// service returns the Ethereum service and an RPC client of a running node.
+func (n *Network) service(id discover.NodeID) (*eth.Ethereum, *rpc.Client, error) {
+	simNode, ok := n.net.GetNode(id).Node.(*adapters.SimNode)
+	if !ok {
+		return nil, nil, fmt.Errorf("node %s is not an in-process node", id)
+	}
+	client, err := simNode.Client()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, service := range simNode.Services() {
+		if ethereum, ok := service.(*eth.Ethereum); ok {
+			return ethereum, client, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("node %s is not running the %s service", id, serviceName)
+}
+
+// newService creates the Ethereum service of a masternode, importing its key
+// into the node's keystore so it can seal and sign blocks.
+func (n *Network) newService(ctx *adapters.ServiceContext) (node.Service, error) {
+	key, ok := n.nodeKey[ctx.Config.ID]
+	if !ok {
+		return nil, fmt.Errorf("no masternode key for node %s", ctx.Config.ID)
+	}
+	ks := ctx.NodeContext.AccountManager.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		return nil, err
+	}
+	tomoX := tomox.New(&tomox.Config{DataDir: filepath.Join(n.datadir, ctx.Config.Name, "tomox")})
+
+	config := eth.DefaultConfig
+	config.Genesis = n.genesis
+	config.NetworkId = n.config.ChainId
+	config.SyncMode = downloader.FullSync
+	config.Etherbase = account.Address
+	return eth.New(ctx.NodeContext, &config, tomoX, tomoxlending.New(tomoX))
+}
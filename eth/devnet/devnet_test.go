@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package devnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+)
+
+func TestGenesis(t *testing.T) {
+	network, err := New(DefaultConfig)
+	if err != nil {
+		t.Fatalf("failed to create devnet: %v", err)
+	}
+	genesis := network.Genesis()
+	for _, addr := range []string{common.MasternodeVotingSMC, common.BlockSigners, common.RandomizeSMC} {
+		if len(genesis.Alloc[common.HexToAddress(addr)].Code) == 0 {
+			t.Errorf("contract %s not deployed in genesis", addr)
+		}
+	}
+	db := rawdb.NewMemoryDatabase()
+	block := genesis.MustCommit(db)
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	candidates := make(map[common.Address]bool)
+	for _, candidate := range state.GetCandidates(statedb) {
+		candidates[candidate] = true
+	}
+	for _, masternode := range network.Masternodes() {
+		if !candidates[masternode] {
+			t.Errorf("masternode %x is not a candidate", masternode)
+		}
+	}
+}
+
+func TestNetworkSealsEpochs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping devnet run in short mode")
+	}
+	config := DefaultConfig
+	config.Period, config.Epoch, config.Gap = 1, 10, 3
+
+	network, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create devnet: %v", err)
+	}
+	if err := network.Start(); err != nil {
+		t.Fatalf("failed to start devnet: %v", err)
+	}
+	defer network.Stop()
+
+	target := config.Epoch + 2
+	deadline := time.Now().Add(2 * time.Minute)
+	for i := 0; i < config.Masternodes; i++ {
+		ethereum, err := network.Ethereum(i)
+		if err != nil {
+			t.Fatalf("masternode %d: %v", i, err)
+		}
+		for ethereum.BlockChain().CurrentBlock().NumberU64() < target {
+			if time.Now().After(deadline) {
+				t.Fatalf("masternode %d stuck at block %d", i, ethereum.BlockChain().CurrentBlock().NumberU64())
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	first, _ := network.Ethereum(0)
+	checkpoint := first.BlockChain().GetHeaderByNumber(config.Epoch)
+	if len(checkpoint.Validators) == 0 {
+		t.Errorf("checkpoint %d carries no validators", config.Epoch)
+	}
+}
@@ -78,6 +78,7 @@ var (
 	errPeersUnavailable        = errors.New("no peers available or all tried for download")
 	errInvalidAncestor         = errors.New("retrieved ancestor is invalid")
 	errInvalidChain            = errors.New("retrieved hash chain is invalid")
+	errCheckpointMismatch      = errors.New("trusted checkpoint mismatch")
 	errInvalidBlock            = errors.New("retrieved block is invalid")
 	errInvalidBody             = errors.New("retrieved block body is invalid")
 	errInvalidReceipt          = errors.New("retrieved receipt is invalid")
@@ -113,6 +114,8 @@ type Downloader struct {
 	lightchain LightChain
 	blockchain BlockChain
 
+	checkpoint *params.TrustedCheckpoint // Trusted PoSV checkpoint the fast synced chain must go through
+
 	// Callbacks
 	dropPeer peerDropFn // Drops a peer for misbehaving
 
@@ -198,8 +201,9 @@ type BlockChain interface {
 	InsertReceiptChain(types.Blocks, []types.Receipts) (int, error)
 }
 
-// New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+// New creates a new downloader to fetch hashes and blocks from remote peers. The
+// optional trusted checkpoint is only used during fast sync.
+func New(mode SyncMode, checkpoint *params.TrustedCheckpoint, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
@@ -214,6 +218,7 @@ func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockC
 		rttConfidence:  uint64(1000000),
 		blockchain:     chain,
 		lightchain:     lightchain,
+		checkpoint:     checkpoint,
 		dropPeer:       dropPeer,
 		headerCh:       make(chan dataPack, 1),
 		bodyCh:         make(chan dataPack, 1),
@@ -322,7 +327,7 @@ func (d *Downloader) Synchronise(id string, head common.Hash, td *big.Int, mode
 
 	case errTimeout, errBadPeer, errStallingPeer,
 		errEmptyHeaderSet, errPeersUnavailable, errTooOld,
-		errInvalidAncestor, errInvalidChain:
+		errInvalidAncestor, errInvalidChain, errCheckpointMismatch:
 		log.Warn("Synchronisation failed, dropping peer", "peer", id, "err", err)
 		if d.dropPeer == nil {
 			// The dropPeer method is nil when `--copydb` is used for a local copy.
@@ -432,6 +437,11 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if err != nil {
 		return err
 	}
+	if d.mode == FastSync && !d.checkpoint.Empty() && origin < d.checkpoint.Number && height > d.checkpoint.Number {
+		if err := d.fetchCheckpoint(p); err != nil {
+			return err
+		}
+	}
 	d.syncStatsLock.Lock()
 	if d.syncStatsChainHeight <= origin || d.syncStatsChainOrigin > origin {
 		d.syncStatsChainOrigin = origin
@@ -580,6 +590,69 @@ func (d *Downloader) fetchHeight(p *peerConnection, hash common.Hash) (*types.He
 	}
 }
 
+// fetchCheckpoint retrieves the trusted checkpoint header from the peer and makes
+// sure it matches the local one, so that a peer on another chain is dropped before
+// any of its headers is imported.
+func (d *Downloader) fetchCheckpoint(p *peerConnection) error {
+	go p.peer.RequestHeadersByNumber(d.checkpoint.Number, 1, 0, false)
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return errCancelBlockFetch
+
+		case packet := <-d.headerCh:
+			// Discard anything not from the origin peer
+			if packet.PeerId() != p.id {
+				log.Debug("Received headers from incorrect peer", "peer", packet.PeerId())
+				break
+			}
+			headers := packet.(*headerPack).headers
+			if len(headers) != 1 {
+				p.log.Debug("Multiple headers for single request", "headers", len(headers))
+				return errBadPeer
+			}
+			header := headers[0]
+			if header.Number.Uint64() != d.checkpoint.Number || header.Hash() != d.checkpoint.Hash {
+				p.log.Debug("Trusted checkpoint mismatch", "number", header.Number, "hash", header.Hash(), "want", d.checkpoint.Hash)
+				return errCheckpointMismatch
+			}
+			p.log.Debug("Trusted checkpoint identified", "number", header.Number, "hash", header.Hash())
+			return nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for checkpoint header timed out", "elapsed", ttl)
+			return errTimeout
+
+		case <-d.bodyCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
+
+// checkTrustedHeaders makes sure that the trusted checkpoint and the gap block
+// its snapshot was taken at are the expected ones if the chunk contains them.
+// The headers below the checkpoint are still verified like any other, as the
+// peer could serve a forged chain which only links to the checkpoint by number.
+func (d *Downloader) checkTrustedHeaders(chunk []*types.Header) error {
+	if d.mode != FastSync || d.checkpoint.Empty() {
+		return nil
+	}
+	config := d.blockchain.Config().Posv
+	for _, header := range chunk {
+		switch number := header.Number.Uint64(); {
+		case number == d.checkpoint.Number && header.Hash() != d.checkpoint.Hash:
+			return errCheckpointMismatch
+		case config != nil && number == d.checkpoint.SnapshotNumber(config) && header.Hash() != d.checkpoint.SnapshotHash:
+			return errCheckpointMismatch
+		}
+	}
+	return nil
+}
+
 // findAncestor tries to locate the common ancestor link of the local chain and
 // a remote peers blockchain. In the general case when our node was in sync and
 // on the correct chain, checking the top N links should already get us a match.
@@ -1266,6 +1339,11 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 					if chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
 						frequency = 1
 					}
+					// The chain must go through the trusted checkpoint, if any
+					if err := d.checkTrustedHeaders(chunk); err != nil {
+						log.Debug("Trusted checkpoint mismatch", "number", d.checkpoint.Number, "hash", d.checkpoint.Hash)
+						return err
+					}
 					if n, err := d.lightchain.InsertHeaderChain(chunk, frequency); err != nil {
 						// If some headers were inserted, add them too to the rollback list
 						if n > 0 {
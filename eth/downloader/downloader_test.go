@@ -62,6 +62,7 @@ type downloadTester struct {
 	ownBlocks   map[common.Hash]*types.Block   // Blocks belonging to the tester
 	ownReceipts map[common.Hash]types.Receipts // Receipts belonging to the tester
 	ownChainTd  map[common.Hash]*big.Int       // Total difficulties of the blocks in the local chain
+	unsealed    map[common.Hash]bool           // Headers whose seal fails to verify
 
 	peerHashes   map[string][]common.Hash                  // Hash chain belonging to different test peers
 	peerHeaders  map[string]map[common.Hash]*types.Header  // Headers belonging to different test peers
@@ -87,6 +88,7 @@ func newTester() *downloadTester {
 		ownBlocks:         map[common.Hash]*types.Block{genesis.Hash(): genesis},
 		ownReceipts:       map[common.Hash]types.Receipts{genesis.Hash(): nil},
 		ownChainTd:        map[common.Hash]*big.Int{genesis.Hash(): genesis.Difficulty()},
+		unsealed:          make(map[common.Hash]bool),
 		peerHashes:        make(map[string][]common.Hash),
 		peerHeaders:       make(map[string]map[common.Hash]*types.Header),
 		peerBlocks:        make(map[string]map[common.Hash]*types.Block),
@@ -97,7 +99,7 @@ func newTester() *downloadTester {
 	tester.stateDb= rawdb.NewMemoryDatabase()
 	tester.stateDb.Put(genesis.Root().Bytes(), []byte{0x00})
 
-	tester.downloader = New(FullSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
+	tester.downloader = New(FullSync, nil, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
 
 	return tester
 }
@@ -316,6 +318,12 @@ func (dl *downloadTester) InsertHeaderChain(headers []*types.Header, checkFreq i
 			return i, errors.New("unknown parent")
 		}
 	}
+	// Verify the seals of the sampled headers
+	for i, header := range headers {
+		if checkFreq > 0 && (i%checkFreq == 0 || i == len(headers)-1) && dl.unsealed[header.Hash()] {
+			return i, errors.New("invalid seal")
+		}
+	}
 	// Do a full insert if pre-checks passed
 	for i, header := range headers {
 		if _, ok := dl.ownHeaders[header.Hash()]; ok {
@@ -1757,3 +1765,105 @@ func testDeliverHeadersHang(t *testing.T, protocol int, mode SyncMode) {
 		tester.downloader.peers.peers["peer"].peer.(*floodingTestPeer).pend.Wait()
 	}
 }
+
+// Tests that fast sync checks the trusted checkpoint against the peer and syncs
+// the chain going through it.
+func TestTrustedCheckpoint63(t *testing.T) { testTrustedCheckpoint(t, 63) }
+func TestTrustedCheckpoint64(t *testing.T) { testTrustedCheckpoint(t, 64) }
+
+func testTrustedCheckpoint(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Empty blocks are enough, build them on a copy of the genesis block to leave
+	// the bonus transactions out
+	targetBlocks := blockCacheItems - 15
+	genesis := types.NewBlockWithHeader(tester.genesis.Header())
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, genesis, nil, false)
+
+	number := uint64(targetBlocks / 2)
+	tester.downloader.checkpoint = &params.TrustedCheckpoint{
+		Number: number,
+		Hash:   hashes[len(hashes)-1-int(number)],
+	}
+	tester.newPeer("peer", protocol, hashes, headers, blocks, receipts)
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, targetBlocks+1)
+}
+
+// Tests that a peer whose chain doesn't contain the trusted checkpoint is
+// refused before any of its headers is imported.
+func TestTrustedCheckpointMismatch63(t *testing.T) { testTrustedCheckpointMismatch(t, 63) }
+func TestTrustedCheckpointMismatch64(t *testing.T) { testTrustedCheckpointMismatch(t, 64) }
+
+func testTrustedCheckpointMismatch(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	targetBlocks := blockCacheItems - 15
+	genesis := types.NewBlockWithHeader(tester.genesis.Header())
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, genesis, nil, false)
+
+	tester.downloader.checkpoint = &params.TrustedCheckpoint{
+		Number: uint64(targetBlocks / 2),
+		Hash:   common.HexToHash("0xdeadbeef"),
+	}
+	tester.newPeer("peer", protocol, hashes, headers, blocks, receipts)
+	if err := tester.sync("peer", nil, FastSync); err != errCheckpointMismatch {
+		t.Fatalf("synchronisation error mismatch: have %v, want %v", err, errCheckpointMismatch)
+	}
+	assertOwnChain(t, tester, 1)
+}
+
+// Tests that a peer serving the trusted checkpoint on top of a forged chain is
+// refused without any of the forged headers being kept.
+func TestTrustedCheckpointForged63(t *testing.T) { testTrustedCheckpointForged(t, 63) }
+func TestTrustedCheckpointForged64(t *testing.T) { testTrustedCheckpointForged(t, 64) }
+
+func testTrustedCheckpointForged(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Forge more headers than the rollback safety net covers
+	targetBlocks := 3 * fsHeaderSafetyNet
+	genesis := types.NewBlockWithHeader(tester.genesis.Header())
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, genesis, nil, false)
+
+	number := 2*fsHeaderSafetyNet + MaxHeaderFetch/2
+	tester.downloader.checkpoint = &params.TrustedCheckpoint{
+		Number: uint64(number),
+		Hash:   hashes[len(hashes)-1-number],
+	}
+	tester.newPeer("peer", protocol, hashes, headers, blocks, receipts)
+
+	// Replace the headers below the checkpoint with a forged chain whose seals
+	// don't verify
+	forgedHashes, forgedHeaders, forgedBlocks, forgedReceipts := tester.makeChain(number-1, 1, genesis, nil, false)
+	tester.lock.Lock()
+	for i := 1; i < number; i++ {
+		hash := forgedHashes[len(forgedHashes)-1-i]
+		tester.peerHashes["peer"][len(hashes)-1-i] = hash
+		tester.peerHeaders["peer"][hash] = forgedHeaders[hash]
+		tester.peerBlocks["peer"][hash] = forgedBlocks[hash]
+		tester.peerReceipts["peer"][hash] = forgedReceipts[hash]
+		tester.unsealed[hash] = true
+	}
+	tester.lock.Unlock()
+
+	if err := tester.sync("peer", nil, FastSync); err == nil {
+		t.Fatalf("forged chain synchronised")
+	}
+	for hash := range tester.unsealed {
+		if _, ok := tester.ownHeaders[hash]; ok {
+			t.Fatalf("forged header #%d kept", forgedHeaders[hash].Number)
+		}
+	}
+}
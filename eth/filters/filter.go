@@ -42,6 +42,7 @@ type Backend interface {
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+	SubscribeFinalizedHeadEvent(ch chan<- core.FinalizedHeadEvent) event.Subscription
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
@@ -136,6 +137,21 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 	head := header.Number.Uint64()
 
+	if f.begin == rpc.FinalizedBlockNumber.Int64() || f.end == rpc.FinalizedBlockNumber.Int64() {
+		finalized, err := f.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if finalized == nil {
+			return nil, errors.New("finalized block not found")
+		}
+		if f.begin == rpc.FinalizedBlockNumber.Int64() {
+			f.begin = finalized.Number.Int64()
+		}
+		if f.end == rpc.FinalizedBlockNumber.Int64() {
+			f.end = finalized.Number.Int64()
+		}
+	}
 	if f.begin == -1 {
 		f.begin = int64(head)
 	}
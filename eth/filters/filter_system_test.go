@@ -39,13 +39,14 @@ import (
 )
 
 type testBackend struct {
-	mux        *event.TypeMux
-	db         ethdb.Database
-	sections   uint64
-	txFeed     *event.Feed
-	rmLogsFeed *event.Feed
-	logsFeed   *event.Feed
-	chainFeed  *event.Feed
+	mux           *event.TypeMux
+	db            ethdb.Database
+	sections      uint64
+	txFeed        *event.Feed
+	rmLogsFeed    *event.Feed
+	logsFeed      *event.Feed
+	chainFeed     *event.Feed
+	finalizedFeed *event.Feed
 }
 
 func (b *testBackend) ChainDb() ethdb.Database {
@@ -62,6 +63,12 @@ func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumbe
 	if blockNr == rpc.LatestBlockNumber {
 		hash = core.GetHeadBlockHash(b.db)
 		num = core.GetBlockNumber(b.db, hash)
+	} else if blockNr == rpc.FinalizedBlockNumber {
+		hash = core.GetHeadFinalizedBlockHash(b.db)
+		if hash == (common.Hash{}) {
+			return nil, nil
+		}
+		num = core.GetBlockNumber(b.db, hash)
 	} else {
 		num = uint64(blockNr)
 		hash = core.GetCanonicalHash(b.db, num)
@@ -106,6 +113,10 @@ func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subsc
 	return b.chainFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeFinalizedHeadEvent(ch chan<- core.FinalizedHeadEvent) event.Subscription {
+	return b.finalizedFeed.Subscribe(ch)
+}
+
 func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
@@ -152,7 +163,7 @@ func TestBlockSubscription(t *testing.T) {
 		rmLogsFeed  = new(event.Feed)
 		logsFeed    = new(event.Feed)
 		chainFeed   = new(event.Feed)
-		backend     = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend     = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api         = NewPublicFilterAPI(backend, false)
 		genesis     = new(core.Genesis).MustCommit(db)
 		chain, _    = core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
@@ -198,6 +209,54 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestFinalizedHeadSubscription tests if a finalized head subscription returns
+// the headers of the finalized blocks.
+func TestFinalizedHeadSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux             = new(event.TypeMux)
+		db              = rawdb.NewMemoryDatabase()
+		txFeed          = new(event.Feed)
+		rmLogsFeed      = new(event.Feed)
+		logsFeed        = new(event.Feed)
+		chainFeed       = new(event.Feed)
+		finalizedFeed   = new(event.Feed)
+		backend         = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, finalizedFeed}
+		api             = NewPublicFilterAPI(backend, false)
+		genesis         = new(core.Genesis).MustCommit(db)
+		chain, _        = core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
+		finalizedEvents = []core.FinalizedHeadEvent{}
+	)
+
+	for _, blk := range chain {
+		finalizedEvents = append(finalizedEvents, core.FinalizedHeadEvent{Block: blk})
+	}
+
+	headers := make(chan *types.Header)
+	sub := api.events.SubscribeNewFinalizedHeads(headers)
+
+	go func() { // simulate client
+		for i := 0; i != len(finalizedEvents); {
+			header := <-headers
+			if finalizedEvents[i].Block.Hash() != header.Hash() {
+				t.Errorf("received invalid hash on index %d, want %x, got %x", i, finalizedEvents[i].Block.Hash(), header.Hash())
+			}
+			i++
+		}
+		sub.Unsubscribe()
+	}()
+
+	time.Sleep(1 * time.Second)
+	for _, e := range finalizedEvents {
+		// Plain chain events must not be delivered to finalized head subscribers
+		chainFeed.Send(core.ChainEvent{Hash: e.Block.Hash(), Block: e.Block})
+		finalizedFeed.Send(e)
+	}
+
+	<-sub.Err()
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
@@ -209,7 +268,7 @@ func TestPendingTxFilter(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		transactions = []*types.Transaction{
@@ -272,7 +331,7 @@ func TestLogFilterCreation(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		testCases = []struct {
@@ -321,7 +380,7 @@ func TestInvalidLogFilterCreation(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 	)
 
@@ -348,7 +407,7 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 		blockHash  = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
 	)
@@ -378,7 +437,7 @@ func TestLogFilter(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
@@ -497,7 +556,7 @@ func TestPendingLogsSubscription(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		api        = NewPublicFilterAPI(backend, false)
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
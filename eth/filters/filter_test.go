@@ -31,6 +31,7 @@ import (
 	"github.com/tomochain/tomochain/crypto"
 	"github.com/tomochain/tomochain/event"
 	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
 )
 
 func makeReceipt(addr common.Address) *types.Receipt {
@@ -56,7 +57,7 @@ func BenchmarkFilters(b *testing.B) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		addr1      = crypto.PubkeyToAddress(key1.PublicKey)
 		addr2      = common.BytesToAddress([]byte("jeff"))
@@ -121,7 +122,7 @@ func TestFilters(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed, new(event.Feed)}
 		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		addr       = crypto.PubkeyToAddress(key1.PublicKey)
 
@@ -211,6 +212,24 @@ func TestFilters(t *testing.T) {
 		t.Errorf("expected log[0].Topics[0] to be %x, got %x", hash3, logs[0].Topics[0])
 	}
 
+	// the finalized tag resolves to the finalized head, not to the genesis
+	if _, err := NewRangeFilter(backend, rpc.FinalizedBlockNumber.Int64(), -1, nil, nil).Logs(context.Background()); err == nil {
+		t.Error("expected an error without a finalized block")
+	}
+	if err := core.WriteHeadFinalizedBlockHash(db, chain[998].Hash()); err != nil {
+		t.Fatal(err)
+	}
+	filter = NewRangeFilter(backend, rpc.FinalizedBlockNumber.Int64(), -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, _ = filter.Logs(context.Background())
+	if len(logs) != 2 {
+		t.Error("expected 2 log from the finalized block, got", len(logs))
+	}
+	filter = NewRangeFilter(backend, 0, rpc.FinalizedBlockNumber.Int64(), []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, _ = filter.Logs(context.Background())
+	if len(logs) != 3 {
+		t.Error("expected 3 log up to the finalized block, got", len(logs))
+	}
+
 	filter = NewRangeFilter(backend, 1, 10, nil, [][]common.Hash{{hash1, hash2}})
 
 	logs, _ = filter.Logs(context.Background())
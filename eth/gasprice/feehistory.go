@@ -0,0 +1,262 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+const (
+	// maxFeeHistory is the maximum number of blocks that can be retrieved for a
+	// fee history request.
+	maxFeeHistory = 1024
+
+	// feeCacheSize is the number of block gas price distributions kept in
+	// memory, enough to serve the maximum fee history twice.
+	feeCacheSize = 2 * maxFeeHistory
+)
+
+// txGasAndPrice is the gas used by a transaction and the price paid for it.
+type txGasAndPrice struct {
+	gasUsed uint64
+	price   *big.Int
+}
+
+// blockFees is the gas price distribution of a block. The transactions whose
+// fee was paid by a TRC21 token issuer are only accounted in sponsoredGasUsed,
+// their zero gas price doesn't reflect what senders pay to get included.
+type blockFees struct {
+	gasUsed          uint64
+	gasLimit         uint64
+	sponsoredGasUsed uint64
+	txs              []txGasAndPrice // Priced transactions, sorted by increasing price
+}
+
+// gasUsedRatio returns the ratio of the block gas limit used by all the
+// transactions of the block.
+func (f *blockFees) gasUsedRatio() float64 {
+	if f.gasLimit == 0 {
+		return 0
+	}
+	return float64(f.gasUsed) / float64(f.gasLimit)
+}
+
+// sponsoredGasUsedRatio returns the ratio of the block gas limit used by the
+// sponsored transactions of the block.
+func (f *blockFees) sponsoredGasUsedRatio() float64 {
+	if f.gasLimit == 0 {
+		return 0
+	}
+	return float64(f.sponsoredGasUsed) / float64(f.gasLimit)
+}
+
+// rewards returns the gas prices at the given percentiles of the gas used by
+// the priced transactions of the block, zero for blocks without any.
+func (f *blockFees) rewards(percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	if len(f.txs) == 0 {
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+	var total uint64
+	for _, tx := range f.txs {
+		total += tx.gasUsed
+	}
+	var txIndex int
+	sumGasUsed := f.txs[0].gasUsed
+
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(total) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(f.txs)-1 {
+			txIndex++
+			sumGasUsed += f.txs[txIndex].gasUsed
+		}
+		rewards[i] = new(big.Int).Set(f.txs[txIndex].price)
+	}
+	return rewards
+}
+
+// blockFees retrieves the gas price distribution of a block, from the cache if
+// it was already computed.
+func (gpo *Oracle) blockFees(ctx context.Context, block *types.Block) (*blockFees, error) {
+	if fees, ok := gpo.feeCache.Get(block.Hash()); ok {
+		return fees.(*blockFees), nil
+	}
+	fees := &blockFees{
+		gasUsed:  block.GasUsed(),
+		gasLimit: block.GasLimit(),
+	}
+	if len(block.Transactions()) > 0 {
+		// Transactions are weighted by their gas limit if the receipts are
+		// not available
+		receipts, err := gpo.backend.GetReceipts(ctx, block.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if len(receipts) != len(block.Transactions()) {
+			receipts = nil
+		}
+		// The tokens sponsoring fees are the ones with a fee capacity when the
+		// block started. Without the parent state, sponsored transactions are
+		// recognized by their zero gas price which the pool only accepts for them.
+		var tokens map[common.Address]*big.Int
+		parent, header, err := gpo.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(block.ParentHash(), false))
+		if err == nil && parent != nil && header != nil {
+			tokens = state.GetTRC21FeeCapacityFromStateWithCache(header.Root, parent)
+		}
+		signer := types.MakeSigner(gpo.backend.ChainConfig(), block.Number())
+		for i, tx := range block.Transactions() {
+			// System transactions of the masternodes are free
+			if tx.IsSpecialTransaction() {
+				continue
+			}
+			// Block producers can include their own transactions at any price
+			if sender, err := types.Sender(signer, tx); err != nil || sender == block.Coinbase() {
+				continue
+			}
+			gasUsed := tx.Gas()
+			if receipts != nil {
+				gasUsed = receipts[i].GasUsed
+			}
+			if tx.To() != nil && ((tokens != nil && tokens[*tx.To()] != nil) || (tokens == nil && tx.GasPrice().Sign() == 0)) {
+				fees.sponsoredGasUsed += gasUsed
+				continue
+			}
+			fees.txs = append(fees.txs, txGasAndPrice{gasUsed: gasUsed, price: tx.GasPrice()})
+		}
+		sort.Slice(fees.txs, func(i, j int) bool {
+			return fees.txs[i].price.Cmp(fees.txs[j].price) < 0
+		})
+	}
+	gpo.feeCache.Add(block.Hash(), fees)
+	return fees, nil
+}
+
+// FeeHistory returns data relevant for fee estimation based on the specified range of blocks.
+// The range can be specified either with absolute block numbers or ending with the latest,
+// finalized or pending block. Since blocks have no base fee, the reward of a block at a
+// percentile is the gas price paid by the transaction containing the gas used
+// at that percentile, excluding the transactions sponsored by TRC21 token
+// issuers. The following data is returned for each block:
+// - gas used ratio
+// - gas used ratio of the sponsored transactions
+// - (optional) gas prices at the requested percentiles
+//
+// Note: the returned values are ordered from the oldest to the newest block.
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []float64, []float64, error) {
+	if blocks < 1 {
+		return common.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+	}
+	if blocks > maxFeeHistory {
+		blocks = maxFeeHistory
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	// Resolve the last block, the pending one being only known by the miner. If
+	// it isn't available the history ends with the latest block.
+	var pending *types.Block
+	if lastBlock == rpc.PendingBlockNumber {
+		if pending, _ = gpo.backend.BlockByNumber(ctx, rpc.PendingBlockNumber); pending == nil {
+			lastBlock = rpc.LatestBlockNumber
+		}
+	}
+	var last uint64
+	switch {
+	case pending != nil:
+		last = pending.NumberU64()
+	case lastBlock < 0:
+		header, err := gpo.backend.HeaderByNumber(ctx, lastBlock)
+		if header == nil || err != nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		last = header.Number.Uint64()
+	default:
+		head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if err != nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		if uint64(lastBlock) > head.Number.Uint64() {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, head.Number)
+		}
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	oldest := last + 1 - uint64(blocks)
+
+	var (
+		reward                [][]*big.Int
+		gasUsedRatio          = make([]float64, blocks)
+		sponsoredGasUsedRatio = make([]float64, blocks)
+	)
+	if len(rewardPercentiles) > 0 {
+		reward = make([][]*big.Int, blocks)
+	}
+	for i := 0; i < blocks; i++ {
+		var (
+			number = oldest + uint64(i)
+			block  = pending
+			err    error
+		)
+		if pending == nil || number != last {
+			block, err = gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		}
+		if block == nil || err != nil {
+			// Blocks missing from a light client end the history
+			if i == 0 {
+				return common.Big0, nil, nil, nil, err
+			}
+			if reward != nil {
+				reward = reward[:i]
+			}
+			return new(big.Int).SetUint64(oldest), reward, gasUsedRatio[:i], sponsoredGasUsedRatio[:i], nil
+		}
+		fees, err := gpo.blockFees(ctx, block)
+		if err != nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		gasUsedRatio[i] = fees.gasUsedRatio()
+		sponsoredGasUsedRatio[i] = fees.sponsoredGasUsedRatio()
+		if reward != nil {
+			reward[i] = fees.rewards(rewardPercentiles)
+		}
+	}
+	return new(big.Int).SetUint64(oldest), reward, gasUsedRatio, sponsoredGasUsedRatio, nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/crypto"
+	"github.com/tomochain/tomochain/internal/ethapi"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+// testBackend serves a fixed chain to the oracle. The methods the oracle
+// doesn't use are left to the nil embedded interface.
+type testBackend struct {
+	ethapi.Backend
+	blocks   []*types.Block
+	pending  *types.Block // Block of the miner, nil if unavailable
+	receipts map[common.Hash]types.Receipts
+	state    *state.StateDB // Parent state of every block, nil if unavailable
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	switch number {
+	case rpc.PendingBlockNumber:
+		return b.pending, nil
+	case rpc.FinalizedBlockNumber:
+		return b.blocks[0], nil
+	case rpc.LatestBlockNumber:
+		return b.blocks[len(b.blocks)-1], nil
+	}
+	if int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if b.state == nil {
+		return nil, nil, errors.New("missing trie node")
+	}
+	return b.state, &types.Header{Root: b.state.IntermediateRoot(false)}, nil
+}
+
+var (
+	testKey, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	minerKey, _    = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	testRecipient  = common.HexToAddress("0x0000000000000000000000000000000000001234")
+	testTRC21Token = common.HexToAddress("0x0000000000000000000000000000000000005678")
+)
+
+// newTestBackend creates a chain of an empty genesis followed by a block with
+// two priced transactions, one sponsored by a TRC21 issuer and one of the
+// block producer. The genesis is finalized and the miner has an empty pending
+// block on top of the chain.
+func newTestBackend(t *testing.T, sponsoredPrice *big.Int, withState bool) *testBackend {
+	signer := types.MakeSigner(params.TestChainConfig, big.NewInt(1))
+	miner := crypto.PubkeyToAddress(minerKey.PublicKey)
+
+	var (
+		txs      []*types.Transaction
+		receipts types.Receipts
+		gasUsed  uint64
+	)
+	add := func(key *ecdsa.PrivateKey, nonce uint64, to common.Address, price int64, used uint64) {
+		tx, err := types.SignTx(types.NewTransaction(nonce, to, new(big.Int), used, big.NewInt(price), nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		gasUsed += used
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{CumulativeGasUsed: gasUsed, GasUsed: used})
+	}
+	add(testKey, 0, testRecipient, 3*params.Shannon, 63000)
+	add(testKey, 1, testRecipient, params.Shannon, 21000)
+	add(testKey, 2, testTRC21Token, sponsoredPrice.Int64(), 50000)
+	add(minerKey, 0, testRecipient, 0, 21000)
+
+	genesis := types.NewBlock(&types.Header{Number: big.NewInt(0), GasLimit: 1000000}, nil, nil, nil)
+	block := types.NewBlock(&types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   1000000,
+		GasUsed:    gasUsed,
+		Coinbase:   miner,
+	}, txs, nil, receipts)
+
+	pending := types.NewBlock(&types.Header{
+		ParentHash: block.Hash(),
+		Number:     big.NewInt(2),
+		GasLimit:   1000000,
+		Coinbase:   miner,
+	}, nil, nil, nil)
+
+	backend := &testBackend{
+		blocks:   []*types.Block{genesis, block},
+		pending:  pending,
+		receipts: map[common.Hash]types.Receipts{block.Hash(): receipts},
+	}
+	if withState {
+		// Register the token in the issuer contract with some fee capacity
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		tokens := common.BigToHash(new(big.Int).SetUint64(state.SlotTRC21Issuer["tokens"]))
+		statedb.SetState(common.TRC21IssuerSMC, tokens, common.BigToHash(big.NewInt(1)))
+		statedb.SetState(common.TRC21IssuerSMC, state.GetLocDynamicArrAtElement(tokens, 0, 1), testTRC21Token.Hash())
+		capacity := state.GetLocMappingAtKey(testTRC21Token.Hash(), state.SlotTRC21Issuer["tokensState"])
+		statedb.SetState(common.TRC21IssuerSMC, common.BigToHash(capacity), common.BigToHash(big.NewInt(params.Ether)))
+		backend.state = statedb
+	}
+	return backend
+}
+
+func TestFeeHistory(t *testing.T) {
+	var cases = []struct {
+		name           string
+		sponsoredPrice int64
+		withState      bool
+		count          int
+		last           rpc.BlockNumber
+		percent        []float64
+		expFirst       uint64
+		expCount       int
+		expReward      []int64
+		expSponsored   float64
+		expErr         error
+	}{
+		{"latest", 0, false, 1, rpc.LatestBlockNumber, []float64{0, 25, 50, 100}, 1, 1, []int64{params.Shannon, params.Shannon, 3 * params.Shannon, 3 * params.Shannon}, 0.05, nil},
+		{"range", 0, false, 5, 1, nil, 0, 2, nil, 0.05, nil},
+		{"finalized", 0, false, 5, rpc.FinalizedBlockNumber, []float64{50}, 0, 1, []int64{0}, 0, nil},
+		{"pending", 0, false, 2, rpc.PendingBlockNumber, []float64{50}, 1, 2, []int64{0}, 0, nil},
+		{"genesis", 0, false, 1, 0, []float64{50}, 0, 1, []int64{0}, 0, nil},
+		{"sponsored by state", 2 * params.Shannon, true, 1, 1, []float64{0, 30}, 1, 1, []int64{params.Shannon, 3 * params.Shannon}, 0.05, nil},
+		{"priced without state", 2 * params.Shannon, false, 1, 1, []float64{0, 30}, 1, 1, []int64{params.Shannon, 2 * params.Shannon}, 0, nil},
+		{"empty", 0, false, 0, 1, nil, 0, 0, nil, 0, nil},
+		{"beyond head", 0, false, 1, 2, nil, 0, 0, nil, 0, errRequestBeyondHead},
+		{"invalid percentile", 0, false, 1, 1, []float64{101}, 0, 0, nil, 0, errInvalidPercentile},
+		{"unordered percentiles", 0, false, 1, 1, []float64{50, 10}, 0, 0, nil, 0, errInvalidPercentile},
+	}
+	for _, c := range cases {
+		oracle := NewOracle(newTestBackend(t, big.NewInt(c.sponsoredPrice), c.withState), Config{Blocks: 20, Percentile: 60})
+
+		first, reward, ratio, sponsored, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
+		if !errors.Is(err, c.expErr) {
+			t.Fatalf("%s: error mismatch: have %v, want %v", c.name, err, c.expErr)
+		}
+		if err != nil {
+			continue
+		}
+		if first.Uint64() != c.expFirst {
+			t.Errorf("%s: first block mismatch: have %d, want %d", c.name, first, c.expFirst)
+		}
+		if len(ratio) != c.expCount || len(sponsored) != c.expCount {
+			t.Fatalf("%s: block count mismatch: have %d/%d, want %d", c.name, len(ratio), len(sponsored), c.expCount)
+		}
+		if c.expCount > 0 && sponsored[len(sponsored)-1] != c.expSponsored {
+			t.Errorf("%s: sponsored gas ratio mismatch: have %v, want %v", c.name, sponsored[len(sponsored)-1], c.expSponsored)
+		}
+		if c.percent == nil {
+			if reward != nil {
+				t.Errorf("%s: unexpected rewards %v", c.name, reward)
+			}
+			continue
+		}
+		if len(reward) != c.expCount {
+			t.Fatalf("%s: reward count mismatch: have %d, want %d", c.name, len(reward), c.expCount)
+		}
+		for i, want := range c.expReward {
+			if have := reward[len(reward)-1][i]; have.Int64() != want {
+				t.Errorf("%s: reward %d mismatch: have %v, want %v", c.name, i, have, want)
+			}
+		}
+	}
+}
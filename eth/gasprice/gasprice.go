@@ -22,6 +22,7 @@ import (
 	"sort"
 	"sync"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/internal/ethapi"
@@ -43,6 +44,7 @@ type Oracle struct {
 	backend   ethapi.Backend
 	lastHead  common.Hash
 	lastPrice *big.Int
+	feeCache  *lru.Cache // Gas price distributions of recent blocks by hash
 	cacheLock sync.RWMutex
 	fetchLock sync.Mutex
 
@@ -63,9 +65,11 @@ func NewOracle(backend ethapi.Backend, params Config) *Oracle {
 	if percent > 100 {
 		percent = 100
 	}
+	feeCache, _ := lru.New(feeCacheSize)
 	return &Oracle{
 		backend:     backend,
 		lastPrice:   params.Default,
+		feeCache:    feeCache,
 		checkBlocks: blocks,
 		maxEmpty:    blocks / 2,
 		maxBlocks:   blocks * 5,
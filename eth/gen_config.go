@@ -4,6 +4,7 @@ package eth
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/common/hexutil"
@@ -11,6 +12,7 @@ import (
 	"github.com/tomochain/tomochain/core"
 	"github.com/tomochain/tomochain/eth/downloader"
 	"github.com/tomochain/tomochain/eth/gasprice"
+	"github.com/tomochain/tomochain/params"
 )
 
 var _ = (*configMarshaling)(nil)
@@ -20,10 +22,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Genesis                 *core.Genesis `toml:",omitempty"`
 		NetworkId               uint64
 		SyncMode                downloader.SyncMode
-		LightServ               int  `toml:",omitempty"`
-		LightPeers              int  `toml:",omitempty"`
-		SkipBcVersionCheck      bool `toml:"-"`
-		DatabaseHandles         int  `toml:"-"`
+		AddressIndex            bool                      `toml:",omitempty"`
+		RevertReasons           bool                      `toml:",omitempty"`
+		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		LightServ               int                       `toml:",omitempty"`
+		LightPeers              int                       `toml:",omitempty"`
+		SkipBcVersionCheck      bool                      `toml:"-"`
+		DatabaseHandles         int                       `toml:"-"`
 		DatabaseCache           int
 		Etherbase               common.Address `toml:",omitempty"`
 		MinerThreads            int            `toml:",omitempty"`
@@ -33,12 +38,17 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
+		RPCGasCap               uint64
+		RPCEVMTimeout           time.Duration
 		DocRoot                 string `toml:"-"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
 	enc.NetworkId = c.NetworkId
 	enc.SyncMode = c.SyncMode
+	enc.AddressIndex = c.AddressIndex
+	enc.RevertReasons = c.RevertReasons
+	enc.Checkpoint = c.Checkpoint
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
@@ -52,6 +62,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.RPCGasCap = c.RPCGasCap
+	enc.RPCEVMTimeout = c.RPCEVMTimeout
 	enc.DocRoot = c.DocRoot
 	return &enc, nil
 }
@@ -61,10 +73,13 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Genesis                 *core.Genesis `toml:",omitempty"`
 		NetworkId               *uint64
 		SyncMode                *downloader.SyncMode
-		LightServ               *int  `toml:",omitempty"`
-		LightPeers              *int  `toml:",omitempty"`
-		SkipBcVersionCheck      *bool `toml:"-"`
-		DatabaseHandles         *int  `toml:"-"`
+		AddressIndex            *bool                     `toml:",omitempty"`
+		RevertReasons           *bool                     `toml:",omitempty"`
+		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		LightServ               *int                      `toml:",omitempty"`
+		LightPeers              *int                      `toml:",omitempty"`
+		SkipBcVersionCheck      *bool                     `toml:"-"`
+		DatabaseHandles         *int                      `toml:"-"`
 		DatabaseCache           *int
 		Etherbase               *common.Address `toml:",omitempty"`
 		MinerThreads            *int            `toml:",omitempty"`
@@ -74,6 +89,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
+		RPCGasCap               *uint64
+		RPCEVMTimeout           *time.Duration
 		DocRoot                 *string `toml:"-"`
 	}
 	var dec Config
@@ -89,6 +106,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SyncMode != nil {
 		c.SyncMode = *dec.SyncMode
 	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
+	if dec.RevertReasons != nil {
+		c.RevertReasons = *dec.RevertReasons
+	}
+	if dec.Checkpoint != nil {
+		c.Checkpoint = dec.Checkpoint
+	}
 	if dec.LightServ != nil {
 		c.LightServ = *dec.LightServ
 	}
@@ -128,6 +154,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.RPCGasCap != nil {
+		c.RPCGasCap = *dec.RPCGasCap
+	}
+	if dec.RPCEVMTimeout != nil {
+		c.RPCEVMTimeout = *dec.RPCEVMTimeout
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
@@ -0,0 +1,241 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/core/vm"
+)
+
+func init() {
+	registerNative("callTracer", newCallTracer)
+}
+
+// callLog is a log emitted by a call frame, reported when withLog is set.
+type callLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// callFrame is a single call in the call tree. The field order follows the
+// output of the JavaScript callTracer.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []callFrame     `json:"calls,omitempty"`
+	Logs    []callLog       `json:"logs,omitempty"`
+}
+
+// callTracerConfig is the configuration of the native callTracer.
+type callTracerConfig struct {
+	OnlyTopCall bool `json:"onlyTopCall"` // If true, call tracer won't collect any subcalls
+	WithLog     bool `json:"withLog"`     // If true, call tracer will collect event logs
+}
+
+// callTracer is the native implementation of the callTracer, reconstructing
+// the call tree from the call frames entered and exited by the EVM.
+type callTracer struct {
+	env       *vm.EVM
+	config    callTracerConfig
+	callstack []callFrame
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newCallTracer returns a native go tracer which tracks the call frames of a
+// transaction and yields them as a tree when the execution finishes.
+func newCallTracer(cfg json.RawMessage) (ResultTracer, error) {
+	var config callTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	// First callframe contains the transaction call and all subcalls
+	return &callTracer{config: config, callstack: make([]callFrame, 1)}, nil
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.env = env
+	t.callstack[0] = callFrame{
+		Type:  vm.CALL.String(),
+		From:  from,
+		To:    &to,
+		Input: common.CopyBytes(input),
+		Gas:   hexutil.Uint64(gas),
+	}
+	if create {
+		t.callstack[0].Type = vm.CREATE.String()
+	}
+	if value != nil {
+		t.callstack[0].Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to collect the emitted logs.
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		env.Cancel()
+		return nil
+	}
+	if err != nil || !t.config.WithLog {
+		return nil
+	}
+	// Only logs need to be captured via opcode processing, and only the top
+	// call when onlyTopCall is set
+	if t.config.OnlyTopCall && depth > 1 {
+		return nil
+	}
+	if op < vm.LOG0 || op > vm.LOG4 {
+		return nil
+	}
+	size := int(op - vm.LOG0)
+	offset, length := stack.Back(0).Int64(), stack.Back(1).Int64()
+	topics := make([]common.Hash, size)
+	for i := 0; i < size; i++ {
+		topics[i] = common.BigToHash(stack.Back(2 + i))
+	}
+	frame := &t.callstack[len(t.callstack)-1]
+	frame.Logs = append(frame.Logs, callLog{
+		Address: contract.Address(),
+		Topics:  topics,
+		Data:    memory.GetCopy(offset, length),
+	})
+	return nil
+}
+
+// CaptureEnter is called when the EVM enters a new call frame.
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	if t.config.OnlyTopCall {
+		return nil
+	}
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		return nil
+	}
+	call := callFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    &to,
+		Input: common.CopyBytes(input),
+		Gas:   hexutil.Uint64(gas),
+	}
+	if value != nil {
+		call.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.callstack = append(t.callstack, call)
+	return nil
+}
+
+// CaptureExit is called when the EVM exits a call frame, and moves the frame
+// into the calls of its parent.
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	if t.config.OnlyTopCall {
+		return nil
+	}
+	size := len(t.callstack)
+	if size <= 1 {
+		return nil
+	}
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	t.finish(&call, output, err)
+
+	size--
+	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
+	return nil
+}
+
+// CaptureFault implements the Tracer interface. Faults are reported through
+// the errors of the exited call frames.
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	t.callstack[0].GasUsed = hexutil.Uint64(gasUsed)
+	t.finish(&t.callstack[0], output, err)
+	return nil
+}
+
+// finish sets the outcome of a call frame. Like the JavaScript tracer, failed
+// frames report no output and failed creations no address, and the logs of
+// reverted frames are dropped as they never made it into the chain.
+func (t *callTracer) finish(call *callFrame, output []byte, err error) {
+	if err != nil {
+		call.Error = err.Error()
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			call.To = nil
+		}
+		clearLogs(call)
+		return
+	}
+	if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+		// The output of a creation is the deployed code
+		output = t.env.StateDB.GetCode(*call.To)
+	}
+	call.Output = common.CopyBytes(output)
+}
+
+// clearLogs drops the logs of a failed call frame and all its subcalls.
+func clearLogs(call *callFrame) {
+	call.Logs = nil
+	for i := range call.Calls {
+		clearLogs(&call.Calls[i])
+	}
+}
+
+// GetResult returns the json-encoded nested list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if len(t.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	res, err := json.Marshal(t.callstack[0])
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *callTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
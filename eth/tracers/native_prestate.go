@@ -0,0 +1,310 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/crypto"
+)
+
+func init() {
+	registerNative("prestateTracer", newPrestateTracer)
+}
+
+// prestateAccount is the state of a single account reported by the
+// prestateTracer. In diff mode only the modified fields are reported.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// exists reports whether the account had any state.
+func (a *prestateAccount) exists() bool {
+	return a.Nonce > 0 || len(a.Code) > 0 || len(a.Storage) > 0 || (a.Balance != nil && a.Balance.ToInt().Sign() != 0)
+}
+
+type prestate = map[common.Address]*prestateAccount
+
+// prestateTracerConfig is the configuration of the native prestateTracer.
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+}
+
+// prestateTracer is the native implementation of the prestateTracer. It
+// collects every account and storage slot touched by a transaction as it was
+// before the transaction, and in diff mode also the state after it.
+type prestateTracer struct {
+	env     *vm.EVM
+	config  prestateTracerConfig
+	pre     prestate
+	post    prestate
+	create  bool
+	to      common.Address
+	created map[common.Address]bool
+	deleted map[common.Address]bool
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newPrestateTracer returns a native go tracer which collects the state
+// accessed by a transaction.
+func newPrestateTracer(cfg json.RawMessage) (ResultTracer, error) {
+	var config prestateTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &prestateTracer{
+		config:  config,
+		pre:     prestate{},
+		post:    prestate{},
+		created: make(map[common.Address]bool),
+		deleted: make(map[common.Address]bool),
+	}, nil
+}
+
+// CaptureTxStart implements the TxTracer interface to look up the accounts
+// charged for the transaction before its gas is bought.
+func (t *prestateTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address) error {
+	t.env = env
+	if to == nil {
+		t.create = true
+		t.to = crypto.CreateAddress(from, env.StateDB.GetNonce(from))
+	} else {
+		t.to = *to
+		// Sponsored TRC21 transactions are paid from the fee capacity of the
+		// token in the issuer contract rather than by the sender
+		slot := common.BigToHash(state.GetLocMappingAtKey(to.Hash(), state.SlotTRC21Issuer["tokensState"]))
+		if env.StateDB.GetState(common.TRC21IssuerSMC, slot) != (common.Hash{}) {
+			t.lookupAccount(common.TRC21IssuerSMC)
+			t.lookupStorage(common.TRC21IssuerSMC, slot)
+		}
+	}
+	t.lookupAccount(from)
+	t.lookupAccount(t.to)
+	t.lookupAccount(env.Coinbase)
+	return nil
+}
+
+// CaptureTxEnd implements the TxTracer interface to collect the post state of
+// the touched accounts in diff mode.
+func (t *prestateTracer) CaptureTxEnd(restGas uint64) error {
+	if !t.config.DiffMode {
+		return nil
+	}
+	for addr, pre := range t.pre {
+		// The deleted account's state is pruned from `post` but kept in `pre`
+		if t.deleted[addr] {
+			continue
+		}
+		modified := false
+		post := new(prestateAccount)
+
+		if balance := t.env.StateDB.GetBalance(addr); pre.Balance.ToInt().Cmp(balance) != 0 {
+			modified = true
+			post.Balance = (*hexutil.Big)(balance)
+		}
+		if nonce := t.env.StateDB.GetNonce(addr); nonce != pre.Nonce {
+			modified = true
+			post.Nonce = nonce
+		}
+		if code := t.env.StateDB.GetCode(addr); !bytes.Equal(code, pre.Code) {
+			modified = true
+			post.Code = code
+		}
+		for key, val := range pre.Storage {
+			// don't include the empty slot
+			if val == (common.Hash{}) {
+				delete(pre.Storage, key)
+			}
+			newVal := t.env.StateDB.GetState(addr, key)
+			if val == newVal {
+				// Omit unchanged slots
+				delete(pre.Storage, key)
+			} else {
+				modified = true
+				if newVal != (common.Hash{}) {
+					if post.Storage == nil {
+						post.Storage = make(map[common.Hash]common.Hash)
+					}
+					post.Storage[key] = newVal
+				}
+			}
+		}
+		if modified {
+			t.post[addr] = post
+		} else {
+			// if state is not modified, then no need to include into the pre state
+			delete(t.pre, addr)
+		}
+	}
+	// the new created contracts' prestate were empty, so delete them
+	for addr := range t.created {
+		// the created contract maybe exists in statedb before the creating tx
+		if s := t.pre[addr]; s != nil && !s.exists() {
+			delete(t.pre, addr)
+		}
+	}
+	return nil
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing
+// operation. The accounts are only looked up here if the transaction level
+// hooks were not called, in which case the sender balance already lacks the
+// bought gas.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.env = env
+	if create {
+		t.create = true
+		t.created[to] = true
+	}
+	t.to = to
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+	t.lookupAccount(env.Coinbase)
+	return nil
+}
+
+// CaptureState implements the Tracer interface to look up the state touched
+// by every opcode.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		env.Cancel()
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	stackLen := len(stack.Data())
+	caller := contract.Address()
+	switch {
+	case stackLen >= 1 && (op == vm.SLOAD || op == vm.SSTORE):
+		t.lookupStorage(caller, common.BigToHash(stack.Back(0)))
+	case stackLen >= 1 && (op == vm.EXTCODECOPY || op == vm.EXTCODEHASH || op == vm.EXTCODESIZE || op == vm.BALANCE || op == vm.SELFDESTRUCT):
+		addr := common.BigToAddress(stack.Back(0))
+		t.lookupAccount(addr)
+		if op == vm.SELFDESTRUCT {
+			t.deleted[caller] = true
+		}
+	case stackLen >= 5 && (op == vm.DELEGATECALL || op == vm.CALL || op == vm.STATICCALL || op == vm.CALLCODE):
+		t.lookupAccount(common.BigToAddress(stack.Back(1)))
+	case op == vm.CREATE:
+		addr := crypto.CreateAddress(caller, env.StateDB.GetNonce(caller))
+		t.lookupAccount(addr)
+		t.created[addr] = true
+	case stackLen >= 4 && op == vm.CREATE2:
+		offset, size := stack.Back(1).Int64(), stack.Back(2).Int64()
+		init := memory.GetCopy(offset, size)
+		salt := common.BigToHash(stack.Back(3))
+		addr := crypto.CreateAddress2(caller, salt, crypto.Keccak256(init))
+		t.lookupAccount(addr)
+		t.created[addr] = true
+	}
+	return nil
+}
+
+// CaptureEnter is called when the EVM enters a new call frame.
+func (t *prestateTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureExit is called when the EVM exits a call frame.
+func (t *prestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	if t.config.DiffMode {
+		return nil
+	}
+	if t.create {
+		// Keep existing account prior to contract creation at that address
+		if s := t.pre[t.to]; s != nil && !s.exists() {
+			// Exclude newly created contract.
+			delete(t.pre, t.to)
+		}
+	}
+	return nil
+}
+
+// GetResult returns the json-encoded prestate, or the pre and post states in
+// diff mode.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	var res []byte
+	var err error
+	if t.config.DiffMode {
+		res, err = json.Marshal(struct {
+			Post prestate `json:"post"`
+			Pre  prestate `json:"pre"`
+		}{t.post, t.pre})
+	} else {
+		res, err = json.Marshal(t.pre)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupAccount fetches details of an account and adds it to the prestate
+// if it doesn't exist there.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	t.pre[addr] = &prestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(t.env.StateDB.GetBalance(addr))),
+		Nonce:   t.env.StateDB.GetNonce(addr),
+		Code:    common.CopyBytes(t.env.StateDB.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage fetches the requested storage slot and adds
+// it to the prestate of the given contract. It assumes `lookupAccount`
+// has been performed on the contract before.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	if _, ok := t.pre[addr].Storage[key]; ok {
+		return
+	}
+	t.pre[addr].Storage[key] = t.env.StateDB.GetState(addr, key)
+}
@@ -0,0 +1,305 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/rlp"
+	"github.com/tomochain/tomochain/tests"
+)
+
+// loadCallTracerTests reads all the callTracer test cases from disk.
+func loadCallTracerTests(t *testing.T) map[string]*callTracerTest {
+	files, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	cases := make(map[string]*callTracerTest)
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), "call_tracer_") {
+			continue
+		}
+		blob, err := ioutil.ReadFile(filepath.Join("testdata", file.Name()))
+		if err != nil {
+			t.Fatalf("failed to read testcase: %v", err)
+		}
+		test := new(callTracerTest)
+		if err := json.Unmarshal(blob, test); err != nil {
+			t.Fatalf("failed to parse testcase: %v", err)
+		}
+		cases[camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "call_tracer_"), ".json"))] = test
+	}
+	return cases
+}
+
+// runTracer executes the transaction of a test case on a fresh copy of its
+// prestate with the named tracer, and returns the tracing result.
+func runTracer(t *testing.T, test *callTracerTest, name string, config json.RawMessage) json.RawMessage {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
+		t.Fatalf("failed to parse testcase input: %v", err)
+	}
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	origin, _ := signer.Sender(tx)
+
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Origin:      origin,
+		Coinbase:    test.Context.Miner,
+		BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
+		Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
+		Difficulty:  (*big.Int)(test.Context.Difficulty),
+		GasLimit:    uint64(test.Context.GasLimit),
+		GasPrice:    tx.GasPrice(),
+	}
+	statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), test.Genesis.Alloc)
+
+	tracer, err := Create(name, config)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", name, err)
+	}
+	evm := vm.NewEVM(context, statedb, nil, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+
+	msg, err := tx.AsMessage(signer, nil, common.Big0, false, test.Genesis.Config.IsAtlas(context.BlockNumber))
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if _, _, _, err = st.TransitionDb(common.Address{}); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	return res
+}
+
+func TestCreateTracer(t *testing.T) {
+	for name, native := range map[string]bool{
+		"callTracer":       true,
+		"prestateTracer":   true,
+		"4byteTracer":      true,
+		"noopTracer":       true,
+		"callTracerJs":     false,
+		"prestateTracerJs": false,
+		"opcountTracer":    false,
+	} {
+		tracer, err := Create(name, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create tracer: %v", name, err)
+		}
+		if _, js := tracer.(*Tracer); js == native {
+			t.Errorf("%s: tracer type mismatch: have %T, want native %v", name, tracer, native)
+		}
+	}
+	if _, err := Create("callTracer", json.RawMessage(`{"onlyTopCall": 1}`)); err == nil {
+		t.Errorf("invalid tracer config accepted")
+	}
+}
+
+// normalizeCallTrace drops the details of a native call trace which the
+// JavaScript tracer can't report: it skips the gas of calls into accounts
+// without code and the error of frames failing in an inner call. Empty
+// outputs are omitted by the native tracer.
+func normalizeCallTrace(have, want *callTrace) {
+	if len(have.Output) == 0 && len(want.Output) == 0 {
+		have.Output = want.Output
+	}
+	if want.Gas == nil {
+		have.Gas, have.GasUsed = nil, nil
+	}
+	if want.Error == "internal failure" && have.Error != "" {
+		have.Error = want.Error
+	}
+	for i := 0; i < len(have.Calls) && i < len(want.Calls); i++ {
+		normalizeCallTrace(&have.Calls[i], &want.Calls[i])
+	}
+}
+
+// Runs the native callTracer against the JavaScript test harness.
+func TestNativeCallTracer(t *testing.T) {
+	for name, test := range loadCallTracerTests(t) {
+		ret := new(callTrace)
+		if err := json.Unmarshal(runTracer(t, test, "callTracer", nil), ret); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		normalizeCallTrace(ret, test.Result)
+		if !reflect.DeepEqual(ret, test.Result) {
+			have, _ := json.MarshalIndent(ret, "", " ")
+			want, _ := json.MarshalIndent(test.Result, "", " ")
+			t.Errorf("%s: trace mismatch: \nhave %s\nwant %s", name, have, want)
+		}
+	}
+}
+
+func TestNativeCallTracerConfig(t *testing.T) {
+	tests := loadCallTracerTests(t)
+
+	// The top call only must match the outer frame of the full trace
+	for name, test := range tests {
+		full, top := new(callTrace), new(callTrace)
+		if err := json.Unmarshal(runTracer(t, test, "callTracer", nil), full); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if err := json.Unmarshal(runTracer(t, test, "callTracer", json.RawMessage(`{"onlyTopCall": true}`)), top); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if len(top.Calls) != 0 {
+			t.Errorf("%s: subcalls traced with onlyTopCall: %d", name, len(top.Calls))
+		}
+		full.Calls = nil
+		if !reflect.DeepEqual(full, top) {
+			t.Errorf("%s: top call mismatch: have %+v, want %+v", name, top, full)
+		}
+	}
+	// Logs must be reported by the emitting frames of successful calls
+	var logs func(call *callFrame) int
+	logs = func(call *callFrame) int {
+		n := len(call.Logs)
+		for i := range call.Calls {
+			n += logs(&call.Calls[i])
+		}
+		return n
+	}
+	for name, want := range map[string]int{"deepCalls": 3, "simple": 1, "revert": 0} {
+		call := new(callFrame)
+		if err := json.Unmarshal(runTracer(t, tests[name], "callTracer", json.RawMessage(`{"withLog": true}`)), call); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if have := logs(call); have != want {
+			t.Errorf("%s: log count mismatch: have %d, want %d", name, have, want)
+		}
+	}
+}
+
+// Runs the native prestateTracer against the genesis allocations of the test
+// harness, which were assembled by the JavaScript prestateTracer.
+func TestNativePrestateTracer(t *testing.T) {
+	for name, test := range loadCallTracerTests(t) {
+		var native, js prestate
+		if err := json.Unmarshal(runTracer(t, test, "prestateTracer", nil), &native); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if err := json.Unmarshal(runTracer(t, test, "prestateTracerJs", nil), &js); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		// The JavaScript tracer reads the accounts after the gas was bought,
+		// so only the accounts it found are cross-checked.
+		for addr, want := range js {
+			have, ok := native[addr]
+			if !ok {
+				t.Errorf("%s: account %x missing", name, addr)
+				continue
+			}
+			if !bytes.Equal(have.Code, want.Code) || len(have.Storage) != len(want.Storage) {
+				t.Errorf("%s: account %x mismatch: have %+v, want %+v", name, addr, have, want)
+			}
+			for key, val := range want.Storage {
+				if have.Storage[key] != val {
+					t.Errorf("%s: account %x slot %x mismatch: have %x, want %x", name, addr, key, have.Storage[key], val)
+				}
+			}
+		}
+		// The native tracer reports the exact prestate of the allocations
+		for addr, have := range native {
+			want, ok := test.Genesis.Alloc[addr]
+			if !ok {
+				if have.exists() {
+					t.Errorf("%s: account %x not in genesis: %+v", name, addr, have)
+				}
+				continue
+			}
+			if have.Balance.ToInt().Cmp(want.Balance) != 0 || have.Nonce != want.Nonce || !bytes.Equal(have.Code, want.Code) {
+				t.Errorf("%s: account %x mismatch: have %+v, want %+v", name, addr, have, want)
+			}
+			for key, val := range have.Storage {
+				if want.Storage[key] != val {
+					t.Errorf("%s: account %x slot %x mismatch: have %x, want %x", name, addr, key, val, want.Storage[key])
+				}
+			}
+		}
+	}
+}
+
+func TestNativePrestateTracerDiffMode(t *testing.T) {
+	test := loadCallTracerTests(t)["simple"]
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
+		t.Fatalf("failed to parse testcase input: %v", err)
+	}
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	from, _ := signer.Sender(tx)
+
+	var res struct {
+		Pre  prestate `json:"pre"`
+		Post prestate `json:"post"`
+	}
+	if err := json.Unmarshal(runTracer(t, test, "prestateTracer", json.RawMessage(`{"diffMode": true}`)), &res); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	pre, post := res.Pre[from], res.Post[from]
+	if pre == nil || post == nil {
+		t.Fatalf("sender missing from diff: pre %v, post %v", pre, post)
+	}
+	if post.Nonce != pre.Nonce+1 {
+		t.Errorf("sender nonce mismatch: have %d, want %d", post.Nonce, pre.Nonce+1)
+	}
+	if post.Balance.ToInt().Cmp(pre.Balance.ToInt()) >= 0 {
+		t.Errorf("sender balance not charged: pre %v, post %v", pre.Balance, post.Balance)
+	}
+	if post.Code != nil {
+		t.Errorf("unmodified code reported: %x", post.Code)
+	}
+	// Every reported account must have been modified
+	for addr := range res.Pre {
+		if _, ok := res.Post[addr]; !ok {
+			t.Errorf("unmodified account %x reported", addr)
+		}
+	}
+}
+
+// Runs the native 4byteTracer against the JavaScript one.
+func TestNativeFourByteTracer(t *testing.T) {
+	for name, test := range loadCallTracerTests(t) {
+		var native, js map[string]int
+		if err := json.Unmarshal(runTracer(t, test, "4byteTracer", nil), &native); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if err := json.Unmarshal(runTracer(t, test, "4byteTracerJs", nil), &js); err != nil {
+			t.Fatalf("%s: failed to unmarshal trace result: %v", name, err)
+		}
+		if !reflect.DeepEqual(native, js) {
+			t.Errorf("%s: 4byte mismatch: have %v, want %v", name, native, js)
+		}
+	}
+}
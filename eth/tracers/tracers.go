@@ -14,16 +14,63 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package tracers is a collection of JavaScript transaction tracers.
+// Package tracers is a collection of JavaScript and native Go transaction
+// tracers.
 package tracers
 
 import (
+	"encoding/json"
 	"strings"
 	"unicode"
 
+	"github.com/tomochain/tomochain/core/vm"
 	"github.com/tomochain/tomochain/eth/tracers/internal/tracers"
 )
 
+// ResultTracer is a vm.Tracer assembling a JSON result from the traced
+// execution, which can be interrupted while running. Both the JavaScript and
+// the native tracers implement it.
+type ResultTracer interface {
+	vm.Tracer
+	GetResult() (json.RawMessage, error)
+	Stop(err error)
+}
+
+// nativeCtor creates a native tracer from its optional JSON configuration.
+type nativeCtor func(config json.RawMessage) (ResultTracer, error)
+
+// natives contains all the built in native Go tracers by name.
+var natives = make(map[string]nativeCtor)
+
+// registerNative makes a native tracer available under the given name.
+func registerNative(name string, ctor nativeCtor) {
+	natives[name] = ctor
+}
+
+// jsSuffix selects the JavaScript implementation of a tracer that also has a
+// native one, e.g. callTracerJs.
+const jsSuffix = "Js"
+
+// Create resolves a tracer by name or JavaScript code. Native tracers take
+// precedence over the JavaScript ones of the same name, which remain
+// available with a "Js" suffix. Anything else is evaluated as JavaScript.
+// The configuration is only used by native tracers.
+func Create(code string, config json.RawMessage) (ResultTracer, error) {
+	if ctor, ok := natives[code]; ok {
+		return ctor(config)
+	}
+	if name := strings.TrimSuffix(code, jsSuffix); name != code {
+		if _, ok := all[name]; ok {
+			code = name
+		}
+	}
+	tracer, err := New(code)
+	if err != nil {
+		return nil, err
+	}
+	return tracer, nil
+}
+
 // all contains all the built in JavaScript tracers by name.
 var all = make(map[string]string)
 
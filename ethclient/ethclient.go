@@ -319,6 +319,12 @@ func (ec *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header)
 	return ec.c.EthSubscribe(ctx, ch, "newHeads", map[string]struct{}{})
 }
 
+// SubscribeNewFinalizedHead subscribes to notifications about blocks that have
+// been signed by enough masternodes to be final on the given channel.
+func (ec *Client) SubscribeNewFinalizedHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return ec.c.EthSubscribe(ctx, ch, "newFinalizedHeads", map[string]struct{}{})
+}
+
 // State Access
 
 // NetworkID returns the network ID (also known as the chain ID) for this chain.
@@ -350,6 +356,70 @@ func (ec *Client) StorageAt(ctx context.Context, account common.Address, key com
 	return result, err
 }
 
+// AccountResult is the result of a GetProof operation.
+type AccountResult struct {
+	Address      common.Address
+	AccountProof []string
+	Balance      *big.Int
+	CodeHash     common.Hash
+	Nonce        uint64
+	StorageHash  common.Hash
+	StorageProof []StorageResult
+}
+
+// StorageResult provides a proof for a key-value pair.
+type StorageResult struct {
+	Key   string
+	Value *big.Int
+	Proof []string
+}
+
+// GetProof returns the account and storage values of the specified account
+// including the Merkle-proof. The block number can be nil, in which case the
+// proof is taken from the latest known block.
+func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []string, blockNumber *big.Int) (*AccountResult, error) {
+	type storageResult struct {
+		Key   string       `json:"key"`
+		Value *hexutil.Big `json:"value"`
+		Proof []string     `json:"proof"`
+	}
+	type accountResult struct {
+		Address      common.Address  `json:"address"`
+		AccountProof []string        `json:"accountProof"`
+		Balance      *hexutil.Big    `json:"balance"`
+		CodeHash     common.Hash     `json:"codeHash"`
+		Nonce        hexutil.Uint64  `json:"nonce"`
+		StorageHash  common.Hash     `json:"storageHash"`
+		StorageProof []storageResult `json:"storageProof"`
+	}
+	// Avoid keys being 'null'.
+	if keys == nil {
+		keys = []string{}
+	}
+	var res accountResult
+	if err := ec.c.CallContext(ctx, &res, "eth_getProof", account, keys, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	// Turn hexutils back to normal datatypes
+	storageResults := make([]StorageResult, 0, len(res.StorageProof))
+	for _, st := range res.StorageProof {
+		storageResults = append(storageResults, StorageResult{
+			Key:   st.Key,
+			Value: st.Value.ToInt(),
+			Proof: st.Proof,
+		})
+	}
+	return &AccountResult{
+		Address:      res.Address,
+		AccountProof: res.AccountProof,
+		Balance:      res.Balance.ToInt(),
+		Nonce:        uint64(res.Nonce),
+		CodeHash:     res.CodeHash,
+		StorageHash:  res.StorageHash,
+		StorageProof: storageResults,
+	}, nil
+}
+
 // CodeAt returns the contract code of the given account.
 // The block number can be nil, in which case the code is taken from the latest known block.
 func (ec *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
@@ -489,7 +559,7 @@ func (ec *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64
 // If the transaction was a contract creation use the TransactionReceipt method to get the
 // contract address after the transaction has been mined.
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
@@ -534,5 +604,8 @@ func toCallArg(msg ethereum.CallMsg) interface{} {
 	if msg.GasPrice != nil {
 		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
 	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
 	return arg
 }
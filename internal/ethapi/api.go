@@ -31,6 +31,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/tomochain/tomochain/accounts"
+	"github.com/tomochain/tomochain/accounts/abi"
 	"github.com/tomochain/tomochain/accounts/abi/bind"
 	"github.com/tomochain/tomochain/accounts/keystore"
 	"github.com/tomochain/tomochain/common"
@@ -82,6 +83,47 @@ func (s *PublicEthereumAPI) GasPrice(ctx context.Context) (*big.Int, error) {
 	return s.b.SuggestPrice(ctx)
 }
 
+type feeHistoryResult struct {
+	OldestBlock           *hexutil.Big     `json:"oldestBlock"`
+	Reward                [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee               []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio          []float64        `json:"gasUsedRatio"`
+	SponsoredGasUsedRatio []float64        `json:"sponsoredGasUsedRatio"`
+}
+
+// FeeHistory returns the fee market history. The blocks carry no base fee, so
+// it is reported as zero and the rewards are the gas prices paid at the given
+// percentiles. The gas used by transactions whose fee was paid by a TRC21
+// token issuer is reported separately and excluded from the rewards.
+func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, gasUsed, sponsoredGasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:           (*hexutil.Big)(oldest),
+		GasUsedRatio:          gasUsed,
+		SponsoredGasUsedRatio: sponsoredGasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	// The base fee is also reported for the block following the newest one
+	if len(gasUsed) > 0 {
+		results.BaseFee = make([]*hexutil.Big, len(gasUsed)+1)
+		for i := range results.BaseFee {
+			results.BaseFee[i] = (*hexutil.Big)(new(big.Int))
+		}
+	}
+	return results, nil
+}
+
 // ProtocolVersion returns the current Ethereum protocol version this node supports
 func (s *PublicEthereumAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
@@ -412,7 +454,7 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(signed)
+	data, err := signed.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -616,6 +658,19 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 	return code, state.Error()
 }
 
+// IsBlacklisted returns whether the transactions from or to the given address
+// are rejected in the block following the given one, by either the hard-coded
+// black-list or, past the black-list fork, the black-list contract.
+func (s *PublicBlockChainAPI) IsBlacklisted(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (bool, error) {
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return false, err
+	}
+	next := new(big.Int).Add(header.Number, common.Big1)
+	blacklisted := core.BlacklistRule(s.b.ChainConfig(), state, next, address) != ""
+	return blacklisted, state.Error()
+}
+
 // GetStorageAt returns the storage from the state at the given address, key and
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
@@ -628,6 +683,80 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// AccountResult is the result of eth_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the Merkle proof of a single storage slot in eth_getProof.
+type StorageResult struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// GetProof returns the Merkle-proof for a given account and optionally some
+// storage keys, as defined by EIP-1186.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	storageTrie := state.StorageTrie(address)
+	storageHash := types.EmptyRootHash
+	codeHash := state.GetCodeHash(address)
+	storageProof := make([]StorageResult, len(storageKeys))
+
+	// If we have a storageTrie, (which means the account exists), we can update the storagehash
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	} else {
+		// no storageTrie means the account does not exist, so the codeHash is the hash of an empty bytearray.
+		codeHash = crypto.Keccak256Hash(nil)
+	}
+	// Create the proofs for the storageKeys
+	for i, key := range storageKeys {
+		if storageTrie != nil {
+			proof, storageError := state.GetStorageProof(address, common.HexToHash(key))
+			if storageError != nil {
+				return nil, storageError
+			}
+			storageProof[i] = StorageResult{key, (*hexutil.Big)(state.GetState(address, common.HexToHash(key)).Big()), toHexSlice(proof)}
+		} else {
+			storageProof[i] = StorageResult{key, &hexutil.Big{}, []string{}}
+		}
+	}
+	// Create the accountProof
+	accountProof, proofErr := state.GetProof(address)
+	if proofErr != nil {
+		return nil, proofErr
+	}
+	return &AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     codeHash,
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, state.Error()
+}
+
+// toHexSlice creates a slice of hex-strings based on []byte.
+func toHexSlice(b [][]byte) []string {
+	r := make([]string, len(b))
+	for i := range b {
+		r[i] = hexutil.Encode(b[i])
+	}
+	return r
+}
+
 // GetBlockReceipts returns the block receipts for the given block hash or number or tag.
 func (api *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
 	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
@@ -1045,43 +1174,185 @@ func (s *PublicBlockChainAPI) getCandidatesFromSmartContract() ([]posv.Masternod
 
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      hexutil.Uint64  `json:"gas"`
-	GasPrice hexutil.Big     `json:"gasPrice"`
-	Value    hexutil.Big     `json:"value"`
-	Data     hexutil.Bytes   `json:"data"`
+	From       common.Address    `json:"from"`
+	To         *common.Address   `json:"to"`
+	Gas        hexutil.Uint64    `json:"gas"`
+	GasPrice   hexutil.Big       `json:"gasPrice"`
+	Value      hexutil.Big       `json:"value"`
+	Data       hexutil.Bytes     `json:"data"`
+	AccessList *types.AccessList `json:"accessList"`
+}
+
+// ToMessage converts the call arguments into a message. The gas defaults to
+// gasCap and the gas price to the default one. The gas is paid from the given
+// TRC21 fee capacity if any, otherwise the sender isn't charged for it.
+func (args *CallArgs) ToMessage(gasCap uint64, feeCapacity *big.Int) types.Message {
+	// Set default gas & gas price if none were set
+	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
+	if gas == 0 {
+		gas = gasCap
+	}
+	if gasPrice.Sign() == 0 {
+		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
+	}
+	balanceTokenFee := feeCapacity
+	if balanceTokenFee == nil {
+		balanceTokenFee = big.NewInt(0).SetUint64(gas)
+		balanceTokenFee = balanceTokenFee.Mul(balanceTokenFee, gasPrice)
+	}
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+	return types.NewMessage(args.From, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, accessList, false, balanceTokenFee)
+}
+
+// OverrideAccount indicates the overriding fields of account during the execution
+// of a message call.
+// Note, state and stateDiff can't be specified at the same time. If state is
+// set, message execution will only use the data in the given state. Otherwise
+// if statDiff is set, all diff will be applied first and then execute the call
+// message.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   **hexutil.Big                `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// FeeCapacity returns the TRC21 fee capacity sponsoring a call to the given
+// address in the overridden state. It is only looked up when the overrides touch
+// the TRC21 issuer contract, nil is returned otherwise.
+func (diff *StateOverride) FeeCapacity(statedb *state.StateDB, to *common.Address) *big.Int {
+	if diff == nil {
+		return nil
+	}
+	if _, ok := (*diff)[common.TRC21IssuerSMC]; !ok {
+		return nil
+	}
+	return state.GetTRC21FeeCapacityFromStateWithToken(statedb, to)
+}
+
+// Apply overrides the fields of specified accounts into the given state.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		// Override account nonce.
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		// Override account(contract) code.
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		// Override account balance.
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(*account.Balance))
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		// Replace entire state if caller requires.
+		if account.State != nil {
+			state.SetStorage(addr, *account.State)
+		}
+		// Apply state diff into specified accounts.
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
+// BlockOverrides is a set of header fields to override in the block context
+// of a message call.
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number"`
+	Time     *hexutil.Uint64 `json:"time"`
+	Coinbase *common.Address `json:"coinbase"`
+}
+
+// ApplyHeader returns a copy of the header with the number and time overrides
+// applied, so that the chain rules of the call follow the overridden number.
+func (o *BlockOverrides) ApplyHeader(header *types.Header) *types.Header {
+	if o == nil {
+		return header
+	}
+	header = types.CopyHeader(header)
+	if o.Number != nil {
+		header.Number = new(big.Int).Set(o.Number.ToInt())
+	}
+	if o.Time != nil {
+		header.Time = new(big.Int).SetUint64(uint64(*o.Time))
+	}
+	return header
+}
+
+// ApplyContext applies the coinbase override to the block context of the EVM.
+// The beneficiary of a PoSV block is recovered from its seal instead of being
+// read from the header, so it can't be overridden through ApplyHeader.
+func (o *BlockOverrides) ApplyContext(context *vm.Context) {
+	if o == nil {
+		return
+	}
+	if o.Coinbase != nil {
+		context.Coinbase = *o.Coinbase
+	}
+}
+
+// tradingStateAt returns the TomoX trading state of a block, or nil if TomoX
+// isn't enabled at the block.
+func (s *PublicBlockChainAPI) tradingStateAt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*tradingstate.TradingStateDB, error) {
+	block, err := s.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if !s.b.ChainConfig().IsTomoXEnabled(block.Number()) {
+		return nil, nil
+	}
+	author, err := s.b.GetEngine().Author(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	tomoXService := s.b.TomoxService()
+	if tomoXService == nil {
+		return nil, nil
+	}
+	return tomoXService.GetTradingState(block, author)
+}
+
+// doCall executes a call on the state of a block, returning the data returned
+// and the gas used. If the EVM execution failed, the error is a *callError,
+// decoding the revert reason with the custom errors of the contract ABI if given.
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, errorsABI *abi.ABI, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if statedb == nil || err != nil {
-		return nil, 0, false, err
+		return nil, 0, err
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		return nil, 0, err
 	}
+	header = blockOverrides.ApplyHeader(header)
 	// Set sender address or use a default if none specified
-	addr := args.From
-	if addr == (common.Address{}) {
+	if args.From == (common.Address{}) {
 		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
 			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
-				addr = accounts[0].Address
+				args.From = accounts[0].Address
 			}
 		}
 	}
-	// Set default gas & gas price if none were set
-	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
-	if gas == 0 {
-		gas = math.MaxUint64 / 2
-	}
-	if gasPrice.Sign() == 0 {
-		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
-	}
-	balanceTokenFee := big.NewInt(0).SetUint64(gas)
-	balanceTokenFee = balanceTokenFee.Mul(balanceTokenFee, gasPrice)
-
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false, balanceTokenFee)
+	msg := args.ToMessage(math.MaxUint64/2, overrides.FeeCapacity(statedb, args.To))
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -1095,32 +1366,17 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// this makes sure resources are cleaned up.
 	defer cancel()
 
-	block, err := s.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	tomoxState, err := s.tradingStateAt(ctx, blockNrOrHash)
 	if err != nil {
-		return nil, 0, false, err
-	}
-
-	var tomoxState *tradingstate.TradingStateDB
-	blockNumber := block.Number()
-	if s.b.ChainConfig().IsTomoXEnabled(blockNumber) {
-		author, err := s.b.GetEngine().Author(block.Header())
-		if err != nil {
-			return nil, 0, false, err
-		}
-		tomoXService := s.b.TomoxService()
-		if tomoXService != nil {
-			tomoxState, err = tomoXService.GetTradingState(block, author)
-			if err != nil {
-				return nil, 0, false, err
-			}
-		}
+		return nil, 0, err
 	}
 
 	// Get a new instance of the EVM.
 	evm, vmError, err := s.b.GetEVM(ctx, msg, statedb, tomoxState, header, vmCfg)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, 0, err
 	}
+	blockOverrides.ApplyContext(&evm.Context)
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -1132,23 +1388,210 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// and apply the message.
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	owner := common.Address{}
-	res, gas, failed, err := core.ApplyMessage(evm, msg, gp, owner)
+	st := core.NewStateTransition(evm, msg, gp)
+	res, gas, _, err := st.TransitionDb(owner)
 	if err := vmError(); err != nil {
-		return nil, 0, false, err
+		return nil, 0, err
 	}
-	return res, gas, failed, err
+	if err != nil {
+		return nil, 0, err
+	}
+	if st.VMError() != nil {
+		return res, gas, newCallError(st.VMError(), res, errorsABI)
+	}
+	return res, gas, nil
 }
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNrOrHash, vm.Config{}, 5*time.Second)
-	return (hexutil.Bytes)(result), err
+//
+// Additionally, the caller can specify a batch of contract for fields overriding
+// and a set of block context fields to override.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, errorsABI *abi.ABI) (hexutil.Bytes, error) {
+	result, _, err := s.doCall(ctx, args, blockNrOrHash, overrides, blockOverrides, errorsABI, vm.Config{}, s.b.RPCEVMTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return (hexutil.Bytes)(result), nil
+}
+
+// callError is the error of a call whose EVM execution failed. A reverted call
+// carries its revert data and the reason decoded from it, if any.
+type callError struct {
+	error
+	reason string // reason of the revert, empty if it couldn't be decoded
+	data   string // revert data, hex encoded, empty if the call didn't revert
+}
+
+// ErrorCode returns the JSON-RPC error code of the failed calls, the one of
+// reverted calls if it reverted.
+func (e *callError) ErrorCode() int {
+	if e.data != "" {
+		return 3
+	}
+	return -32000
+}
+
+// ErrorData returns the hex encoded revert data of the call, if it reverted.
+func (e *callError) ErrorData() interface{} {
+	if e.data == "" {
+		return nil
+	}
+	return e.data
+}
+
+// newCallError returns the error of a call whose EVM execution failed. The
+// reason of a reverted call is decoded from its revert data, using the custom
+// errors of the contract ABI if given.
+func newCallError(vmerr error, data []byte, errorsABI *abi.ABI) *callError {
+	if vmerr != vm.ErrExecutionReverted || len(data) == 0 {
+		return &callError{error: vmerr}
+	}
+	var (
+		reason string
+		err    error
+	)
+	if errorsABI != nil {
+		reason, err = errorsABI.UnpackError(data)
+	} else {
+		reason, err = abi.UnpackRevert(data)
+	}
+	msg := vmerr.Error()
+	if err == nil {
+		msg += ": " + reason
+	} else {
+		reason = ""
+	}
+	return &callError{error: errors.New(msg), reason: reason, data: hexutil.Encode(data)}
+}
+
+// CallManyOptions are the options of a batch of calls.
+type CallManyOptions struct {
+	// Sequential runs every call on the state left by the previous ones
+	// instead of the state of the block.
+	Sequential bool `json:"sequential"`
+}
+
+// CallResult is the outcome of a call of a batch.
+type CallResult struct {
+	ReturnData   hexutil.Bytes  `json:"returnData"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Logs         []*types.Log   `json:"logs"`
+	Status       hexutil.Uint64 `json:"status"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+}
+
+// CallMany executes a batch of calls on a single copy of the state for the
+// given block number, with the same state and block overrides as eth_call.
+// The calls share the gas cap and the timeout configured for the node. A call
+// failing doesn't abort the batch, its error is returned in its result.
+func (s *PublicBlockChainAPI) CallMany(ctx context.Context, calls []CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, options *CallManyOptions) ([]*CallResult, error) {
+	defer func(start time.Time) {
+		log.Debug("Executing EVM calls finished", "calls", len(calls), "runtime", time.Since(start))
+	}(time.Now())
+
+	if options == nil {
+		options = new(CallManyOptions)
+	}
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		return nil, err
+	}
+	header = blockOverrides.ApplyHeader(header)
+
+	tomoxState, err := s.tradingStateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	// All the calls must complete before the deadline
+	var cancel context.CancelFunc
+	timeout := s.b.RPCEVMTimeout()
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var (
+		gasCap  = s.b.RPCGasCap()
+		results = make([]*CallResult, len(calls))
+		base    = statedb
+	)
+	if gasCap == 0 {
+		gasCap = math.MaxUint64 / 2
+	}
+	for i, args := range calls {
+		if gasCap == 0 {
+			return nil, fmt.Errorf("gas cap exhausted at call %d", i)
+		}
+		if args.Gas == 0 || uint64(args.Gas) > gasCap {
+			args.Gas = hexutil.Uint64(gasCap)
+		}
+		if !options.Sequential {
+			statedb = base.Copy()
+		}
+		// Calls are given a fake hash to collect their logs
+		statedb.Prepare(common.BigToHash(big.NewInt(int64(i+1))), header.Hash(), i)
+
+		msg := args.ToMessage(gasCap, overrides.FeeCapacity(statedb, args.To))
+		evm, vmError, err := s.b.GetEVM(ctx, msg, statedb, tomoxState, header, vm.Config{})
+		if err != nil {
+			return nil, err
+		}
+		blockOverrides.ApplyContext(&evm.Context)
+
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+		st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+		res, gas, _, err := st.TransitionDb(common.Address{})
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		result := &CallResult{
+			ReturnData: res,
+			GasUsed:    hexutil.Uint64(gas),
+			Logs:       statedb.GetLogs(common.BigToHash(big.NewInt(int64(i + 1)))),
+			Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if result.Logs == nil {
+			result.Logs = []*types.Log{}
+		}
+		switch {
+		case err != nil:
+			result.Status, result.Error = hexutil.Uint64(types.ReceiptStatusFailed), err.Error()
+		case st.VMError() != nil:
+			result.Status, result.Error = hexutil.Uint64(types.ReceiptStatusFailed), st.VMError().Error()
+			result.RevertReason = newCallError(st.VMError(), res, nil).reason
+		}
+		if options.Sequential {
+			statedb.Finalise(true)
+		}
+		gasCap -= gas
+		results[i] = result
+	}
+	return results, nil
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block. The same state and block
+// overrides as for eth_call may be given, as well as the contract ABI decoding
+// the revert reason of an always failing transaction.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, errorsABI *abi.ABI) (hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
@@ -1172,19 +1615,18 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, bl
 	cap = hi
 
 	// Create a helper to check if a gas allowance results in an executable transaction
-	executable := func(gas uint64) bool {
+	executable := func(gas uint64) (bool, error) {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, bNrOrHash, vm.Config{}, 0)
-		if err != nil || failed {
-			return false
+		if _, _, err := s.doCall(ctx, args, bNrOrHash, overrides, blockOverrides, errorsABI, vm.Config{}, 0); err != nil {
+			return false, err
 		}
-		return true
+		return true, nil
 	}
 	// Execute the binary search and hone in on an executable gas limit
 	for lo+1 < hi {
 		mid := (hi + lo) / 2
-		if !executable(mid) {
+		if ok, _ := executable(mid); !ok {
 			lo = mid
 		} else {
 			hi = mid
@@ -1192,13 +1634,83 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, bl
 	}
 	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
-		if !executable(hi) {
+		if ok, err := executable(hi); !ok {
+			if callErr, ok := err.(*callError); ok && callErr.data != "" {
+				return 0, callErr
+			}
 			return 0, fmt.Errorf("gas required exceeds allowance or always failing transaction")
 		}
 	}
 	return hexutil.Uint64(hi), nil
 }
 
+// accessListResult is the result of eth_createAccessList. It contains an error
+// if the transaction itself failed.
+type accessListResult struct {
+	Accesslist *types.AccessList `json:"accessList"`
+	Error      string            `json:"error,omitempty"`
+	GasUsed    hexutil.Uint64    `json:"gasUsed"`
+}
+
+// CreateAccessList creates an EIP-2930 access list for the given transaction.
+// BlockNrOrHash can be specified to create the access list on top of
+// a certain state, the pending block by default.
+func (s *PublicBlockChainAPI) CreateAccessList(ctx context.Context, args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*accessListResult, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	// Set sender address or use a default if none specified
+	if args.From == (common.Address{}) {
+		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
+			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
+				args.From = accounts[0].Address
+			}
+		}
+	}
+	// Retrieve the precompiles since they don't need to be added to the access list
+	precompiles := vm.ActivePrecompiles(s.b.ChainConfig().Rules(header.Number))
+
+	// The recipient of a contract creation is the address it deploys to
+	var to common.Address
+	if args.To != nil {
+		to = *args.To
+	} else {
+		to = crypto.CreateAddress(args.From, statedb.GetNonce(args.From))
+	}
+	// Create an initial tracer
+	prevTracer := vm.NewAccessListTracer(nil, args.From, to, precompiles)
+	if args.AccessList != nil {
+		prevTracer = vm.NewAccessListTracer(*args.AccessList, args.From, to, precompiles)
+	}
+	for {
+		// Retrieve the current access list to expand
+		accessList := prevTracer.AccessList()
+		log.Trace("Creating access list", "input", accessList)
+
+		// Apply the transaction with the access list tracer
+		args.AccessList = &accessList
+		tracer := vm.NewAccessListTracer(accessList, args.From, to, precompiles)
+		_, gas, err := s.doCall(ctx, args, bNrOrHash, nil, nil, nil, vm.Config{Debug: true, Tracer: tracer}, 0)
+		callErr, failed := err.(*callError)
+		if err != nil && !failed {
+			return nil, fmt.Errorf("failed to apply transaction: %v", err)
+		}
+		if tracer.Equal(prevTracer) {
+			result := &accessListResult{Accesslist: &accessList, GasUsed: hexutil.Uint64(gas)}
+			if failed {
+				result.Error = callErr.Error()
+			}
+			return result, nil
+		}
+		prevTracer = tracer
+	}
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
@@ -1288,6 +1800,9 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 		"validator":        hexutil.Bytes(head.Validator),
 		"penalties":        hexutil.Bytes(head.Penalties),
 	}
+	if finalized, err := s.b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber); err == nil && finalized != nil {
+		fields["finalized"] = head.Number.Cmp(finalized.Number) <= 0 && s.isCanonical(ctx, head)
+	}
 
 	if inclTx {
 		formatTx := func(tx *types.Transaction) (interface{}, error) {
@@ -1320,6 +1835,12 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 	return fields, nil
 }
 
+// isCanonical reports whether the given header is part of the canonical chain.
+func (s *PublicBlockChainAPI) isCanonical(ctx context.Context, head *types.Header) bool {
+	canonical, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(head.Number.Int64()))
+	return err == nil && canonical != nil && canonical.Hash() == head.Hash()
+}
+
 // findNearestSignedBlock finds the nearest checkpoint from input block
 func (s *PublicBlockChainAPI) findNearestSignedBlock(ctx context.Context, b *types.Block) *types.Block {
 	if b.Number().Int64() <= 0 {
@@ -1470,20 +1991,23 @@ func (s *PublicBlockChainAPI) rpcOutputBlockSigners(b *types.Block, ctx context.
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
+	BlockHash        common.Hash       `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex hexutil.Uint      `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	Type             hexutil.Uint64    `json:"type"`
+	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
+	V                *hexutil.Big      `json:"v"`
+	R                *hexutil.Big      `json:"r"`
+	S                *hexutil.Big      `json:"s"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1491,7 +2015,7 @@ type RPCTransaction struct {
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.NewEIP2930Signer(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
@@ -1505,10 +2029,16 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		Nonce:    hexutil.Uint64(tx.Nonce()),
 		To:       tx.To(),
 		Value:    (*hexutil.Big)(tx.Value()),
+		Type:     hexutil.Uint64(tx.Type()),
 		V:        (*hexutil.Big)(v),
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	if tx.Type() != types.LegacyTxType {
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1537,7 +2067,7 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.By
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	blob, _ := rlp.EncodeToBytes(txs[index])
+	blob, _ := txs[index].MarshalBinary()
 	return blob
 }
 
@@ -1656,8 +2186,8 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 			return nil, nil
 		}
 	}
-	// Serialize to RLP and return
-	return rlp.EncodeToBytes(tx)
+	// Serialize to the canonical encoding and return
+	return tx.MarshalBinary()
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
@@ -1677,9 +2207,20 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.NewEIP2930Signer(tx.ChainId())
+	}
+	fields := marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index))
+
+	// Nodes recording revert reasons serve them for the failed transactions
+	if receipt.Status == types.ReceiptStatusFailed {
+		if reasons := core.GetBlockRevertReasons(s.b.ChainDb(), blockHash, blockNumber); int(index) < len(reasons) && len(reasons[index]) > 0 {
+			fields["revertReason"] = hexutil.Bytes(reasons[index])
+			if reason, err := abi.UnpackRevert(reasons[index]); err == nil {
+				fields["revertMessage"] = reason
+			}
+		}
 	}
-	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index)), nil
+	return fields, nil
 }
 
 // marshalReceipt marshals a transaction receipt into a JSON object.
@@ -1693,6 +2234,7 @@ func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber u
 		"transactionIndex":  hexutil.Uint64(txIndex),
 		"from":              from,
 		"to":                tx.To(),
+		"type":              hexutil.Uint(tx.Type()),
 		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
 		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
 		"contractAddress":   nil,
@@ -1745,6 +2287,10 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// Introduced by the access list transactions (EIP-2930), enabled by the EIP2929 fork.
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1785,6 +2331,17 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 			return errors.New(`contract creation without any data provided`)
 		}
 	}
+	if args.AccessList != nil {
+		config := b.ChainConfig()
+		if !config.IsEIP2929(b.CurrentBlock().Number()) {
+			return errors.New("access list transactions are not enabled before the EIP2929 fork")
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(config.ChainId)
+		} else if have := (*big.Int)(args.ChainID); have.Cmp(config.ChainId) != 0 {
+			return fmt.Errorf("chainId does not match node's (have=%v, want=%v)", have, config.ChainId)
+		}
+	}
 	return nil
 }
 
@@ -1795,6 +2352,9 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	if args.AccessList != nil {
+		return types.NewAccessListTransaction((*big.Int)(args.ChainID), uint64(*args.Nonce), args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, *args.AccessList)
+	}
 	if args.To == nil {
 		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
 	}
@@ -1864,7 +2424,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 // The sender is responsible for signing the transaction and using the correct nonce.
 func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
 	tx := new(types.Transaction)
-	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
 		return common.Hash{}, err
 	}
 	return submitTransaction(ctx, s.b, tx)
@@ -1921,7 +2481,7 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -1940,7 +2500,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if tx.Protected() {
-			signer = types.NewEIP155Signer(tx.ChainId())
+			signer = types.NewEIP2930Signer(tx.ChainId())
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, err := s.b.AccountManager().Find(accounts.Account{Address: from}); err == nil {
@@ -1968,7 +2528,7 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	for _, p := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if p.Protected() {
-			signer = types.NewEIP155Signer(p.ChainId())
+			signer = types.NewEIP2930Signer(p.ChainId())
 		}
 		wantSigHash := signer.Hash(matchTx)
 
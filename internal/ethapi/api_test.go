@@ -1,38 +1,51 @@
 package ethapi
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tomochain/tomochain/accounts"
+	"github.com/tomochain/tomochain/accounts/abi"
+	"github.com/tomochain/tomochain/accounts/keystore"
 	"github.com/tomochain/tomochain/common"
 	"github.com/tomochain/tomochain/common/hexutil"
 	"github.com/tomochain/tomochain/consensus"
 	"github.com/tomochain/tomochain/consensus/ethash"
+	trc21contract "github.com/tomochain/tomochain/contracts/trc21issuer/contract"
 	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/rawdb"
 	"github.com/tomochain/tomochain/core/state"
 	"github.com/tomochain/tomochain/core/types"
 	"github.com/tomochain/tomochain/core/vm"
+	"github.com/tomochain/tomochain/core/vm/privacy"
+	"github.com/tomochain/tomochain/core/vm/runtime"
 	"github.com/tomochain/tomochain/crypto"
 	"github.com/tomochain/tomochain/eth/downloader"
 	"github.com/tomochain/tomochain/ethclient"
 	"github.com/tomochain/tomochain/ethdb"
+	"github.com/tomochain/tomochain/ethdb/memorydb"
 	"github.com/tomochain/tomochain/event"
 	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rlp"
 	"github.com/tomochain/tomochain/rpc"
 	"github.com/tomochain/tomochain/tomox"
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 	"github.com/tomochain/tomochain/tomoxlending"
+	"github.com/tomochain/tomochain/trie"
 )
 
 type testBackend struct {
@@ -57,6 +70,19 @@ func (t testBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	panic("implement me")
 }
 
+func (t testBackend) FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []float64, []float64, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (t testBackend) RPCGasCap() uint64 {
+	return 50000000
+}
+
+func (t testBackend) RPCEVMTimeout() time.Duration {
+	return 5 * time.Second
+}
+
 func (t testBackend) ChainDb() ethdb.Database {
 	//TODO implement me
 	panic("implement me")
@@ -324,7 +350,7 @@ func newTestBackend(t *testing.T, n int, gspec *core.Genesis, generator func(i i
 	)
 	// Generate blocks for testing
 	db, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, n, generator)
-	chain, err := core.NewBlockChain(db, cacheConfig, params.TestChainConfig, engine, vm.Config{})
+	chain, err := core.NewBlockChain(db, cacheConfig, gspec.Config, engine, vm.Config{})
 	if err != nil {
 		t.Fatalf("failed to create tester chain: %v", err)
 	}
@@ -407,7 +433,7 @@ func TestEstimateGas(t *testing.T) {
 		},
 	}
 	for i, tc := range testSuite {
-		result, err := api.EstimateGas(context.Background(), tc.call, &rpc.BlockNumberOrHash{BlockNumber: &tc.blockNumber})
+		result, err := api.EstimateGas(context.Background(), tc.call, &rpc.BlockNumberOrHash{BlockNumber: &tc.blockNumber}, nil, nil, nil)
 		if tc.expectErr != nil {
 			if err == nil {
 				t.Errorf("test %d: want error %v, have nothing", i, tc.expectErr)
@@ -428,6 +454,467 @@ func TestEstimateGas(t *testing.T) {
 	}
 }
 
+func TestCallWithOverrides(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0x00000000000000000000000000000000000c0de0")
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				// Returns storage slot 0
+				contract: {
+					Balance: new(big.Int),
+					Code:    common.FromHex("0x60005460005260206000f3"),
+					Storage: map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(1))},
+				},
+			},
+		}
+		// Return the given value of the block context or the own balance
+		returnOp = func(op string) hexutil.Bytes {
+			return hexutil.Bytes(common.FromHex("0x" + op + "60005260206000f3"))
+		}
+		word = func(n int64) hexutil.Bytes {
+			return hexutil.Bytes(common.BigToHash(big.NewInt(n)).Bytes())
+		}
+		balance   = (*hexutil.Big)(big.NewInt(1000))
+		number    = (*hexutil.Big)(big.NewInt(0x1234))
+		timestamp = hexutil.Uint64(0x5678)
+		coinbase  = common.HexToAddress("0xc0ffee")
+		// Sets the TRC21 fee capacity of the contract in the issuer contract
+		capacity = func(n int64) StateOverride {
+			slot := common.BigToHash(state.GetLocMappingAtKey(contract.Hash(), state.SlotTRC21Issuer["tokensState"]))
+			return StateOverride{common.TRC21IssuerSMC: OverrideAccount{StateDiff: &map[common.Hash]common.Hash{slot: common.BigToHash(big.NewInt(n))}}}
+		}
+	)
+	api := NewPublicBlockChainAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	var testSuite = []struct {
+		overrides      StateOverride
+		blockOverrides *BlockOverrides
+		expectErr      bool
+		want           hexutil.Bytes
+	}{
+		// no overrides
+		{want: word(1)},
+		// storage diff is applied on top of the existing storage
+		{
+			overrides: StateOverride{contract: OverrideAccount{StateDiff: &map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(42))}}},
+			want:      word(42),
+		},
+		// full storage replacement drops the existing slots
+		{
+			overrides: StateOverride{contract: OverrideAccount{State: &map[common.Hash]common.Hash{{0x01}: common.BigToHash(big.NewInt(42))}}},
+			want:      word(0),
+		},
+		// state and stateDiff are mutually exclusive
+		{
+			overrides: StateOverride{contract: OverrideAccount{State: &map[common.Hash]common.Hash{}, StateDiff: &map[common.Hash]common.Hash{}}},
+			expectErr: true,
+		},
+		// code and balance overrides
+		{
+			overrides: StateOverride{contract: OverrideAccount{Code: &[]hexutil.Bytes{returnOp("3031")}[0], Balance: &balance}},
+			want:      word(1000),
+		},
+		// block context overrides
+		{
+			overrides:      StateOverride{contract: OverrideAccount{Code: &[]hexutil.Bytes{returnOp("43")}[0]}},
+			blockOverrides: &BlockOverrides{Number: number},
+			want:           word(0x1234),
+		},
+		{
+			overrides:      StateOverride{contract: OverrideAccount{Code: &[]hexutil.Bytes{returnOp("42")}[0]}},
+			blockOverrides: &BlockOverrides{Time: &timestamp},
+			want:           word(0x5678),
+		},
+		{
+			overrides:      StateOverride{contract: OverrideAccount{Code: &[]hexutil.Bytes{returnOp("41")}[0]}},
+			blockOverrides: &BlockOverrides{Coinbase: &coinbase},
+			want:           hexutil.Bytes(common.BytesToHash(coinbase.Bytes()).Bytes()),
+		},
+		// the gas is paid from the TRC21 fee capacity the overrides fund
+		{
+			overrides: capacity(100000 * defaultGasPrice),
+			want:      word(1),
+		},
+		{
+			overrides: capacity(1),
+			expectErr: true,
+		},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	for i, tc := range testSuite {
+		args := CallArgs{From: accounts[0].addr, To: &contract, Gas: 100000}
+		result, err := api.Call(context.Background(), args, latest, &tc.overrides, tc.blockOverrides, nil)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("test %d: want error, have nothing", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: want no error, have %v", i, err)
+			continue
+		}
+		if !bytes.Equal(result, tc.want) {
+			t.Errorf("test %d, result mismatch, have %x, want %x", i, result, tc.want)
+		}
+	}
+}
+
+func TestCallMany(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		counter  = common.HexToAddress("0x00000000000000000000000000000000000c0de0")
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000c0de1")
+	)
+	// The reverter copies an Error("boom") revert reason out of its code
+	typ, _ := abi.NewType("string")
+	reason, _ := abi.Arguments{{Type: typ}}.Pack("boom")
+	reason = append(crypto.Keccak256([]byte("Error(string)"))[:4], reason...)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			// Increments storage slot 0, logs and returns the new value
+			counter:  {Balance: new(big.Int), Code: common.FromHex("0x6000546001018060005560005260206000a060206000f3")},
+			reverter: {Balance: new(big.Int), Code: append(common.FromHex("0x6064600c60003960646000fd"), reason...)},
+		},
+	}
+	api := NewPublicBlockChainAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	word := func(n int64) hexutil.Bytes {
+		return hexutil.Bytes(common.BigToHash(big.NewInt(n)).Bytes())
+	}
+	calls := []CallArgs{
+		{From: accounts[0].addr, To: &counter},
+		{From: accounts[0].addr, To: &reverter},
+		{From: accounts[0].addr, To: &counter, Gas: 100000},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	for _, sequential := range []bool{false, true} {
+		results, err := api.CallMany(context.Background(), calls, latest, nil, nil, &CallManyOptions{Sequential: sequential})
+		if err != nil {
+			t.Fatalf("sequential %v: unexpected error: %v", sequential, err)
+		}
+		if len(results) != len(calls) {
+			t.Fatalf("sequential %v: result count mismatch: have %d, want %d", sequential, len(results), len(calls))
+		}
+		last := int64(1)
+		if sequential {
+			last = 2
+		}
+		for i, want := range []hexutil.Bytes{word(1), reason, word(last)} {
+			if !bytes.Equal(results[i].ReturnData, want) {
+				t.Errorf("sequential %v, call %d: result mismatch: have %x, want %x", sequential, i, results[i].ReturnData, want)
+			}
+		}
+		for _, i := range []int{0, 2} {
+			if results[i].Status != 1 || results[i].Error != "" || len(results[i].Logs) != 1 || results[i].GasUsed == 0 {
+				t.Errorf("sequential %v, call %d: unexpected result %+v", sequential, i, results[i])
+			}
+		}
+		if res := results[1]; res.Status != 0 || res.Error != vm.ErrExecutionReverted.Error() || res.RevertReason != "boom" || len(res.Logs) != 0 {
+			t.Errorf("sequential %v: unexpected revert result %+v", sequential, res)
+		}
+	}
+}
+
+func TestCallRevert(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000c0de1")
+	)
+	errorsABI, err := abi.JSON(strings.NewReader(`[{"type": "error", "name": "Unauthorized", "inputs": [{"name": "code", "type": "uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringType, _ := abi.NewType("string")
+	reason, _ := abi.Arguments{{Type: stringType}}.Pack("boom")
+	reason = append(crypto.Keccak256([]byte("Error(string)"))[:4], reason...)
+	custom, _ := errorsABI.Errors["Unauthorized"].Inputs.Pack(big.NewInt(7))
+	custom = append(errorsABI.Errors["Unauthorized"].Id(), custom...)
+
+	// The reverter copies its revert data out of the 100 bytes after its code
+	revertCode := func(data []byte) *hexutil.Bytes {
+		code := hexutil.Bytes(append(common.FromHex("0x6064600c60003960646000fd"), common.RightPadBytes(data, 100)...))
+		return &code
+	}
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	api := NewPublicBlockChainAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	var testSuite = []struct {
+		data      []byte
+		errorsABI *abi.ABI
+		wantMsg   string
+	}{
+		{reason, nil, "execution reverted: boom"},
+		{custom, nil, "execution reverted"},
+		{custom, &errorsABI, "execution reverted: Unauthorized(7)"},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	for i, tc := range testSuite {
+		args := CallArgs{From: accounts[0].addr, To: &reverter, Gas: 100000}
+		overrides := StateOverride{reverter: OverrideAccount{Code: revertCode(tc.data)}}
+
+		_, err := api.Call(context.Background(), args, latest, &overrides, nil, tc.errorsABI)
+		if err == nil || err.Error() != tc.wantMsg {
+			t.Errorf("test %d: call error mismatch: have %v, want %q", i, err, tc.wantMsg)
+			continue
+		}
+		revert, ok := err.(*callError)
+		if !ok {
+			t.Fatalf("test %d: error is not a revert error: %T", i, err)
+		}
+		if revert.ErrorCode() != 3 || revert.ErrorData() != hexutil.Encode(common.RightPadBytes(tc.data, 100)) {
+			t.Errorf("test %d: revert error mismatch: code %d, data %v", i, revert.ErrorCode(), revert.ErrorData())
+		}
+		args.Gas = 0
+		if _, err := api.EstimateGas(context.Background(), args, &latest, &overrides, nil, tc.errorsABI); err == nil || err.Error() != tc.wantMsg {
+			t.Errorf("test %d: estimate error mismatch: have %v, want %q", i, err, tc.wantMsg)
+		}
+	}
+}
+
+func TestCreateAccessList(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0x00000000000000000000000000000000000c0de2")
+		other    = common.HexToAddress("0x00000000000000000000000000000000000c0de3")
+		config   = *params.TestChainConfig
+	)
+	config.EIP2929Block = big.NewInt(0)
+
+	// The contract loads its slots 0 and 1 and the code size of the other account
+	code := common.FromHex("0x600054506001545073" + common.Bytes2Hex(other.Bytes()) + "3b5000")
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Balance: new(big.Int), Code: code},
+		},
+	}
+	api := NewPublicBlockChainAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	result, err := api.CreateAccessList(context.Background(), CallArgs{From: accounts[0].addr, To: &contract, Gas: 100000}, &latest)
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("execution failed: %v", result.Error)
+	}
+	want := map[common.Address][]common.Hash{
+		contract: {common.BigToHash(big.NewInt(0)), common.BigToHash(big.NewInt(1))},
+		other:    nil,
+	}
+	if len(*result.Accesslist) != len(want) {
+		t.Fatalf("access list length mismatch: have %d, want %d", len(*result.Accesslist), len(want))
+	}
+	for _, tuple := range *result.Accesslist {
+		slots, ok := want[tuple.Address]
+		if !ok {
+			t.Fatalf("unexpected address in access list: %x", tuple.Address)
+		}
+		if len(tuple.StorageKeys) != len(slots) {
+			t.Fatalf("storage keys of %x mismatch: have %v, want %v", tuple.Address, tuple.StorageKeys, slots)
+		}
+		for _, slot := range slots {
+			found := false
+			for _, key := range tuple.StorageKeys {
+				found = found || key == slot
+			}
+			if !found {
+				t.Errorf("storage key %x of %x missing", slot, tuple.Address)
+			}
+		}
+	}
+	// With the slots and the account warm, every access costs WARM_STORAGE_READ_COST
+	wantGas := params.TxGas + 2*params.TxAccessListAddressGas + 2*params.TxAccessListStorageKeyGas +
+		3*params.WarmStorageReadCostEIP2929 + 3*3 + 3*2
+	if uint64(result.GasUsed) != wantGas {
+		t.Errorf("gas used mismatch: have %d, want %d", result.GasUsed, wantGas)
+	}
+
+	// The access list cuts the cost of the call against the cold accesses
+	args := CallArgs{From: accounts[0].addr, To: &contract, Gas: 100000}
+	gas, err := api.EstimateGas(context.Background(), args, &latest, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	coldGas := params.TxGas + 2*params.ColdSloadCostEIP2929 + params.ColdAccountAccessCostEIP2929 + 3*3 + 3*2
+	if uint64(gas) != coldGas {
+		t.Errorf("gas without access list mismatch: have %d, want %d", gas, coldGas)
+	}
+}
+
+func TestGetProof(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0x00000000000000000000000000000000000c0de2")
+		missing  = common.HexToAddress("0x00000000000000000000000000000000000c0de3")
+		code     = common.FromHex("0x600054")
+		slot     = common.BigToHash(big.NewInt(1))
+		value    = common.BigToHash(big.NewInt(42))
+	)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Balance: big.NewInt(1000), Nonce: 1, Code: code, Storage: map[common.Hash]common.Hash{slot: value}},
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	api := NewPublicBlockChainAPI(backend)
+	root := backend.chain.CurrentBlock().Root()
+
+	toDb := func(proof []string) *memorydb.Database {
+		proofDb := memorydb.New()
+		for _, node := range proof {
+			blob := common.FromHex(node)
+			proofDb.Put(crypto.Keccak256(blob), blob)
+		}
+		return proofDb
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	result, err := api.GetProof(context.Background(), contract, []string{slot.Hex(), "0x02"}, latest)
+	if err != nil {
+		t.Fatalf("failed to get proof: %v", err)
+	}
+	// The account proof resolves to the returned account against the state root
+	enc, err := trie.VerifyProof(root, crypto.Keccak256(contract.Bytes()), toDb(result.AccountProof))
+	if err != nil {
+		t.Fatalf("failed to verify account proof: %v", err)
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(enc, &account); err != nil {
+		t.Fatalf("failed to decode proven account: %v", err)
+	}
+	if account.Nonce != uint64(result.Nonce) || account.Balance.Cmp(result.Balance.ToInt()) != 0 ||
+		account.Root != result.StorageHash || common.BytesToHash(account.CodeHash) != result.CodeHash {
+		t.Errorf("proven account mismatch: have %+v, returned %+v", account, result)
+	}
+	if result.Balance.ToInt().Int64() != 1000 || result.Nonce != 1 || result.CodeHash != crypto.Keccak256Hash(code) {
+		t.Errorf("account mismatch: have balance %v, nonce %d, code hash %x", result.Balance, result.Nonce, result.CodeHash)
+	}
+	// The storage proofs resolve to the returned values against the storage root
+	for i, want := range []common.Hash{value, {}} {
+		proof := result.StorageProof[i]
+		if common.BigToHash(proof.Value.ToInt()) != want {
+			t.Errorf("slot %s: value mismatch: have %v, want %x", proof.Key, proof.Value, want)
+		}
+		enc, err := trie.VerifyProof(result.StorageHash, crypto.Keccak256(common.HexToHash(proof.Key).Bytes()), toDb(proof.Proof))
+		if err != nil {
+			t.Fatalf("slot %s: failed to verify storage proof: %v", proof.Key, err)
+		}
+		var proven common.Hash
+		if len(enc) > 0 {
+			_, content, _, err := rlp.Split(enc)
+			if err != nil {
+				t.Fatalf("slot %s: failed to decode proven value: %v", proof.Key, err)
+			}
+			proven = common.BytesToHash(content)
+		}
+		if proven != want {
+			t.Errorf("slot %s: proven value mismatch: have %x, want %x", proof.Key, proven, want)
+		}
+	}
+	// A missing account is proven absent from the state root
+	result, err = api.GetProof(context.Background(), missing, []string{slot.Hex()}, latest)
+	if err != nil {
+		t.Fatalf("failed to get proof of missing account: %v", err)
+	}
+	if enc, err := trie.VerifyProof(root, crypto.Keccak256(missing.Bytes()), toDb(result.AccountProof)); err != nil || enc != nil {
+		t.Errorf("missing account not proven absent: have %x, err %v", enc, err)
+	}
+	if result.StorageHash != types.EmptyRootHash || result.CodeHash != crypto.Keccak256Hash(nil) || len(result.StorageProof[0].Proof) != 0 {
+		t.Errorf("missing account mismatch: have %+v", result)
+	}
+}
+
+func TestScanStealthOutputs(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "privacy-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	api := &PrivatePrivacyAPI{am: accounts.NewManager(ks)}
+
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{accounts[0].addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.HomesteadSigner{}
+	)
+	if _, err := ks.ImportECDSA(accounts[1].key, "password"); err != nil {
+		t.Fatal(err)
+	}
+	receiver := privacy.NewStealthKeys(accounts[1].key).Address().Bytes()
+
+	// Every block carries a stealth transfer to the keystore account and one
+	// to another stealth address
+	other, _ := crypto.GenerateKey()
+	var want []common.Hash
+	genDb := rawdb.NewMemoryDatabase()
+	genesis.MustCommit(genDb)
+	genChain, _ := core.NewBlockChain(genDb, nil, genesis.Config, ethash.NewFaker(), vm.Config{})
+	defer genChain.Stop()
+	backend := newTestBackend(t, 3, genesis, func(i int, b *core.BlockGen) {
+		for j, addr := range [][]byte{privacy.NewStealthKeys(other).Address().Bytes(), receiver} {
+			transfer, err := api.StealthTransfer(addr)
+			if err != nil {
+				t.Fatalf("failed to create stealth transfer: %v", err)
+			}
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(accounts[0].addr), transfer.To, big.NewInt(int64(1000+i)), 50000, nil, transfer.Data), signer, accounts[0].key)
+			b.AddTxWithChain(genChain, tx)
+			if j == 1 && i > 0 {
+				want = append(want, tx.Hash())
+			}
+		}
+	})
+	api.b = backend
+
+	outputs, err := api.ScanOutputs(context.Background(), accounts[1].addr, "password", 2, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to scan outputs: %v", err)
+	}
+	if len(outputs) != len(want) {
+		t.Fatalf("output count mismatch: have %d, want %d", len(outputs), len(want))
+	}
+	keys := privacy.NewStealthKeys(accounts[1].key)
+	for i, output := range outputs {
+		if output.TxHash != want[i] || uint64(output.BlockNumber) != uint64(i+2) || output.Value.ToInt().Int64() != int64(1000+i+1) {
+			t.Errorf("output %d mismatch: have %+v", i, output)
+		}
+		// The recipient holds the one-time key of the output
+		txKey, err := privacy.ParseCompressed(output.TxKey)
+		if err != nil {
+			t.Fatalf("output %d: invalid transaction key: %v", i, err)
+		}
+		if addr := crypto.PubkeyToAddress(keys.OutputKey(txKey).PublicKey); addr != output.To {
+			t.Errorf("output %d: one-time key mismatch: have %x, want %x", i, addr, output.To)
+		}
+	}
+	if _, err := api.ScanOutputs(context.Background(), accounts[1].addr, "wrong", 0, rpc.LatestBlockNumber); err == nil {
+		t.Error("outputs scanned with a wrong password")
+	}
+	if _, err := api.ScanOutputs(context.Background(), accounts[1].addr, "password", 3, 1); err == nil {
+		t.Error("outputs scanned over an inverted range")
+	}
+}
+
 func TestRPCGetBlockReceipts(t *testing.T) {
 	t.Parallel()
 
@@ -650,3 +1137,238 @@ func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc s
 	}
 	require.JSONEqf(t, string(want), string(data), "test %d: json not match, want: %s, have: %s", testid, string(want), string(data))
 }
+
+func TestTRC21EstimateFee(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(1)
+		token    = common.HexToAddress("0x00000000000000000000000000000000000c0de2")
+		other    = common.HexToAddress("0x00000000000000000000000000000000000c0de3")
+		capacity = big.NewInt(params.Ether)
+	)
+	// Register the token in the issuer contract with some fee capacity
+	tokens := common.BigToHash(new(big.Int).SetUint64(state.SlotTRC21Issuer["tokens"]))
+	tokenState := state.GetLocMappingAtKey(token.Hash(), state.SlotTRC21Issuer["tokensState"])
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			common.TRC21IssuerSMC: {Balance: new(big.Int), Storage: map[common.Hash]common.Hash{
+				tokens: common.BigToHash(big.NewInt(1)),
+				state.GetLocDynamicArrAtElement(tokens, 0, 1): token.Hash(),
+				common.BigToHash(tokenState):                  common.BigToHash(capacity),
+			}},
+		},
+	}
+	api := NewPublicTRC21API(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	have, err := api.GetFeeCapacity(context.Background(), token, latest)
+	if err != nil || have.ToInt().Cmp(capacity) != 0 {
+		t.Fatalf("fee capacity mismatch: have %v (%v), want %v", have, err, capacity)
+	}
+	if have, _ := api.GetFeeCapacity(context.Background(), other, latest); have != nil {
+		t.Fatalf("unexpected fee capacity for unregistered token: %v", have)
+	}
+	list, err := api.ListSponsoredTokens(context.Background(), latest)
+	if err != nil || len(list) != 1 || list[token].ToInt().Cmp(capacity) != 0 {
+		t.Fatalf("sponsored tokens mismatch: have %v (%v)", list, err)
+	}
+	// The sender holds nothing, so only the sponsored transaction is accepted
+	gas := hexutil.Uint64(50000)
+	fee := new(big.Int).Mul(common.TRC21GasPrice, big.NewInt(int64(gas)))
+	var testSuite = []struct {
+		to         common.Address
+		sponsored  bool
+		wantReason string
+	}{
+		{token, true, ""},
+		{other, false, core.ErrInsufficientFunds.Error()},
+	}
+	for i, tc := range testSuite {
+		to := tc.to
+		args := SendTxArgs{From: accounts[0].addr, To: &to, Gas: &gas, GasPrice: (*hexutil.Big)(common.MinGasPrice)}
+		res, err := api.EstimateFee(context.Background(), args, &latest)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if res.Sponsored != tc.sponsored || res.RejectReason != tc.wantReason {
+			t.Errorf("test %d: result mismatch: have sponsored %v, reason %q, want %v, %q", i, res.Sponsored, res.RejectReason, tc.sponsored, tc.wantReason)
+		}
+		if res.TRC21Cost.ToInt().Cmp(fee) != 0 {
+			t.Errorf("test %d: TRC21 cost mismatch: have %v, want %v", i, res.TRC21Cost, fee)
+		}
+		if tc.sponsored {
+			if want := new(big.Int).Sub(capacity, fee); res.RemainingCapacity.ToInt().Cmp(want) != 0 {
+				t.Errorf("test %d: remaining capacity mismatch: have %v, want %v", i, res.RemainingCapacity, want)
+			}
+		} else if res.FeeCapacity != nil || res.RemainingCapacity != nil {
+			t.Errorf("test %d: unexpected capacity for unsponsored transaction: %+v", i, res)
+		}
+	}
+}
+
+func TestTRC21ValidateToken(t *testing.T) {
+	t.Parallel()
+	var (
+		valid   = common.HexToAddress("0x00000000000000000000000000000000000c0de4")
+		invalid = common.HexToAddress("0x00000000000000000000000000000000000c0de5")
+		missing = common.HexToAddress("0x00000000000000000000000000000000000c0de6")
+	)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			valid:   {Balance: new(big.Int), Code: trc21RuntimeCode(t)},
+			invalid: {Balance: new(big.Int), Code: []byte{byte(vm.STOP)}},
+		},
+	}
+	api := NewPublicTRC21API(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	if ok, err := api.ValidateToken(context.Background(), valid, latest); !ok || err != nil {
+		t.Errorf("standard token rejected: %v", err)
+	}
+	if ok, err := api.ValidateToken(context.Background(), invalid, latest); ok || err == nil {
+		t.Error("contract without token storage layout accepted")
+	}
+	if ok, err := api.ValidateToken(context.Background(), missing, latest); ok || err == nil {
+		t.Error("account without code accepted")
+	}
+}
+
+// trc21RuntimeCode deploys a standard TRC21 token, minting to a non-zero
+// issuer, and returns its runtime code.
+func trc21RuntimeCode(t *testing.T) []byte {
+	tokenABI, err := abi.JSON(strings.NewReader(trc21contract.MyTRC21ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := tokenABI.Pack("", "Token", "TKN", uint8(18), big.NewInt(params.Ether), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, _, err := runtime.Create(append(common.FromHex(trc21contract.MyTRC21Bin), args...), &runtime.Config{ChainConfig: params.TestChainConfig, Origin: common.HexToAddress("0xc0de")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestTomoXRegistry(t *testing.T) {
+	t.Parallel()
+	var (
+		coinbase = common.HexToAddress("0x00000000000000000000000000000000000c0de1")
+		owner    = common.HexToAddress("0x00000000000000000000000000000000000c0de2")
+		token    = common.HexToAddress("0x00000000000000000000000000000000000c0de4")
+		native   = common.HexToAddress(common.TomoNativeAddress)
+		deposit  = new(big.Int).Mul(big.NewInt(25000), big.NewInt(params.Ether))
+
+		relayers = make(map[common.Hash]common.Hash)
+		listing  = make(map[common.Hash]common.Hash)
+	)
+	// Lay out the storage of the registration and listing contracts the way
+	// the registration of a relayer trading token/TOMO leaves it
+	relayer := state.GetLocMappingAtKey(coinbase.Hash(), tradingstate.RelayerMappingSlot["RELAYER_LIST"])
+	field := func(name string) common.Hash {
+		return common.BigToHash(new(big.Int).Add(relayer, tradingstate.RelayerStructMappingSlot[name]))
+	}
+	relayers[state.GetLocSimpleVariable(tradingstate.RelayerMappingSlot["RelayerCount"])] = common.BigToHash(big.NewInt(1))
+	relayers[state.GetLocSimpleVariable(tradingstate.RelayerMappingSlot["MinimumDeposit"])] = common.BigToHash(deposit)
+	relayers[common.BigToHash(state.GetLocMappingAtKey(common.BigToHash(common.Big0), tradingstate.RelayerMappingSlot["RELAYER_COINBASES"]))] = coinbase.Hash()
+	relayers[field("_deposit")] = common.BigToHash(deposit)
+	relayers[field("_fee")] = common.BigToHash(big.NewInt(10))
+	relayers[field("_fromTokens")] = common.BigToHash(common.Big1)
+	relayers[state.GetLocDynamicArrAtElement(field("_fromTokens"), 0, 1)] = token.Hash()
+	relayers[field("_toTokens")] = common.BigToHash(common.Big1)
+	relayers[state.GetLocDynamicArrAtElement(field("_toTokens"), 0, 1)] = native.Hash()
+	relayers[field("_owner")] = owner.Hash()
+
+	listing[state.GetLocSimpleVariable(tradingstate.ListingMappingSlot["_tokens"])] = common.BigToHash(common.Big1)
+	listing[state.GetLocDynamicArrAtElement(state.GetLocSimpleVariable(tradingstate.ListingMappingSlot["_tokens"]), 0, 1)] = token.Hash()
+	listing[common.BigToHash(state.GetLocMappingAtKey(token.Hash(), tradingstate.ListingMappingSlot["tokensState"]))] = common.BigToHash(common.Big1)
+
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			common.HexToAddress(common.RelayerRegistrationSMC): {Balance: deposit, Storage: relayers},
+			common.TomoXListingSMC:                             {Balance: new(big.Int), Storage: listing},
+			token:                                              {Balance: new(big.Int), Code: trc21RuntimeCode(t)},
+		},
+	}
+	api := NewPublicTomoXRegistryAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	info, err := api.GetRelayer(context.Background(), coinbase, latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RelayerInfo{
+		Coinbase: coinbase,
+		Owner:    owner,
+		Deposit:  (*hexutil.Big)(deposit),
+		TradeFee: 10,
+		Pairs:    []tomox.TradingPair{{BaseToken: token, QuoteToken: native}},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("relayer mismatch: have %+v, want %+v", info, want)
+	}
+	if info, err := api.GetRelayer(context.Background(), owner, latest); info != nil || err != nil {
+		t.Errorf("unregistered relayer found: %+v, %v", info, err)
+	}
+	if relayers, err := api.ListRelayers(context.Background(), latest); err != nil || len(relayers) != 1 || relayers[0].Coinbase != coinbase {
+		t.Errorf("relayer list mismatch: %v, %v", relayers, err)
+	}
+	if min, err := api.GetMinimumDeposit(context.Background(), latest); err != nil || min.ToInt().Cmp(deposit) != 0 {
+		t.Errorf("minimum deposit mismatch: have %v, want %v", min, deposit)
+	}
+	if tokens, err := api.ListTokens(context.Background(), latest); err != nil || !reflect.DeepEqual(tokens, []common.Address{token}) {
+		t.Errorf("listed tokens mismatch: %v, %v", tokens, err)
+	}
+	if ok, err := api.ValidateToken(context.Background(), token, latest); !ok || err != nil {
+		t.Errorf("standard token rejected: %v", err)
+	}
+	if ok, err := api.ValidateToken(context.Background(), owner, latest); ok || err == nil {
+		t.Error("account without code accepted")
+	}
+}
+
+func TestGetEpochPrices(t *testing.T) {
+	t.Parallel()
+	config := *params.TestChainConfig
+	backend := newTestBackend(t, 5, &core.Genesis{Config: &config}, func(i int, b *core.BlockGen) {})
+	// Only split the chain into epochs once imported, as importing blocks with
+	// PoSV epochs requires the PoSV engine
+	config.Posv = &params.PosvConfig{Epoch: 2}
+	backend.TomoX = tomox.New(&tomox.Config{DataDir: t.TempDir()})
+	defer backend.TomoX.GetLevelDB().Close()
+	api := NewPublicTomoXRegistryAPI(backend)
+
+	pair := tomox.TradingPair{BaseToken: common.HexToAddress("0x1"), QuoteToken: common.HexToAddress("0x2")}
+	orderBook := tradingstate.GetTradingOrderBookHash(pair.BaseToken, pair.QuoteToken)
+	other := tradingstate.GetTradingOrderBookHash(pair.QuoteToken, pair.BaseToken)
+	for lastBlock, prices := range map[common.Hash]map[common.Hash]*big.Int{
+		backend.chain.GetHeaderByNumber(1).Hash(): {orderBook: big.NewInt(100), other: big.NewInt(1)},
+		backend.chain.GetHeaderByNumber(3).Hash(): {orderBook: big.NewInt(120)},
+		common.HexToHash("0xf0"):                  {orderBook: big.NewInt(130)}, // Side fork of the second epoch
+	} {
+		if err := backend.TomoX.WriteEpochPrices(lastBlock, prices); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prices, err := api.GetEpochPrices(context.Background(), pair, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EpochPrice{
+		{Epoch: 1, Price: (*hexutil.Big)(big.NewInt(100))},
+		{Epoch: 2, Price: (*hexutil.Big)(big.NewInt(120))},
+	}
+	if !reflect.DeepEqual(prices, want) {
+		t.Errorf("prices mismatch: have %v, want %v", prices, want)
+	}
+	if _, err := api.GetEpochPrices(context.Background(), pair, 5, 0); err == nil {
+		t.Error("inverted range accepted")
+	}
+	if _, err := api.GetEpochPrices(context.Background(), pair, 0, maxEpochPriceRange); err == nil {
+		t.Error("oversized range accepted")
+	}
+}
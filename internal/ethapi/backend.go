@@ -20,6 +20,7 @@ package ethapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 	"github.com/tomochain/tomochain/tomoxlending"
@@ -48,6 +49,9 @@ type Backend interface {
 	Downloader() *downloader.Downloader
 	ProtocolVersion() int
 	SuggestPrice(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []float64, []float64, error)
+	RPCGasCap() uint64            // global gas cap for eth_callMany
+	RPCEVMTimeout() time.Duration // global timeout for eth_call and eth_callMany
 	ChainDb() ethdb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
@@ -127,6 +131,16 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicDebugAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "trc21",
+			Version:   "1.0",
+			Service:   NewPublicTRC21API(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "tomox",
+			Version:   "1.0",
+			Service:   NewPublicTomoXRegistryAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -141,6 +155,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateAccountAPI(apiBackend, nonceLock),
 			Public:    false,
+		}, {
+			Namespace: "privacy",
+			Version:   "1.0",
+			Service:   NewPrivatePrivacyAPI(apiBackend),
+			Public:    false,
 		},
 	}
 }
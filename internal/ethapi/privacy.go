@@ -0,0 +1,237 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/tomochain/tomochain/accounts"
+	"github.com/tomochain/tomochain/accounts/keystore"
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/core/vm/privacy"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+// maxPrivacyScanRange is the maximum number of blocks scanned for incoming
+// stealth transfers in a single request.
+const maxPrivacyScanRange = 10000
+
+// PrivatePrivacyAPI provides the wallet tooling of the private transfers made
+// through the ring signature and bulletproof precompiles: stealth addresses,
+// ring signatures and range proofs, along with the stealth transfers of plain
+// value to one-time keys and a scanner of the incoming ones. It's private as it
+// reads the keys of the keystore.
+type PrivatePrivacyAPI struct {
+	am *accounts.Manager
+	b  Backend
+}
+
+// NewPrivatePrivacyAPI creates a new privacy wallet API.
+func NewPrivatePrivacyAPI(b Backend) *PrivatePrivacyAPI {
+	return &PrivatePrivacyAPI{am: b.AccountManager(), b: b}
+}
+
+// PrivacyOutput is an output sent to a one-time key, along with the
+// transaction key of its sender.
+type PrivacyOutput struct {
+	OneTimeKey hexutil.Bytes `json:"oneTimeKey"`
+	TxKey      hexutil.Bytes `json:"txKey"`
+}
+
+// StealthTransfer is the recipient and the data of a transaction transferring
+// value to a new one-time key of a stealth address.
+type StealthTransfer struct {
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+// StealthOutput is a stealth transfer received by an account. The one-time key
+// of its recipient is derived from the transaction key to spend it.
+type StealthOutput struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	To          common.Address `json:"to"`
+	TxKey       hexutil.Bytes  `json:"txKey"`
+	Value       *hexutil.Big   `json:"value"`
+}
+
+// RingInput is an output spent by a ring signature, identified by the
+// transaction key it was sent with and hidden among decoy one-time keys.
+type RingInput struct {
+	TxKey  hexutil.Bytes   `json:"txKey"`
+	Decoys []hexutil.Bytes `json:"decoys"`
+}
+
+// RangeProof is an aggregated range proof along with the commitments of the
+// proven values and their blinding factors.
+type RangeProof struct {
+	Proof           hexutil.Bytes   `json:"proof"`
+	Commitments     []hexutil.Bytes `json:"commitments"`
+	BlindingFactors []*hexutil.Big  `json:"blindingFactors"`
+}
+
+// StealthAddress returns the stealth address of a keystore account, which
+// senders derive one-time keys from.
+func (s *PrivatePrivacyAPI) StealthAddress(addr common.Address, password string) (hexutil.Bytes, error) {
+	keys, err := StealthKeys(s.am, addr, password)
+	if err != nil {
+		return nil, err
+	}
+	return keys.Address().Bytes(), nil
+}
+
+// GenerateOneTimeKey derives a new one-time key of a stealth address to send
+// an output to.
+func (s *PrivatePrivacyAPI) GenerateOneTimeKey(stealthAddress hexutil.Bytes) (*PrivacyOutput, error) {
+	addr, err := privacy.ParseStealthAddress(stealthAddress)
+	if err != nil {
+		return nil, err
+	}
+	oneTime, txKey, err := addr.GenerateOneTimeKey()
+	if err != nil {
+		return nil, err
+	}
+	return &PrivacyOutput{OneTimeKey: privacy.SerializeCompressed(oneTime), TxKey: privacy.SerializeCompressed(txKey)}, nil
+}
+
+// SignRing creates a ring signature of a message spending outputs of a
+// keystore account, each hidden among its decoys.
+func (s *PrivatePrivacyAPI) SignRing(addr common.Address, password string, message common.Hash, inputs []RingInput) (hexutil.Bytes, error) {
+	keys, err := StealthKeys(s.am, addr, password)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		spendKeys = make([]*ecdsa.PrivateKey, len(inputs))
+		decoys    = make([][]*ecdsa.PublicKey, len(inputs))
+	)
+	for i, input := range inputs {
+		txKey, err := privacy.ParseCompressed(input.TxKey)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: invalid transaction key: %v", i, err)
+		}
+		spendKeys[i] = keys.OutputKey(txKey)
+		for j, decoy := range input.Decoys {
+			pub, err := privacy.ParseCompressed(decoy)
+			if err != nil {
+				return nil, fmt.Errorf("input %d: invalid decoy %d: %v", i, j, err)
+			}
+			decoys[i] = append(decoys[i], pub)
+		}
+	}
+	return privacy.SignInputs(message, spendKeys, decoys)
+}
+
+// ProveRange creates an aggregated range proof of 1, 2, 4 or 8 values below
+// 2^64. The blinding factors of the commitments are needed to spend them.
+func (s *PrivatePrivacyAPI) ProveRange(values []hexutil.Big) (*RangeProof, error) {
+	ints := make([]*big.Int, len(values))
+	for i := range values {
+		ints[i] = values[i].ToInt()
+	}
+	proof, commitments, blindings, err := privacy.ProveRange(ints)
+	if err != nil {
+		return nil, err
+	}
+	result := &RangeProof{Proof: proof}
+	for i, commitment := range commitments {
+		result.Commitments = append(result.Commitments, privacy.SerializeCompressed(commitment))
+		result.BlindingFactors = append(result.BlindingFactors, (*hexutil.Big)(blindings[i]))
+	}
+	return result, nil
+}
+
+// StealthTransfer returns the recipient and the data of a transaction
+// transferring value to a new one-time key of a stealth address, to be sent
+// with eth_sendTransaction.
+func (s *PrivatePrivacyAPI) StealthTransfer(stealthAddress hexutil.Bytes) (*StealthTransfer, error) {
+	addr, err := privacy.ParseStealthAddress(stealthAddress)
+	if err != nil {
+		return nil, err
+	}
+	to, data, err := privacy.StealthTransfer(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StealthTransfer{To: to, Data: data}, nil
+}
+
+// ScanOutputs returns the stealth transfers in a range of blocks which were
+// sent to the stealth address of a keystore account.
+func (s *PrivatePrivacyAPI) ScanOutputs(ctx context.Context, addr common.Address, password string, fromBlock, toBlock rpc.BlockNumber) ([]*StealthOutput, error) {
+	keys, err := StealthKeys(s.am, addr, password)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.b.HeaderByNumber(ctx, fromBlock)
+	if from == nil || err != nil {
+		return nil, fmt.Errorf("block %v not found", fromBlock)
+	}
+	to, err := s.b.HeaderByNumber(ctx, toBlock)
+	if to == nil || err != nil {
+		return nil, fmt.Errorf("block %v not found", toBlock)
+	}
+	first, last := from.Number.Uint64(), to.Number.Uint64()
+	if first > last {
+		return nil, errors.New("invalid block range")
+	}
+	if last-first >= maxPrivacyScanRange {
+		return nil, fmt.Errorf("block range over %d blocks", maxPrivacyScanRange)
+	}
+	outputs := []*StealthOutput{}
+	for number := first; number <= last; number++ {
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil || err != nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		txs := block.Transactions()
+		for _, i := range privacy.ScanTransfers(keys, txs) {
+			outputs = append(outputs, &StealthOutput{
+				BlockNumber: hexutil.Uint64(number),
+				TxHash:      txs[i].Hash(),
+				To:          *txs[i].To(),
+				TxKey:       txs[i].Data(),
+				Value:       (*hexutil.Big)(txs[i].Value()),
+			})
+		}
+	}
+	return outputs, nil
+}
+
+// StealthKeys decrypts the key file of a keystore account and derives its
+// stealth keys from it.
+func StealthKeys(am *accounts.Manager, addr common.Address, password string) (*privacy.StealthKeys, error) {
+	account, err := fetchKeystore(am).Find(accounts.Account{Address: addr})
+	if err != nil {
+		return nil, err
+	}
+	keyJSON, err := ioutil.ReadFile(account.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+	return privacy.NewStealthKeys(key.PrivateKey), nil
+}
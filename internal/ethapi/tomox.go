@@ -0,0 +1,231 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/rpc"
+	"github.com/tomochain/tomochain/tomox"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+// PublicTomoXRegistryAPI provides an API to inspect the relayers registered in
+// the TomoX relayer registration contract, the tokens of the TomoX listing
+// contract and the epoch prices of the canonical chain. It's served in the
+// tomox namespace, next to the TomoX service.
+type PublicTomoXRegistryAPI struct {
+	b Backend
+}
+
+// NewPublicTomoXRegistryAPI creates a new TomoX registry API.
+func NewPublicTomoXRegistryAPI(b Backend) *PublicTomoXRegistryAPI {
+	return &PublicTomoXRegistryAPI{b}
+}
+
+// RelayerInfo is the registration of a relayer.
+type RelayerInfo struct {
+	Coinbase    common.Address      `json:"coinbase"`
+	Owner       common.Address      `json:"owner"`
+	Deposit     *hexutil.Big        `json:"deposit"`
+	TradeFee    hexutil.Uint64      `json:"tradeFee"`              // Trading fee, in 1/10000 of the traded amount
+	ReleaseTime *hexutil.Big        `json:"releaseTime,omitempty"` // Time the deposit is released at, if resigned
+	Pairs       []tomox.TradingPair `json:"pairs"`
+}
+
+// GetRelayer returns the registration of a relayer at the given block, or null
+// if the coinbase isn't registered.
+func (api *PublicTomoXRegistryAPI) GetRelayer(ctx context.Context, coinbase common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*RelayerInfo, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	return relayerInfo(statedb, coinbase)
+}
+
+// ListRelayers returns the registrations of all the relayers at the given block.
+func (api *PublicTomoXRegistryAPI) ListRelayers(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*RelayerInfo, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	relayers := []*RelayerInfo{}
+	for _, coinbase := range tradingstate.GetAllCoinbases(statedb) {
+		info, err := relayerInfo(statedb, coinbase)
+		if err != nil {
+			return nil, err
+		}
+		if info != nil {
+			relayers = append(relayers, info)
+		}
+	}
+	return relayers, nil
+}
+
+// GetMinimumDeposit returns the deposit needed to register a relayer at the
+// given block.
+func (api *PublicTomoXRegistryAPI) GetMinimumDeposit(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(tradingstate.GetMinimumDeposit(statedb)), nil
+}
+
+// ListTokens returns the tokens listed in the TomoX listing contract at the
+// given block.
+func (api *PublicTomoXRegistryAPI) ListTokens(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]common.Address, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	return tradingstate.GetListedTokens(statedb), nil
+}
+
+// ValidateToken checks at the state of the given block that a token keeps its
+// balances at the storage slot TomoX settles trades at and reports its
+// decimals, the way applications to the TomoX listing contract are validated.
+func (api *PublicTomoXRegistryAPI) ValidateToken(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (bool, error) {
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return false, err
+	}
+	if statedb.GetCodeSize(token) == 0 {
+		return false, fmt.Errorf("no contract at %s", token.Hex())
+	}
+	chain := &backendChainContext{ctx: ctx, b: api.b, header: header}
+	if err := core.ValidateTomoXApplyTransaction(chain, statedb, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UserFeeTier is the fee tier a user reached at a relayer.
+type UserFeeTier struct {
+	Relayer  common.Address `json:"relayer"`
+	User     common.Address `json:"user"`
+	Volume   *hexutil.Big   `json:"volume"`   // Volume traded through the relayer, in TOMO
+	Tier     hexutil.Uint64 `json:"tier"`     // Tier reached, 0 being none
+	MakerFee hexutil.Uint64 `json:"makerFee"` // Fee charged as maker, in 1/10000 of the traded amount
+	TakerFee hexutil.Uint64 `json:"takerFee"` // Fee charged as taker, in 1/10000 of the traded amount
+}
+
+// GetUserFeeTier returns the fee tier a user reached at a relayer at the given
+// block, with the maker and taker fees the user is charged past the maker/taker
+// fee fork, or null if the coinbase isn't registered.
+func (api *PublicTomoXRegistryAPI) GetUserFeeTier(ctx context.Context, coinbase common.Address, user common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*UserFeeTier, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if tradingstate.GetRelayerOwner(coinbase, statedb) == (common.Address{}) {
+		return nil, nil
+	}
+	tier, makerFee, takerFee := tradingstate.GetUserFeeTier(coinbase, user, statedb)
+	return &UserFeeTier{
+		Relayer:  coinbase,
+		User:     user,
+		Volume:   (*hexutil.Big)(tradingstate.GetUserVolume(coinbase, user, statedb)),
+		Tier:     hexutil.Uint64(tier),
+		MakerFee: hexutil.Uint64(makerFee.Uint64()),
+		TakerFee: hexutil.Uint64(takerFee.Uint64()),
+	}, nil
+}
+
+// maxEpochPriceRange is the maximum number of epochs whose prices are returned
+// in a single request.
+const maxEpochPriceRange = 1000
+
+// EpochPrice is the medium price of an order book over an epoch.
+type EpochPrice struct {
+	Epoch hexutil.Uint64 `json:"epoch"`
+	Price *hexutil.Big   `json:"price"`
+}
+
+// GetEpochPrices returns the medium prices of a pair over a range of epochs of
+// the canonical chain indexed by the node, skipping the epochs it has no price
+// of.
+func (api *PublicTomoXRegistryAPI) GetEpochPrices(ctx context.Context, pair tomox.TradingPair, fromEpoch, toEpoch hexutil.Uint64) ([]EpochPrice, error) {
+	if fromEpoch > toEpoch {
+		return nil, errors.New("invalid epoch range")
+	}
+	if toEpoch-fromEpoch >= maxEpochPriceRange {
+		return nil, fmt.Errorf("epoch range over %d epochs", maxEpochPriceRange)
+	}
+	config := api.b.ChainConfig()
+	if config.Posv == nil || config.Posv.Epoch == 0 {
+		return nil, errors.New("chain without epochs")
+	}
+	tomoX := api.b.TomoxService()
+	if tomoX == nil {
+		return nil, errors.New("TomoX service not running")
+	}
+	orderBook := tradingstate.GetTradingOrderBookHash(pair.BaseToken, pair.QuoteToken)
+	prices := []EpochPrice{}
+	for epoch := uint64(fromEpoch); epoch <= uint64(toEpoch) && epoch <= math.MaxInt64/config.Posv.Epoch; epoch++ {
+		// The prices of an epoch are indexed by its last block, the parent of
+		// the checkpoint computing them
+		checkpoint, err := api.b.HeaderByNumber(ctx, rpc.BlockNumber(epoch*config.Posv.Epoch))
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint == nil {
+			break
+		}
+		if price := tomoX.ReadEpochPrice(orderBook, checkpoint.ParentHash); price != nil {
+			prices = append(prices, EpochPrice{Epoch: hexutil.Uint64(epoch), Price: (*hexutil.Big)(price)})
+		}
+	}
+	return prices, nil
+}
+
+// relayerInfo reads the registration of a relayer, returning nil if it has no
+// owner.
+func relayerInfo(statedb *state.StateDB, coinbase common.Address) (*RelayerInfo, error) {
+	owner := tradingstate.GetRelayerOwner(coinbase, statedb)
+	if owner == (common.Address{}) {
+		return nil, nil
+	}
+	info := &RelayerInfo{
+		Coinbase: coinbase,
+		Owner:    owner,
+		Deposit:  (*hexutil.Big)(tradingstate.GetRelayerDeposit(coinbase, statedb)),
+		TradeFee: hexutil.Uint64(tradingstate.GetExRelayerFee(coinbase, statedb).Uint64()),
+		Pairs:    []tomox.TradingPair{},
+	}
+	if release := tradingstate.GetResignRequest(coinbase, statedb); release.Sign() > 0 {
+		info.ReleaseTime = (*hexutil.Big)(release)
+	}
+	bases, quotes := tradingstate.GetBaseTokenLength(coinbase, statedb), tradingstate.GetQuoteTokenLength(coinbase, statedb)
+	if bases != quotes {
+		return nil, fmt.Errorf("relayer %s has %d base tokens and %d quote tokens", coinbase.Hex(), bases, quotes)
+	}
+	for i := uint64(0); i < bases; i++ {
+		info.Pairs = append(info.Pairs, tomox.TradingPair{
+			BaseToken:  tradingstate.GetBaseTokenAtIndex(coinbase, statedb, i),
+			QuoteToken: tradingstate.GetQuoteTokenAtIndex(coinbase, statedb, i),
+		})
+	}
+	return info, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/hexutil"
+	"github.com/tomochain/tomochain/consensus"
+	"github.com/tomochain/tomochain/core"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/rpc"
+)
+
+// PublicTRC21API provides an API to inspect the sponsorship of transaction
+// fees by the TRC21 token issuers.
+type PublicTRC21API struct {
+	b Backend
+}
+
+// NewPublicTRC21API creates a new TRC21 fee sponsorship API.
+func NewPublicTRC21API(b Backend) *PublicTRC21API {
+	return &PublicTRC21API{b}
+}
+
+// GetFeeCapacity returns the fee capacity left by the issuer of a token at the
+// given block, or null if the token doesn't sponsor fees.
+func (api *PublicTRC21API) GetFeeCapacity(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.GetTRC21FeeCapacityFromStateWithToken(statedb, &token)), nil
+}
+
+// ListSponsoredTokens returns the tokens registered in the TRC21 issuer
+// contract at the given block along with their fee capacity.
+func (api *PublicTRC21API) ListSponsoredTokens(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[common.Address]*hexutil.Big, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	tokens := make(map[common.Address]*hexutil.Big)
+	for token, capacity := range state.GetTRC21FeeCapacityFromState(statedb) {
+		tokens[token] = (*hexutil.Big)(capacity)
+	}
+	return tokens, nil
+}
+
+// ValidateToken checks at the state of the given block that a token keeps its
+// balances and its minimum fee at the storage slots the fee sponsorship reads
+// them from, the way applications to the TRC21 issuer contract are validated.
+func (api *PublicTRC21API) ValidateToken(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (bool, error) {
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return false, err
+	}
+	if statedb.GetCodeSize(token) == 0 {
+		return false, fmt.Errorf("no contract at %s", token.Hex())
+	}
+	chain := &backendChainContext{ctx: ctx, b: api.b, header: header}
+	if err := core.ValidateTomoZApplyTransaction(chain, statedb, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// backendChainContext serves the chain context of the contract calls run at
+// the state of a given header by the backend.
+type backendChainContext struct {
+	ctx    context.Context
+	b      Backend
+	header *types.Header
+}
+
+func (c *backendChainContext) Engine() consensus.Engine { return c.b.GetEngine() }
+
+func (c *backendChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header, err := c.b.HeaderByHash(c.ctx, hash)
+	if err != nil || header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
+func (c *backendChainContext) CurrentHeader() *types.Header { return c.header }
+
+func (c *backendChainContext) Config() *params.ChainConfig { return c.b.ChainConfig() }
+
+// TRC21Fee describes who pays the fee of a transaction and how much.
+type TRC21Fee struct {
+	Sponsored         bool           `json:"sponsored"`         // Whether the fee is paid by the token issuer
+	Gas               hexutil.Uint64 `json:"gas"`               // Gas limit of the transaction
+	GasPrice          *hexutil.Big   `json:"gasPrice"`          // Price the gas is charged at
+	Fee               *hexutil.Big   `json:"fee"`               // Fee charged upfront for the gas limit
+	TRC21Cost         *hexutil.Big   `json:"trc21Cost"`         // Value plus the gas limit at the TRC21 gas price
+	FeeCapacity       *hexutil.Big   `json:"feeCapacity"`       // Fee capacity of the token, null if it doesn't sponsor fees
+	RemainingCapacity *hexutil.Big   `json:"remainingCapacity"` // Fee capacity left after deducting the fee, if sponsored
+	RejectReason      string         `json:"rejectReason,omitempty"`
+}
+
+// EstimateFee reports whether the fee of a transaction would be sponsored by
+// the issuer of the token it's sent to, at the state of the given block, the
+// pending one by default. The gas limit is estimated if not given. The reason
+// the transaction pool would reject the transaction is reported as well,
+// except for its nonce and signature which aren't checked.
+func (api *PublicTRC21API) EstimateFee(ctx context.Context, args SendTxArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*TRC21Fee, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	if args.Data == nil {
+		args.Data = args.Input
+	}
+	if args.Data == nil {
+		args.Data = &hexutil.Bytes{}
+	}
+	if args.Value == nil {
+		args.Value = new(hexutil.Big)
+	}
+	if args.Nonce == nil {
+		args.Nonce = new(hexutil.Uint64)
+	}
+	if args.GasPrice == nil {
+		price, err := api.b.SuggestPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args.GasPrice = (*hexutil.Big)(price)
+	}
+	if args.AccessList != nil && args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(api.b.ChainConfig().ChainId)
+	}
+	if args.Gas == nil {
+		call := CallArgs{From: args.From, To: args.To, Value: *args.Value, Data: *args.Data, AccessList: args.AccessList}
+		gas, err := NewPublicBlockChainAPI(api.b).EstimateGas(ctx, call, &bNrOrHash, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		args.Gas = &gas
+	}
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	var (
+		tx       = args.toTransaction()
+		config   = api.b.ChainConfig()
+		isAtlas  = config.IsAtlas(header.Number)
+		trc21Fee = new(big.Int).Sub(tx.TRC21Cost(), tx.Value())
+		capacity *big.Int
+	)
+	if tx.To() != nil {
+		capacity = state.GetTRC21FeeCapacityFromStateWithToken(statedb, tx.To())
+	}
+	result := &TRC21Fee{
+		Gas:         hexutil.Uint64(tx.Gas()),
+		GasPrice:    (*hexutil.Big)(tx.GasPrice()),
+		Fee:         (*hexutil.Big)(new(big.Int).Sub(tx.Cost(), tx.Value())),
+		TRC21Cost:   (*hexutil.Big)(tx.TRC21Cost()),
+		FeeCapacity: (*hexutil.Big)(capacity),
+	}
+	// The issuer pays the fee at the TRC21 gas price if it has the capacity
+	// for it after Atlas, and whenever the token is registered before
+	if capacity != nil && (!isAtlas || capacity.Cmp(trc21Fee) > 0) {
+		result.Sponsored = true
+		result.GasPrice, result.Fee = (*hexutil.Big)(common.TRC21GasPrice), (*hexutil.Big)(trc21Fee)
+		result.RemainingCapacity = (*hexutil.Big)(new(big.Int).Sub(capacity, trc21Fee))
+	}
+	if err := core.ValidateTxCost(config, header.Number, statedb, args.From, tx); err != nil {
+		result.RejectReason = err.Error()
+	}
+	return result, nil
+}
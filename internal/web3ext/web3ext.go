@@ -27,11 +27,13 @@ var Modules = map[string]string{
 	"miner":        Miner_JS,
 	"net":          Net_JS,
 	"personal":     Personal_JS,
+	"privacy":      Privacy_JS,
 	"rpc":          RPC_JS,
 	"shh":          Shh_JS,
 	"tomox":        TomoX_JS,
 	"tomoxlending": TomoXLending_JS,
 	"swarmfs":      SWARMFS_JS,
+	"trc21":        TRC21_JS,
 	"txpool":       TxPool_JS,
 }
 
@@ -138,6 +140,12 @@ web3._extend({
 			call: 'posv_getSignersAtHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getM1M2Mapping',
+			call: 'posv_getM1M2Mapping',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -396,6 +404,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCall',
+			call: 'debug_traceCall',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -491,6 +505,42 @@ web3._extend({
 			call: 'eth_getBlockReceipts',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'createAccessList',
+			call: 'eth_createAccessList',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getProof',
+			call: 'eth_getProof',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'eth_feeHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'callMany',
+			call: 'eth_callMany',
+			params: 5,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'isBlacklisted',
+			call: 'eth_isBlacklisted',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'eth_getTransactionsByAddress',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -838,6 +888,48 @@ web3._extend({
             call: 'tomox_getLendingTradeById',
             params: 3
 		}),
+		new web3._extend.Method({
+			name: 'getRelayer',
+			call: 'tomox_getRelayer',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listRelayers',
+			call: 'tomox_listRelayers',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getMinimumDeposit',
+			call: 'tomox_getMinimumDeposit',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listTokens',
+			call: 'tomox_listTokens',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'validateToken',
+			call: 'tomox_validateToken',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getUserFeeTier',
+			call: 'tomox_getUserFeeTier',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochPrices',
+			call: 'tomox_getEpochPrices',
+			params: 3,
+			inputFormatter: [null, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
 	]
 });
 `
@@ -1011,3 +1103,76 @@ web3._extend({
 	]
 });
 `
+
+const TRC21_JS = `
+web3._extend({
+	property: 'trc21',
+	methods: [
+		new web3._extend.Method({
+			name: 'getFeeCapacity',
+			call: 'trc21_getFeeCapacity',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listSponsoredTokens',
+			call: 'trc21_listSponsoredTokens',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'validateToken',
+			call: 'trc21_validateToken',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'estimateFee',
+			call: 'trc21_estimateFee',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
+
+const Privacy_JS = `
+web3._extend({
+	property: 'privacy',
+	methods: [
+		new web3._extend.Method({
+			name: 'stealthAddress',
+			call: 'privacy_stealthAddress',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'generateOneTimeKey',
+			call: 'privacy_generateOneTimeKey',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signRing',
+			call: 'privacy_signRing',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'proveRange',
+			call: 'privacy_proveRange',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stealthTransfer',
+			call: 'privacy_stealthTransfer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'scanOutputs',
+			call: 'privacy_scanOutputs',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
@@ -496,6 +496,12 @@ func (self *LightChain) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) e
 	return self.scope.Track(self.chainSideFeed.Subscribe(ch))
 }
 
+// SubscribeFinalizedHeadEvent implements the interface of filters.Backend
+// LightChain does not track finality, so return an empty subscription.
+func (self *LightChain) SubscribeFinalizedHeadEvent(ch chan<- core.FinalizedHeadEvent) event.Subscription {
+	return self.scope.Track(new(event.Feed).Subscribe(ch))
+}
+
 // SubscribeLogsEvent implements the interface of filters.Backend
 // LightChain does not send logs events, so return an empty subscription.
 func (self *LightChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
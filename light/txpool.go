@@ -19,6 +19,7 @@ package light
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -67,6 +68,8 @@ type TxPool struct {
 	clearIdx     uint64                               // earliest block nr that can contain mined tx info
 
 	homestead bool
+	cancun    bool
+	eip2929   bool
 }
 
 // TxRelayBackend provides an interface to the mechanism that forwards transacions
@@ -91,7 +94,7 @@ type TxRelayBackend interface {
 func NewTxPool(config *params.ChainConfig, chain *LightChain, relay TxRelayBackend) *TxPool {
 	pool := &TxPool{
 		config:      config,
-		signer:      types.NewEIP155Signer(config.ChainId),
+		signer:      types.NewEIP2930Signer(config.ChainId),
 		nonce:       make(map[common.Address]uint64),
 		pending:     make(map[common.Hash]*types.Transaction),
 		mined:       make(map[common.Hash][]*types.Transaction),
@@ -311,6 +314,8 @@ func (pool *TxPool) setNewHead(head *types.Header) {
 	m, r := txc.getLists()
 	pool.relay.NewHead(pool.head, m, r)
 	pool.homestead = pool.config.IsHomestead(head.Number)
+	pool.cancun = pool.config.IsCancun(head.Number)
+	pool.eip2929 = pool.config.IsEIP2929(head.Number)
 	pool.signer = types.MakeSigner(pool.config, head.Number)
 }
 
@@ -347,16 +352,22 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 		err  error
 	)
 
-	// check if sender is in black list
-	if tx.From() != nil && common.Blacklist[*tx.From()] {
-		return fmt.Errorf("Reject transaction with sender in black-list: %v", tx.From().Hex())
-	}
-	// check if receiver is in black list
-	if tx.To() != nil && common.Blacklist[*tx.To()] {
-		return fmt.Errorf("Reject transaction with receiver in black-list: %v", tx.To().Hex())
+	// Accept only legacy transactions until the EIP2929 fork enables EIP-2718
+	if !pool.eip2929 && tx.Type() != types.LegacyTxType {
+		return core.ErrTxTypeNotSupported
 	}
 
 	header := pool.chain.GetHeaderByHash(pool.head)
+	// check if sender or receiver is in black list, reading the black-list
+	// contract from the light state only past the fork
+	next := new(big.Int).Add(header.Number, common.Big1)
+	var parentState *state.StateDB
+	if pool.config.IsBlacklist(next) {
+		parentState = pool.currentState(ctx)
+	}
+	if err := core.CheckBlacklist(pool.config, parentState, next, tx.From(), tx.To()); err != nil {
+		return fmt.Errorf("Reject %v", err)
+	}
 	// validate balance slot, minFee slot for TomoZ
 	if pool.config.IsTomoZEnabled(header.Number) && tx.IsTomoZApplyTransaction() {
 		copyState := pool.currentState(ctx).Copy()
@@ -402,8 +413,13 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 		return core.ErrInsufficientFunds
 	}
 
+	// Reject creations whose init code is over the EIP-3860 limit
+	if pool.cancun && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+		return core.ErrMaxInitCodeSizeExceeded
+	}
+
 	// Should supply enough intrinsic gas
-	gas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, pool.homestead)
+	gas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, pool.homestead, pool.cancun)
 	if err != nil {
 		return err
 	}
@@ -389,7 +389,7 @@ func (self *worker) wait() {
 			if work.config.Posv != nil {
 				// epoch block
 				if (block.NumberU64() % work.config.Posv.Epoch) == 0 {
-					core.CheckpointCh <- 1
+					self.chain.CheckpointCh <- 1
 				}
 				// prepare set of masternodes for the next epoch
 				if (block.NumberU64() % work.config.Posv.Epoch) == (work.config.Posv.Epoch - work.config.Posv.Gap) {
@@ -490,7 +490,7 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 
 	work := &Work{
 		config:       self.config,
-		signer:       types.NewEIP155Signer(self.config.ChainId),
+		signer:       types.NewEIP2930Signer(self.config.ChainId),
 		state:        state,
 		parentState:  state.Copy(),
 		tradingState: tomoxState,
@@ -635,19 +635,7 @@ func (self *worker) commitNewWork() {
 	if self.config.DAOForkSupport && self.config.DAOForkBlock != nil && self.config.DAOForkBlock.Cmp(header.Number) == 0 {
 		misc.ApplyDAOHardFork(work.state)
 	}
-	if common.TIPSigningBlock.Cmp(header.Number) == 0 {
-		work.state.DeleteAddress(common.HexToAddress(common.BlockSigners))
-	}
-	if self.config.IsAtlas(header.Number) {
-		misc.ApplyVIPVRC25Upgarde(work.state, self.config.AtlasBlock, header.Number)
-	}
-	if self.config.SaigonBlock != nil && self.config.SaigonBlock.Cmp(header.Number) <= 0 {
-		if common.IsTestnet {
-			misc.ApplySaigonHardForkTestnet(work.state, self.config.SaigonBlock, header.Number, self.config.Posv)
-		} else {
-			misc.ApplySaigonHardFork(work.state, self.config.SaigonBlock, header.Number)
-		}
-	}
+	core.ApplyForkState(self.config, work.state, header)
 	// won't grasp txs at checkpoint
 	var (
 		txs                                                                  *types.TransactionsByPriceAndNonce
@@ -686,7 +674,7 @@ func (self *worker) commitNewWork() {
 			tomoXLending := self.eth.GetTomoXLending()
 			if tomoX != nil && header.Number.Uint64() > self.config.Posv.Epoch {
 				if header.Number.Uint64()%self.config.Posv.Epoch == 0 {
-					err := tomoX.UpdateMediumPriceBeforeEpoch(header.Number.Uint64()/self.config.Posv.Epoch, work.tradingState, work.state)
+					err := tomoX.UpdateMediumPriceBeforeEpoch(self.chain, header, header.Number.Uint64()/self.config.Posv.Epoch, work.tradingState, work.state)
 					if err != nil {
 						log.Error("Fail when update medium price last epoch", "error", err)
 						return
@@ -871,19 +859,26 @@ func (env *Work) commitTransactions(mux *event.TypeMux, balanceFee map[common.Ad
 	var coalescedLogs []*types.Log
 	isAtlas := env.config.IsAtlas(bc.CurrentBlock().Number())
 
+	// Past the black-list fork the black-list contract is read from the state
+	// of the parent block, unaffected by the transactions already committed
+	var parentState *state.StateDB
+	blacklistEnforced := core.IsBlacklistEnforced(env.config, env.header.Number)
+	if env.config.IsBlacklist(env.header.Number) {
+		var err error
+		if parentState, err = bc.StateAt(bc.GetHeaderByHash(env.header.ParentHash).Root); err != nil {
+			log.Error("Failed to read the black-list state", "err", err)
+			return
+		}
+	}
+
 	// first priority for special Txs
 	for _, tx := range specialTxs {
 
 		//HF number for black-list
-		if (env.header.Number.Uint64() >= common.BlackListHFBlock) && !common.IsTestnet {
-			// check if sender is in black list
-			if tx.From() != nil && common.Blacklist[*tx.From()] {
-				log.Debug("Skipping transaction with sender in black-list", "sender", tx.From().Hex())
-				continue
-			}
-			// check if receiver is in black list
-			if tx.To() != nil && common.Blacklist[*tx.To()] {
-				log.Debug("Skipping transaction with receiver in black-list", "receiver", tx.To().Hex())
+		if blacklistEnforced {
+			// check if sender or receiver is in black list
+			if err := core.CheckBlacklist(env.config, parentState, env.header.Number, tx.From(), tx.To()); err != nil {
+				log.Debug("Skipping black-listed transaction", "err", err)
 				continue
 			}
 		}
@@ -988,16 +983,16 @@ func (env *Work) commitTransactions(mux *event.TypeMux, balanceFee map[common.Ad
 		}
 
 		//HF number for black-list
-		if (env.header.Number.Uint64() >= common.BlackListHFBlock) && !common.IsTestnet {
+		if blacklistEnforced {
 			// check if sender is in black list
-			if tx.From() != nil && common.Blacklist[*tx.From()] {
-				log.Debug("Skipping transaction with sender in black-list", "sender", tx.From().Hex())
+			if err := core.CheckBlacklist(env.config, parentState, env.header.Number, tx.From(), nil); err != nil {
+				log.Debug("Skipping black-listed transaction", "err", err)
 				txs.Pop()
 				continue
 			}
 			// check if receiver is in black list
-			if tx.To() != nil && common.Blacklist[*tx.To()] {
-				log.Debug("Skipping transaction with receiver in black-list", "receiver", tx.To().Hex())
+			if err := core.CheckBlacklist(env.config, parentState, env.header.Number, nil, tx.To()); err != nil {
+				log.Debug("Skipping black-listed transaction", "err", err)
 				txs.Shift()
 				continue
 			}
@@ -18,6 +18,8 @@ package node
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -41,6 +43,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirJWTSecret       = "jwtsecret"          // Path within the datadir to the secret of the authenticated RPC tokens
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -148,6 +151,32 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// AuthHost is the host interface on which to start the authenticated RPC
+	// server, serving both HTTP and websocket requests bearing an HS256 token.
+	// If this field is empty, no authenticated API endpoint will be started.
+	AuthHost string `toml:",omitempty"`
+
+	// AuthPort is the TCP port number on which to start the authenticated RPC
+	// server.
+	AuthPort int `toml:",omitempty"`
+
+	// AuthVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming requests to the authenticated RPC server.
+	AuthVirtualHosts []string `toml:",omitempty"`
+
+	// AuthModules is a list of API modules to expose via the authenticated RPC
+	// interface. The tokens grant access to the namespaces and methods their
+	// clients may call, the tokens without an access claim to all the listed
+	// modules. If the module list is empty, all RPC API endpoints, public and
+	// private, are exposed, and the tokens without an access claim are denied
+	// every method.
+	AuthModules []string `toml:",omitempty"`
+
+	// JWTSecret is the path to the hex-encoded secret the tokens of the
+	// authenticated RPC server are signed with. If empty, the "jwtsecret" file of
+	// the instance directory is used, generated if missing.
+	JWTSecret string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -229,6 +258,15 @@ func DefaultWSEndpoint() string {
 	return config.WSEndpoint()
 }
 
+// AuthEndpoint resolves an authenticated RPC endpoint based on the configured
+// host interface and port parameters.
+func (c *Config) AuthEndpoint() string {
+	if c.AuthHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.AuthHost, c.AuthPort)
+}
+
 // NodeName returns the devp2p node identifier.
 func (c *Config) NodeName() string {
 	name := c.name()
@@ -335,6 +373,50 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	return key
 }
 
+// JWTSecretKey retrieves the secret the tokens of the authenticated RPC server
+// are signed with, from the configured file or the one of the instance
+// directory. If no secret can be found, a new one is generated and stored, or
+// kept in memory only if no datadir is being used.
+func (c *Config) JWTSecretKey() ([]byte, error) {
+	file := c.JWTSecret
+	if file == "" {
+		file = c.resolvePath(datadirJWTSecret)
+	}
+	if file == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		log.Warn("Generated ephemeral JWT secret, no client can authenticate without a datadir")
+		return secret, nil
+	}
+	if data, err := ioutil.ReadFile(file); err == nil {
+		secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT secret %s: %v", file, err)
+		}
+		if len(secret) != 32 {
+			return nil, fmt.Errorf("invalid JWT secret %s: %d bytes, want 32", file, len(secret))
+		}
+		return secret, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	// No secret found, generate and store a new one.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(file, []byte(hex.EncodeToString(secret)), 0600); err != nil {
+		return nil, err
+	}
+	log.Info("Generated JWT secret", "path", file)
+	return secret, nil
+}
+
 // StaticNodes returns a list of node enode URLs configured as static nodes.
 func (c *Config) StaticNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.resolvePath(datadirStaticNodes))
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/tomochain/tomochain/crypto"
@@ -148,3 +149,48 @@ func TestNodeKeyPersistency(t *testing.T) {
 		t.Fatalf("ephemeral node key persisted to disk")
 	}
 }
+
+// Tests that the JWT secret is generated and persisted in the instance directory
+// if missing, and loaded from the configured file otherwise.
+func TestJWTSecretPersistency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary data directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := &Config{Name: "unit-test", DataDir: dir}
+	secret, err := config.JWTSecretKey()
+	if err != nil {
+		t.Fatalf("failed to generate JWT secret: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Fatalf("JWT secret length mismatch: have %d, want 32", len(secret))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "unit-test", datadirJWTSecret)); err != nil {
+		t.Fatalf("JWT secret not persisted: %v", err)
+	}
+	again, err := config.JWTSecretKey()
+	if err != nil {
+		t.Fatalf("failed to load JWT secret: %v", err)
+	}
+	if !bytes.Equal(secret, again) {
+		t.Fatalf("persisted JWT secret mismatch: have %x, want %x", again, secret)
+	}
+
+	// An explicitly configured secret file is used instead
+	file := filepath.Join(dir, "secret.hex")
+	if err := ioutil.WriteFile(file, []byte("0x"+strings.Repeat("ab", 32)+"\n"), 0600); err != nil {
+		t.Fatalf("failed to write JWT secret: %v", err)
+	}
+	config.JWTSecret = file
+	if secret, err := config.JWTSecretKey(); err != nil || !bytes.Equal(secret, bytes.Repeat([]byte{0xab}, 32)) {
+		t.Fatalf("configured JWT secret mismatch: have %x, err %v", secret, err)
+	}
+	if err := ioutil.WriteFile(file, []byte("abcd"), 0600); err != nil {
+		t.Fatalf("failed to write JWT secret: %v", err)
+	}
+	if _, err := config.JWTSecretKey(); err == nil {
+		t.Fatalf("short JWT secret accepted")
+	}
+}
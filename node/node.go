@@ -69,6 +69,10 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	authEndpoint string       // Authenticated RPC endpoint (interface + port) to listen at (empty = disabled)
+	authListener net.Listener // Authenticated RPC listener socket to server API requests
+	authHandler  *rpc.Server  // Authenticated RPC request handler to process the API requests
+
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 
@@ -118,6 +122,7 @@ func New(conf *Config) (*Node, error) {
 		ipcEndpoint:       conf.IPCEndpoint(),
 		httpEndpoint:      conf.HTTPEndpoint(),
 		wsEndpoint:        conf.WSEndpoint(),
+		authEndpoint:      conf.AuthEndpoint(),
 		eventmux:          new(event.TypeMux),
 		log:               conf.Logger,
 	}, nil
@@ -276,6 +281,13 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
+	if err := n.startAuth(n.authEndpoint, apis, n.config.AuthModules, n.config.AuthVirtualHosts); err != nil {
+		n.stopWS()
+		n.stopHTTP()
+		n.stopIPC()
+		n.stopInProc()
+		return err
+	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
 	return nil
@@ -473,6 +485,69 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startAuth initializes and starts the authenticated RPC endpoint, serving both
+// HTTP and websocket requests.
+func (n *Node) startAuth(endpoint string, apis []rpc.API, modules []string, vhosts []string) error {
+	// Short circuit if the authenticated endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	secret, err := n.config.JWTSecretKey()
+	if err != nil {
+		return err
+	}
+	// Generate the whitelist based on the allowed modules
+	whitelist := make(map[string]bool)
+	for _, module := range modules {
+		whitelist[module] = true
+	}
+	// Register all the APIs exposed by the services, the tokens restricting the
+	// clients to the namespaces and methods they're granted
+	handler := rpc.NewServer()
+	for _, api := range apis {
+		if whitelist[api.Namespace] || len(whitelist) == 0 {
+			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				return err
+			}
+			n.log.Debug("Authenticated RPC registered", "service", api.Service, "namespace", api.Namespace)
+		}
+	}
+	// All APIs registered, start the HTTP listener
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+	// The tokens without an access claim are granted the allowed modules, if any,
+	// and no method at all if every module, the private ones too, is exposed
+	var access rpc.Access
+	if len(modules) > 0 {
+		access = rpc.Access(modules)
+	}
+	go rpc.NewAuthServer(secret, vhosts, access, handler).Serve(listener)
+	n.log.Info("Authenticated RPC endpoint opened", "url", fmt.Sprintf("http://%s", listener.Addr()), "modules", strings.Join(modules, ","), "vhosts", strings.Join(vhosts, ","))
+
+	// All listeners booted successfully
+	n.authEndpoint = endpoint
+	n.authListener = listener
+	n.authHandler = handler
+
+	return nil
+}
+
+// stopAuth terminates the authenticated RPC endpoint.
+func (n *Node) stopAuth() {
+	if n.authListener != nil {
+		n.authListener.Close()
+		n.authListener = nil
+
+		n.log.Info("Authenticated RPC endpoint closed", "url", fmt.Sprintf("http://%s", n.authEndpoint))
+	}
+	if n.authHandler != nil {
+		n.authHandler.Stop()
+		n.authHandler = nil
+	}
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
@@ -488,6 +563,7 @@ func (n *Node) Stop() error {
 		service.SaveData()
 	}
 	// Terminate the API, services and the p2p server.
+	n.stopAuth()
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
@@ -637,6 +713,12 @@ func (n *Node) WSEndpoint() string {
 	return n.wsEndpoint
 }
 
+// AuthEndpoint retrieves the current authenticated RPC endpoint used by the
+// protocol stack.
+func (n *Node) AuthEndpoint() string {
+	return n.authEndpoint
+}
+
 // EventMux retrieves the event multiplexer used by all the network services in
 // the current protocol stack.
 func (n *Node) EventMux() *event.TypeMux {
@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Tomochain
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"github.com/tomochain/tomochain/common"
+)
+
+// TrustedCheckpoint is a PoSV epoch checkpoint which is known to be part of the
+// canonical chain. Fast sync only accepts peers whose chain goes through it, and
+// the engine verifies the headers following it from the signer snapshot taken at
+// the gap block preceding it instead of replaying the votes from genesis.
+type TrustedCheckpoint struct {
+	Number       uint64           `json:"number"`       // Number of the epoch checkpoint block
+	Hash         common.Hash      `json:"hash"`         // Hash of the epoch checkpoint block
+	SnapshotHash common.Hash      `json:"snapshotHash"` // Hash of the gap block (Number - Gap) the snapshot was taken at
+	Signers      []common.Address `json:"signers"`      // Signers of the snapshot
+}
+
+// Empty reports whether the checkpoint carries no information.
+func (c *TrustedCheckpoint) Empty() bool {
+	return c == nil || c.Number == 0 || c.Hash == (common.Hash{})
+}
+
+// Epoch returns the PoSV epoch started by the checkpoint block.
+func (c *TrustedCheckpoint) Epoch(config *PosvConfig) uint64 {
+	return c.Number / config.Epoch
+}
+
+// SnapshotNumber returns the number of the gap block the snapshot was taken at.
+func (c *TrustedCheckpoint) SnapshotNumber(config *PosvConfig) uint64 {
+	return c.Number - config.Gap
+}
+
+var (
+	// VicMainnetTrustedCheckpoint contains the latest checkpoint of the Viction
+	// main network, generated with `tomo checkpoint dump`. It is nil until one
+	// has been embedded, fast sync then only uses the one given with --checkpoint.
+	VicMainnetTrustedCheckpoint *TrustedCheckpoint
+
+	// VicTestnetTrustedCheckpoint contains the latest checkpoint of the Viction
+	// test network, generated with `tomo checkpoint dump`. It is nil until one
+	// has been embedded, fast sync then only uses the one given with --checkpoint.
+	VicTestnetTrustedCheckpoint *TrustedCheckpoint
+)
+
+// TrustedCheckpoints associates each known checkpoint with the genesis hash of
+// the chain it belongs to.
+var TrustedCheckpoints = map[common.Hash]*TrustedCheckpoint{
+	VicMainnetGenesisHash: VicMainnetTrustedCheckpoint,
+	VicTestnetGenesisHash: VicTestnetTrustedCheckpoint,
+}
@@ -218,8 +218,16 @@ type ChainConfig struct {
 	TIPTomoXLendingBlock         *big.Int `json:"tipTomoXLendingBlock,omitempty"`         // TIPTomoXLending switch block (nil = no fork, 0 = already activated)
 	TIPTomoXCancellationFeeBlock *big.Int `json:"tipTomoXCancellationFeeBlock,omitempty"` // TIPTomoXCancellationFee switch block (nil = no fork, 0 = already activated)
 
-	SaigonBlock *big.Int `json:"saigonBlock,omitempty"` // Saigon switch block (nil = no fork, 0 = already activated)
-	AtlasBlock  *big.Int `json:"atlasBlock,omitempty"`  // Atlas switch block (nil = no fork, 0 = already activated)
+	SaigonBlock  *big.Int `json:"saigonBlock,omitempty"`  // Saigon switch block (nil = no fork, 0 = already activated)
+	AtlasBlock   *big.Int `json:"atlasBlock,omitempty"`   // Atlas switch block (nil = no fork, 0 = already activated)
+	CancunBlock  *big.Int `json:"cancunBlock,omitempty"`  // Cancun switch block (nil = no fork, 0 = already activated)
+	EIP2929Block *big.Int `json:"eip2929Block,omitempty"` // EIP2929 and access list transactions switch block (nil = no fork, 0 = already activated)
+
+	BlacklistBlock          *big.Int `json:"blacklistBlock,omitempty"`          // Black-list contract switch block (nil = no fork, 0 = already activated)
+	PrivacyGasBlock         *big.Int `json:"privacyGasBlock,omitempty"`         // Privacy precompiles gas switch block (nil = no fork, 0 = already activated)
+	TomoXEpochAverageBlock  *big.Int `json:"tomoXEpochAverageBlock,omitempty"`  // TomoX epoch price history and epoch average precompile switch block (nil = no fork, 0 = already activated)
+	TomoXPriceRoutingBlock  *big.Int `json:"tomoXPriceRoutingBlock,omitempty"`  // TomoX multi-hop pricing switch block (nil = no fork, 0 = already activated)
+	TomoXMakerTakerFeeBlock *big.Int `json:"tomoXMakerTakerFeeBlock,omitempty"` // TomoX maker/taker fees and fee tiers switch block (nil = no fork, 0 = already activated)
 
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
@@ -272,7 +280,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v TIP2019: %v TIPSigning: %v TIPRandomize: %v BlackListHF: %v TIPTRC21Fee: %v TIPTomoX: %v TIPTomoXLending: %v TIPTomoXCancellationFee: %v Saigon: %v Atlas: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v TIP2019: %v TIPSigning: %v TIPRandomize: %v BlackListHF: %v TIPTRC21Fee: %v TIPTomoX: %v TIPTomoXLending: %v TIPTomoXCancellationFee: %v Saigon: %v Atlas: %v Cancun: %v EIP2929: %v Blacklist: %v PrivacyGas: %v TomoXEpochAverage: %v TomoXPriceRouting: %v TomoXMakerTakerFee: %v Engine: %v}",
 		c.ChainId,
 		c.HomesteadBlock,
 		c.EIP150Block,
@@ -289,6 +297,13 @@ func (c *ChainConfig) String() string {
 		c.TIPTomoXCancellationFeeBlock,
 		c.SaigonBlock,
 		c.AtlasBlock,
+		c.CancunBlock,
+		c.EIP2929Block,
+		c.BlacklistBlock,
+		c.PrivacyGasBlock,
+		c.TomoXEpochAverageBlock,
+		c.TomoXPriceRoutingBlock,
+		c.TomoXMakerTakerFeeBlock,
 		engine,
 	)
 }
@@ -370,6 +385,60 @@ func (c *ChainConfig) IsAtlas(num *big.Int) bool {
 	return isForked(c.AtlasBlock, num)
 }
 
+// IsCancun returns whether num is either equal to the Cancun fork block or greater.
+// The fork brings the EVM up to date with the Shanghai and Cancun opcodes: PUSH0
+// (EIP-3855), MCOPY (EIP-5656), transient storage (EIP-1153), BASEFEE (EIP-3198)
+// and the initcode size limit (EIP-3860).
+func (c *ChainConfig) IsCancun(num *big.Int) bool {
+	return isForked(c.CancunBlock, num)
+}
+
+// IsEIP2929 returns whether num is either equal to the EIP2929 fork block or greater.
+// The fork enables the Berlin access list transactions (EIP-2718, EIP-2930) and
+// the warm/cold state access costs (EIP-2929).
+func (c *ChainConfig) IsEIP2929(num *big.Int) bool {
+	return isForked(c.EIP2929Block, num)
+}
+
+// IsBlacklist returns whether num is either equal to the black-list fork block or greater.
+// Past the fork the black-listed addresses are read from the state of the
+// black-list contract at the parent block instead of the hard-coded list.
+func (c *ChainConfig) IsBlacklist(num *big.Int) bool {
+	return isForked(c.BlacklistBlock, num)
+}
+
+// IsPrivacyGas returns whether num is either equal to the privacy gas fork block or greater.
+// Past the fork the ring signature and bulletproof precompiles charge gas by the
+// size of their input instead of a flat price, and reject the inputs which
+// don't have the shape of a signature or proof the wallets create.
+func (c *ChainConfig) IsPrivacyGas(num *big.Int) bool {
+	return isForked(c.PrivacyGasBlock, num)
+}
+
+// IsTomoXEpochAverage returns whether num is either equal to the TomoX epoch average fork block or greater.
+// Past the fork the medium price of every order book is recorded in the trading
+// state at each checkpoint with its closing time, and the epoch average precompile
+// time-weights the recorded prices of the last epochs.
+func (c *ChainConfig) IsTomoXEpochAverage(num *big.Int) bool {
+	return isForked(c.TomoXEpochAverageBlock, num)
+}
+
+// IsTomoXPriceRouting returns whether num is either equal to the TomoX price routing fork block or greater.
+// Past the fork the tokens without a pair with TOMO are priced in TOMO through
+// the pairs they're traded in, so that their cancellation fees and lending
+// collateral can be valued.
+func (c *ChainConfig) IsTomoXPriceRouting(num *big.Int) bool {
+	return isForked(c.TomoXPriceRoutingBlock, num)
+}
+
+// IsTomoXMakerTakerFee returns whether num is either equal to the TomoX maker/taker fee fork block or greater.
+// Past the fork the relayers which set maker and taker fees in the relayer
+// registration contract charge them instead of their single trade fee, lowered
+// by the tier the traded volume of the user reaches.
+func (c *ChainConfig) IsTomoXMakerTakerFee(num *big.Int) bool {
+	return isForked(c.TomoXMakerTakerFeeBlock, num)
+}
+
 /* Feature flag check */
 func (c *ChainConfig) IsTomoXEnabled(num *big.Int) bool {
 	return !isForked(c.AtlasBlock, num) && isForked(common.TIPTomoXBlock, num)
@@ -480,6 +549,27 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.AtlasBlock, newcfg.AtlasBlock, head) {
 		return newCompatError("Atlas fork block", c.AtlasBlock, newcfg.AtlasBlock)
 	}
+	if isForkIncompatible(c.CancunBlock, newcfg.CancunBlock, head) {
+		return newCompatError("Cancun fork block", c.CancunBlock, newcfg.CancunBlock)
+	}
+	if isForkIncompatible(c.EIP2929Block, newcfg.EIP2929Block, head) {
+		return newCompatError("EIP2929 fork block", c.EIP2929Block, newcfg.EIP2929Block)
+	}
+	if isForkIncompatible(c.BlacklistBlock, newcfg.BlacklistBlock, head) {
+		return newCompatError("Black-list fork block", c.BlacklistBlock, newcfg.BlacklistBlock)
+	}
+	if isForkIncompatible(c.PrivacyGasBlock, newcfg.PrivacyGasBlock, head) {
+		return newCompatError("Privacy gas fork block", c.PrivacyGasBlock, newcfg.PrivacyGasBlock)
+	}
+	if isForkIncompatible(c.TomoXEpochAverageBlock, newcfg.TomoXEpochAverageBlock, head) {
+		return newCompatError("TomoX epoch average fork block", c.TomoXEpochAverageBlock, newcfg.TomoXEpochAverageBlock)
+	}
+	if isForkIncompatible(c.TomoXPriceRoutingBlock, newcfg.TomoXPriceRoutingBlock, head) {
+		return newCompatError("TomoX price routing fork block", c.TomoXPriceRoutingBlock, newcfg.TomoXPriceRoutingBlock)
+	}
+	if isForkIncompatible(c.TomoXMakerTakerFeeBlock, newcfg.TomoXMakerTakerFeeBlock, head) {
+		return newCompatError("TomoX maker/taker fee fork block", c.TomoXMakerTakerFeeBlock, newcfg.TomoXMakerTakerFeeBlock)
+	}
 	return nil
 }
 
@@ -550,7 +640,9 @@ type Rules struct {
 	IsTIP2019, IsTIPSigning, IsTIPRandomize                  bool
 	IsBlackListHF, IsTIPTRC21Fee                             bool
 	IsTIPTomoX, IsTIPTomoXLending, IsTIPTomoXCancellationFee bool
-	IsSaigon, IsAtlas                                        bool
+	IsSaigon, IsAtlas, IsCancun, IsEIP2929, IsBlacklist      bool
+	IsPrivacyGas, IsTomoXEpochAverage, IsTomoXPriceRouting   bool
+	IsTomoXMakerTakerFee                                     bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -579,5 +671,12 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsTIPTomoXCancellationFee: c.IsTIPTomoXCancellationFee(num),
 		IsSaigon:                  c.IsSaigon(num),
 		IsAtlas:                   c.IsAtlas(num),
+		IsCancun:                  c.IsCancun(num),
+		IsEIP2929:                 c.IsEIP2929(num),
+		IsBlacklist:               c.IsBlacklist(num),
+		IsPrivacyGas:              c.IsPrivacyGas(num),
+		IsTomoXEpochAverage:       c.IsTomoXEpochAverage(num),
+		IsTomoXPriceRouting:       c.IsTomoXPriceRouting(num),
+		IsTomoXMakerTakerFee:      c.IsTomoXMakerTakerFee(num),
 	}
 }
@@ -62,7 +62,8 @@ const (
 	MemoryGas        uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 	TxDataNonZeroGas uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
 
-	MaxCodeSize = 24576 // Maximum bytecode to permit for a contract
+	MaxCodeSize     = 24576           // Maximum bytecode to permit for a contract
+	MaxInitCodeSize = 2 * MaxCodeSize // Maximum initcode to permit in a creation transaction and create instructions (EIP-3860)
 
 	// Precompiled contract gas prices
 
@@ -79,6 +80,19 @@ const (
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
 	TomoXPriceGas           uint64 = 1
+
+	// Privacy precompile prices, the per member and per value ones are calibrated
+	// by BenchmarkPrecompiledRingSignature and BenchmarkPrecompiledBulletproof to
+	// the gas per second of the ecrecover precompile
+	RingSignatureGas          uint64 = 100000  // Flat price of a ring signature verification before the privacy gas fork
+	RingSignatureBaseGas      uint64 = 5000    // Base price of a ring signature verification
+	RingSignaturePerMemberGas uint64 = 15000   // Per ring member price of a ring signature verification
+	BulletproofGas            uint64 = 100000  // Flat price of a bulletproof verification before the privacy gas fork
+	BulletproofBaseGas        uint64 = 5000    // Base price of a bulletproof verification
+	BulletproofPerValueGas    uint64 = 1250000 // Per proven value price of a bulletproof verification
+
+	TomoXEpochAverageBaseGas     uint64 = 900 // Base price of a TomoX epoch average, including the storage read of the epoch opening the average
+	TomoXEpochAveragePerEpochGas uint64 = 800 // Per averaged epoch price of a TomoX epoch average, the price of a storage read
 )
 
 var (
@@ -127,6 +141,15 @@ const (
 	ExtcodeHashGasConstantinople uint64 = 400  // Cost of EXTCODEHASH (introduced in Constantinople)
 	ExtcodeHashGasEIP1884        uint64 = 700  // Cost of EXTCODEHASH after EIP 1884 (part in Istanbul)
 	SelfdestructGasEIP150        uint64 = 5000 // Cost of SELFDESTRUCT post EIP 150 (Tangerine)
+	TloadGasEIP1153              uint64 = 100  // Cost of TLOAD (EIP-1153, part of Cancun)
+	TstoreGasEIP1153             uint64 = 100  // Cost of TSTORE (EIP-1153, part of Cancun)
+	InitCodeWordGasEIP3860       uint64 = 2    // Once per word of the initcode of a contract creation (EIP-3860, part of Cancun)
+
+	TxAccessListAddressGas       uint64 = 2400 // Per address specified in an EIP-2930 access list
+	TxAccessListStorageKeyGas    uint64 = 1900 // Per storage key specified in an EIP-2930 access list
+	ColdAccountAccessCostEIP2929 uint64 = 2600 // COLD_ACCOUNT_ACCESS_COST (EIP-2929)
+	ColdSloadCostEIP2929         uint64 = 2100 // COLD_SLOAD_COST (EIP-2929)
+	WarmStorageReadCostEIP2929   uint64 = 100  // WARM_STORAGE_READ_COST (EIP-2929)
 
 	// EXP has a dynamic portion depending on the size of the exponent
 	ExpByteFrontier uint64 = 10 // was set to 10 in Frontier
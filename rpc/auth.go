@@ -0,0 +1,261 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	jwtAlgorithm      = "HS256"
+	jwtScheme         = "Bearer "
+	jwtIssuedAtLeeway = 60 * time.Second // Time a token is accepted for around the moment it's issued at
+)
+
+var (
+	errMissingToken   = errors.New("missing token")
+	errMalformedToken = errors.New("malformed token")
+	errTokenSignature = errors.New("invalid token signature")
+	errMissingIat     = errors.New("missing issued-at")
+	errStaleToken     = errors.New("stale token")
+	errFutureToken    = errors.New("future token")
+	errExpiredToken   = errors.New("expired token")
+)
+
+// Access restricts the methods a client may call to those matching one of its
+// patterns. A pattern is either a namespace, granting all of its methods, or a
+// method name in the syntax of path.Match, e.g. "eth_getBalance", "debug_trace*"
+// or "*" for all the methods.
+type Access []string
+
+// Allows reports whether the method, e.g. eth_getBalance, may be called.
+func (a Access) Allows(method string) bool {
+	namespace := method
+	if i := strings.Index(method, serviceMethodSeparator); i >= 0 {
+		namespace = method[:i]
+	}
+	for _, pattern := range a {
+		if pattern == namespace {
+			return true
+		}
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
+type accessKey struct{}
+
+// WithAccess returns a copy of the context restricting the requests served with
+// it to the methods the access allows.
+func WithAccess(ctx context.Context, access Access) context.Context {
+	return context.WithValue(ctx, accessKey{}, access)
+}
+
+// AccessFromContext returns the access the requests served with the context are
+// restricted to, if they are.
+func AccessFromContext(ctx context.Context) (Access, bool) {
+	access, ok := ctx.Value(accessKey{}).(Access)
+	return access, ok
+}
+
+// accessContext returns a background context keeping the access of the given
+// one, so that the calls outlive the request they're read from.
+func accessContext(ctx context.Context) context.Context {
+	if access, ok := AccessFromContext(ctx); ok {
+		return WithAccess(context.Background(), access)
+	}
+	return context.Background()
+}
+
+// restrictAccess fails the requests for the methods the access of the context
+// doesn't allow.
+func restrictAccess(ctx context.Context, reqs []*serverRequest) {
+	access, ok := AccessFromContext(ctx)
+	if !ok {
+		return
+	}
+	for _, req := range reqs {
+		if req.err == nil && req.method != "" && !access.Allows(req.method) {
+			req.err = &accessDeniedError{req.method}
+		}
+	}
+}
+
+// JWTClaims are the claims of the tokens authenticating the requests to the
+// authenticated RPC endpoint. As with the engine API, the tokens are short-lived
+// and signed by the clients sharing the secret of the node.
+type JWTClaims struct {
+	IssuedAt  int64  `json:"iat"`           // Unix time the token is issued at, within a minute of the request
+	ExpiresAt int64  `json:"exp,omitempty"` // Unix time the token expires at, if sooner
+	Access    Access `json:"access"`        // Methods the token grants access to, the default access of the server if missing
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// SignJWT creates a token holding the claims, signed with the secret.
+func SignJWT(secret []byte, claims *JWTClaims) (string, error) {
+	header, err := json.Marshal(&jwtHeader{Alg: jwtAlgorithm, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(jwtSignature(secret, signed)), nil
+}
+
+// ParseJWT verifies a token was signed with the secret and is valid at the given
+// time, returning its claims.
+func ParseJWT(secret []byte, token string, now time.Time) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != jwtAlgorithm {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	if !hmac.Equal(signature, jwtSignature(secret, parts[0]+"."+parts[1])) {
+		return nil, errTokenSignature
+	}
+	claims := new(JWTClaims)
+	if err := decodeJWTSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	if claims.IssuedAt == 0 {
+		return nil, errMissingIat
+	}
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	if issuedAt.Before(now.Add(-jwtIssuedAtLeeway)) {
+		return nil, errStaleToken
+	}
+	if issuedAt.After(now.Add(jwtIssuedAtLeeway)) {
+		return nil, errFutureToken
+	}
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, errExpiredToken
+	}
+	return claims, nil
+}
+
+func jwtSignature(secret []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errMalformedToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errMalformedToken
+	}
+	return nil
+}
+
+// jwtHandler is a handler which authenticates the requests with the HS256 bearer
+// token of their Authorization header, restricting them to the methods it
+// grants access to, or to the default access if it has no access claim. The
+// requests are served under a context expiring with the token.
+type jwtHandler struct {
+	secret []byte
+	access Access // Access of the tokens without an access claim
+	next   http.Handler
+}
+
+// NewJWTHandler wraps an RPC handler to only serve the requests bearing a token
+// signed with the secret. A nil default access denies all the methods to the
+// tokens without an access claim.
+func NewJWTHandler(secret []byte, access Access, next http.Handler) http.Handler {
+	if access == nil {
+		access = Access{}
+	}
+	return &jwtHandler{secret: secret, access: access, next: next}
+}
+
+// ServeHTTP authenticates the request, implements http.Handler
+func (h *jwtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(jwtScheme) || !strings.EqualFold(auth[:len(jwtScheme)], jwtScheme) {
+		http.Error(w, errMissingToken.Error(), http.StatusUnauthorized)
+		return
+	}
+	claims, err := ParseJWT(h.secret, auth[len(jwtScheme):], time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	access := claims.Access
+	if access == nil {
+		access = h.access
+	}
+	ctx := WithAccess(r.Context(), access)
+	if claims.ExpiresAt != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, time.Unix(claims.ExpiresAt, 0))
+		defer cancel()
+	}
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// NewAuthServer creates a new RPC server around an API provider, serving both
+// HTTP and websocket requests authenticated with tokens signed with the secret.
+// The tokens without an access claim are granted the given access, none if nil.
+// As the tokens authenticate the clients, websocket requests are accepted from
+// any origin, and their connections are closed once the token expires. Only the
+// headers are read under a deadline, not to cut the websocket connections.
+func NewAuthServer(secret []byte, vhosts []string, access Access, srv *Server) *http.Server {
+	ws := srv.WebsocketHandler([]string{"*"})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	})
+	return &http.Server{
+		Handler:           newVHostHandler(vhosts, NewJWTHandler(secret, access, handler)),
+		ReadHeaderTimeout: 15 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+}
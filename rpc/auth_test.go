@@ -0,0 +1,246 @@
+// Copyright 2019 The tomochain Authors
+// This file is part of the tomochain library.
+//
+// The tomochain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The tomochain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the tomochain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestParseJWT(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Unix(1600000000, 0)
+	sign := func(claims *JWTClaims) string {
+		token, err := SignJWT(secret, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := sign(&JWTClaims{IssuedAt: now.Unix(), Access: Access{"eth", "debug_trace*"}})
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Valid", valid, nil},
+		{"Late", sign(&JWTClaims{IssuedAt: now.Unix() - 59}), nil},
+		{"Stale", sign(&JWTClaims{IssuedAt: now.Unix() - 61}), errStaleToken},
+		{"Future", sign(&JWTClaims{IssuedAt: now.Unix() + 61}), errFutureToken},
+		{"Expired", sign(&JWTClaims{IssuedAt: now.Unix(), ExpiresAt: now.Unix()}), errExpiredToken},
+		{"MissingIat", sign(&JWTClaims{}), errMissingIat},
+		{"OtherSecret", func() string {
+			token, _ := SignJWT([]byte("another secret"), &JWTClaims{IssuedAt: now.Unix()})
+			return token
+		}(), errTokenSignature},
+		{"Tampered", valid[:strings.LastIndex(valid, ".")-1] + "A" + valid[strings.LastIndex(valid, "."):], errTokenSignature},
+		{"Malformed", "abc.def", errMalformedToken},
+	}
+	for _, test := range tests {
+		claims, err := ParseJWT(secret, test.token, now)
+		if err != test.err {
+			t.Errorf("%s: error mismatch: have %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if test.name == "Valid" && (len(claims.Access) != 2 || claims.Access[1] != "debug_trace*") {
+			t.Errorf("%s: access mismatch: have %v", test.name, claims.Access)
+		}
+	}
+
+	// tokens not signed with HS256, e.g. unsigned ones, are refused
+	unsigned := "eyJhbGciOiJub25lIn0." + strings.Split(valid, ".")[1] + "."
+	if _, err := ParseJWT(secret, unsigned, now); err == nil {
+		t.Error("unsigned token accepted")
+	}
+}
+
+func TestAccessAllows(t *testing.T) {
+	access := Access{"eth", "debug_trace*", "tomox_getUserFeeTier"}
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"eth_getBalance", true},
+		{"eth_subscribe", true},
+		{"debug_traceTransaction", true},
+		{"debug_setHead", false},
+		{"tomox_getUserFeeTier", true},
+		{"tomox_getRelayer", false},
+		{"ethx_getBalance", false},
+		{"admin_addPeer", false},
+	}
+	for _, test := range tests {
+		if have := access.Allows(test.method); have != test.want {
+			t.Errorf("%s: have %v, want %v", test.method, have, test.want)
+		}
+	}
+	if !(Access{"*"}).Allows("admin_addPeer") {
+		t.Error("wildcard doesn't allow all the methods")
+	}
+	if (Access{}).Allows("eth_getBalance") {
+		t.Error("empty access allows a method")
+	}
+}
+
+func TestAuthServer(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("calc", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	httpsrv := httptest.NewServer(NewAuthServer(secret, []string{"*"}, Access{"calc"}, server).Handler)
+	defer httpsrv.Close()
+	denysrv := httptest.NewServer(NewAuthServer(secret, []string{"*"}, nil, server).Handler)
+	defer denysrv.Close()
+
+	token := func(access Access) string {
+		token, err := SignJWT(secret, &JWTClaims{IssuedAt: time.Now().Unix(), Access: access})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	postTo := func(url string, auth string, method string) (int, *jsonErrResponse) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":["s",1,{"S":"a"}]}`
+		req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set("content-type", contentType)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		var response jsonErrResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, &response
+	}
+	post := func(token string, method string) (int, *jsonErrResponse) {
+		if token == "" {
+			return postTo(httpsrv.URL, "", method)
+		}
+		return postTo(httpsrv.URL, "Bearer "+token, method)
+	}
+
+	if code, _ := post("", "test_echo"); code != http.StatusUnauthorized {
+		t.Errorf("request without token: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := postTo(httpsrv.URL, token(nil), "test_echo"); code != http.StatusUnauthorized {
+		t.Errorf("request without bearer scheme: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := postTo(httpsrv.URL, "Basic "+token(nil), "test_echo"); code != http.StatusUnauthorized {
+		t.Errorf("request with basic scheme: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	stale, _ := SignJWT(secret, &JWTClaims{IssuedAt: time.Now().Add(-time.Hour).Unix()})
+	if code, _ := post(stale, "test_echo"); code != http.StatusUnauthorized {
+		t.Errorf("request with stale token: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	tests := []struct {
+		access  Access
+		method  string
+		allowed bool
+	}{
+		{nil, "calc_echo", true}, // no access claim, the default access
+		{nil, "test_echo", false},
+		{Access{"test"}, "test_echo", true},
+		{Access{"test"}, "calc_echo", false},
+		{Access{"calc_echoWithCtx"}, "calc_echoWithCtx", true},
+		{Access{"calc_echoWithCtx"}, "calc_echo", false},
+		{Access{"*_echo"}, "calc_echo", true},
+		{Access{}, "test_echo", false},
+	}
+	for _, test := range tests {
+		code, response := post(token(test.access), test.method)
+		if code != http.StatusOK {
+			t.Errorf("%v %s: status mismatch: have %d, want %d", test.access, test.method, code, http.StatusOK)
+			continue
+		}
+		denied := response.Error.Code == (&accessDeniedError{}).ErrorCode()
+		if denied == test.allowed {
+			t.Errorf("%v %s: allowed mismatch: have %v, want %v (error %v)", test.access, test.method, !denied, test.allowed, response.Error)
+		}
+	}
+	// without a default access, the tokens without an access claim are denied
+	if code, response := postTo(denysrv.URL, "Bearer "+token(nil), "calc_echo"); code != http.StatusOK || response.Error.Code != (&accessDeniedError{}).ErrorCode() {
+		t.Errorf("request without access claim nor default access: have status %d, response %+v", code, response)
+	}
+	if code, response := postTo(denysrv.URL, "Bearer "+token(Access{"calc"}), "calc_echo"); code != http.StatusOK || response.Error.Code != 0 {
+		t.Errorf("request with access claim without default access: have status %d, response %+v", code, response)
+	}
+
+	// the access of a websocket connection holds for all of its requests
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(httpsrv.URL, "http"), "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set("Authorization", "Bearer "+token(Access{"test"}))
+	client, err := newClient(context.Background(), func(ctx context.Context) (net.Conn, error) {
+		return wsDialContext(ctx, config)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "s", 1, &Args{"a"}); err != nil || result.String != "s" {
+		t.Errorf("websocket call failed: %v, result %v", err, result)
+	}
+	err = client.Call(&result, "calc_echo", "s", 1, &Args{"a"})
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != (&accessDeniedError{}).ErrorCode() {
+		t.Errorf("websocket call of a denied method: error mismatch: have %v", err)
+	}
+
+	// the websocket connections are closed once their token expires
+	expiring, err := SignJWT(secret, &JWTClaims{IssuedAt: time.Now().Unix(), ExpiresAt: time.Now().Add(2 * time.Second).Unix(), Access: Access{"test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set("Authorization", "Bearer "+expiring)
+	expiringClient, err := newClient(context.Background(), func(ctx context.Context) (net.Conn, error) {
+		return wsDialContext(ctx, config)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer expiringClient.Close()
+
+	if err := expiringClient.Call(&result, "test_echo", "s", 1, &Args{"a"}); err != nil {
+		t.Errorf("websocket call before expiry failed: %v", err)
+	}
+	time.Sleep(3 * time.Second)
+	if err := expiringClient.Call(&result, "test_echo", "s", 1, &Args{"a"}); err == nil {
+		t.Error("websocket call after expiry succeeded")
+	}
+}
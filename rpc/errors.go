@@ -51,6 +51,15 @@ func (e *invalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *invalidParamsError) Error() string { return e.message }
 
+// the client isn't granted access to the method
+type accessDeniedError struct{ method string }
+
+func (e *accessDeniedError) ErrorCode() int { return -32001 }
+
+func (e *accessDeniedError) Error() string {
+	return fmt.Sprintf("access to method %s denied", e.method)
+}
+
 // logic error, callback returned an error
 type callbackError struct{ message string }
 
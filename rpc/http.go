@@ -179,7 +179,7 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer codec.Close()
 
 	w.Header().Set("content-type", contentType)
-	srv.ServeSingleRequest(codec, OptionMethodInvocation)
+	srv.serveRequest(accessContext(r.Context()), codec, true, OptionMethodInvocation)
 }
 
 // validateRequest returns a non-zero response code and error message if the
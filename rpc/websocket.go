@@ -68,7 +68,13 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			// Close the connections authenticated until a deadline once it passes
+			if deadline, ok := conn.Request().Context().Deadline(); ok {
+				timer := time.AfterFunc(time.Until(deadline), func() { conn.Close() })
+				defer timer.Stop()
+			}
+			ctx := accessContext(conn.Request().Context())
+			srv.serveCodec(ctx, NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
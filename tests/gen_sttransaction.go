@@ -8,25 +8,28 @@ import (
 
 	"github.com/tomochain/tomochain/common/hexutil"
 	"github.com/tomochain/tomochain/common/math"
+	"github.com/tomochain/tomochain/core/types"
 )
 
 var _ = (*stTransactionMarshaling)(nil)
 
 func (s stTransaction) MarshalJSON() ([]byte, error) {
 	type stTransaction struct {
-		GasPrice   *math.HexOrDecimal256 `json:"gasPrice"`
-		Nonce      math.HexOrDecimal64   `json:"nonce"`
-		To         string                `json:"to"`
-		Data       []string              `json:"data"`
-		GasLimit   []math.HexOrDecimal64 `json:"gasLimit"`
-		Value      []string              `json:"value"`
-		PrivateKey hexutil.Bytes         `json:"secretKey"`
+		GasPrice    *math.HexOrDecimal256 `json:"gasPrice"`
+		Nonce       math.HexOrDecimal64   `json:"nonce"`
+		To          string                `json:"to"`
+		Data        []string              `json:"data"`
+		AccessLists []*types.AccessList   `json:"accessLists,omitempty"`
+		GasLimit    []math.HexOrDecimal64 `json:"gasLimit"`
+		Value       []string              `json:"value"`
+		PrivateKey  hexutil.Bytes         `json:"secretKey"`
 	}
 	var enc stTransaction
 	enc.GasPrice = (*math.HexOrDecimal256)(s.GasPrice)
 	enc.Nonce = math.HexOrDecimal64(s.Nonce)
 	enc.To = s.To
 	enc.Data = s.Data
+	enc.AccessLists = s.AccessLists
 	if s.GasLimit != nil {
 		enc.GasLimit = make([]math.HexOrDecimal64, len(s.GasLimit))
 		for k, v := range s.GasLimit {
@@ -40,13 +43,14 @@ func (s stTransaction) MarshalJSON() ([]byte, error) {
 
 func (s *stTransaction) UnmarshalJSON(input []byte) error {
 	type stTransaction struct {
-		GasPrice   *math.HexOrDecimal256 `json:"gasPrice"`
-		Nonce      *math.HexOrDecimal64  `json:"nonce"`
-		To         *string               `json:"to"`
-		Data       []string              `json:"data"`
-		GasLimit   []math.HexOrDecimal64 `json:"gasLimit"`
-		Value      []string              `json:"value"`
-		PrivateKey *hexutil.Bytes        `json:"secretKey"`
+		GasPrice    *math.HexOrDecimal256 `json:"gasPrice"`
+		Nonce       *math.HexOrDecimal64  `json:"nonce"`
+		To          *string               `json:"to"`
+		Data        []string              `json:"data"`
+		AccessLists []*types.AccessList   `json:"accessLists,omitempty"`
+		GasLimit    []math.HexOrDecimal64 `json:"gasLimit"`
+		Value       []string              `json:"value"`
+		PrivateKey  *hexutil.Bytes        `json:"secretKey"`
 	}
 	var dec stTransaction
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -64,6 +68,9 @@ func (s *stTransaction) UnmarshalJSON(input []byte) error {
 	if dec.Data != nil {
 		s.Data = dec.Data
 	}
+	if dec.AccessLists != nil {
+		s.AccessLists = dec.AccessLists
+	}
 	if dec.GasLimit != nil {
 		s.GasLimit = make([]uint64, len(dec.GasLimit))
 		for k, v := range dec.GasLimit {
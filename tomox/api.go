@@ -5,6 +5,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/tomochain/tomochain/common"
 )
 
 const (
@@ -40,3 +42,9 @@ func NewPublicTomoXAPI(t *TomoX) *PublicTomoXAPI {
 func (api *PublicTomoXAPI) Version(ctx context.Context) string {
 	return ProtocolVersionStr
 }
+
+// TradingPair is a pair of tokens traded on TomoX.
+type TradingPair struct {
+	BaseToken  common.Address `json:"baseToken"`
+	QuoteToken common.Address `json:"quoteToken"`
+}
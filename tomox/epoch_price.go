@@ -0,0 +1,47 @@
+package tomox
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomochain/common"
+)
+
+// epochPricePrefix + orderbook hash + hash of the last block of the epoch -> medium price of the epoch
+var epochPricePrefix = []byte("tomox-epoch-price-")
+
+func epochPriceKey(orderBook common.Hash, lastBlock common.Hash) []byte {
+	key := make([]byte, len(epochPricePrefix)+2*common.HashLength)
+	copy(key, epochPricePrefix)
+	copy(key[len(epochPricePrefix):], orderBook.Bytes())
+	copy(key[len(epochPricePrefix)+common.HashLength:], lastBlock.Bytes())
+	return key
+}
+
+// WriteEpochPrices indexes the medium prices of the order books over an epoch,
+// as computed at the checkpoint ending it, in the local database of the node.
+// The prices are keyed by the last block of the epoch, the parent of the
+// checkpoint they're computed from: the miner computing them again for its
+// checkpoint writes the same prices, and the checkpoints of side forks don't
+// overwrite the ones of the canonical chain.
+func (tomox *TomoX) WriteEpochPrices(lastBlock common.Hash, epochPriceResult map[common.Hash]*big.Int) error {
+	batch := tomox.db.NewBatch()
+	for orderbook, price := range epochPriceResult {
+		if price.Sign() <= 0 {
+			continue
+		}
+		if err := batch.Put(epochPriceKey(orderbook, lastBlock), price.Bytes()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// ReadEpochPrice returns the indexed medium price of an order book over the
+// epoch ending with the given block, or nil if the node didn't index it.
+func (tomox *TomoX) ReadEpochPrice(orderBook common.Hash, lastBlock common.Hash) *big.Int {
+	data, err := tomox.db.Get(epochPriceKey(orderBook, lastBlock))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(data)
+}
@@ -2,13 +2,28 @@ package tomox
 
 import (
 	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus"
+	"github.com/tomochain/tomochain/consensus/misc"
 	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/params"
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 	"math/big"
 	"reflect"
 	"testing"
 )
 
+// testChain is a chain context only serving the chain configuration.
+type testChain struct {
+	config *params.ChainConfig
+}
+
+func (c *testChain) Engine() consensus.Engine                    { return nil }
+func (c *testChain) GetHeader(common.Hash, uint64) *types.Header { return nil }
+func (c *testChain) CurrentHeader() *types.Header                { return nil }
+func (c *testChain) Config() *params.ChainConfig                 { return c.config }
+
 func Test_getCancelFeeV1(t *testing.T) {
 	type CancelFeeArg struct {
 		baseTokenDecimal *big.Int
@@ -89,6 +104,7 @@ func Test_getCancelFeeV1(t *testing.T) {
 
 func Test_getCancelFee(t *testing.T) {
 	tomox := New(&DefaultConfig)
+	chain, header := &testChain{params.TestChainConfig}, &types.Header{Number: common.Big1}
 	db := rawdb.NewMemoryDatabase()
 	stateCache := tradingstate.NewDatabase(db)
 	tradingStateDb, _ := tradingstate.New(common.Hash{}, stateCache)
@@ -306,7 +322,7 @@ func Test_getCancelFee(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := tomox.getCancelFee(nil, nil, tradingStateDb, tt.args.order, tt.args.feeRate); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := tomox.getCancelFee(chain, header, nil, tradingStateDb, tt.args.order, tt.args.feeRate); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getCancelFee() = %v, quantity %v", got, tt.want)
 			}
 		})
@@ -324,7 +340,7 @@ func Test_getCancelFee(t *testing.T) {
 			Side:       tradingstate.Ask,
 		},
 	}
-	if fee, _ := tomox.getCancelFee(nil, nil, tradingStateDb, tokenCOrder.order, tokenCOrder.feeRate); fee != nil && fee.Sign() != 0 {
+	if fee, _ := tomox.getCancelFee(chain, header, nil, tradingStateDb, tokenCOrder.order, tokenCOrder.feeRate); fee != nil && fee.Sign() != 0 {
 		t.Errorf("getCancelFee() = %v, want %v", fee, common.Big0)
 	}
 
@@ -340,7 +356,7 @@ func Test_getCancelFee(t *testing.T) {
 			Side:       tradingstate.Ask,
 		},
 	}
-	if fee, _ := tomox.getCancelFee(nil, nil, tradingStateDb, tokenDOrder.order, tokenDOrder.feeRate); fee != nil && fee.Sign() != 0 {
+	if fee, _ := tomox.getCancelFee(chain, header, nil, tradingStateDb, tokenDOrder.order, tokenDOrder.feeRate); fee != nil && fee.Sign() != 0 {
 		t.Errorf("getCancelFee() = %v, want %v", fee, common.Big0)
 	}
 
@@ -572,3 +588,104 @@ func TestGetTradeQuantity(t *testing.T) {
 		})
 	}
 }
+
+// Tests that the trades are settled at the trade fee of the relayers up to the
+// maker/taker fee fork, and at the maker/taker fees and the tiers the users
+// reached from the fork on.
+func TestGetTradeQuantityMakerTakerFeeFork(t *testing.T) {
+	tomox := New(&DefaultConfig)
+	config := *params.TestChainConfig
+	config.TomoXMakerTakerFeeBlock = big.NewInt(10)
+	chain := &testChain{&config}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	var (
+		registration = common.HexToAddress(common.RelayerRegistrationSMC)
+		relayerFees  = common.HexToAddress(common.RelayerFeesSMC)
+		relayer      = common.HexToAddress("0x0000000000000000000000000000000000000011")
+		owner        = common.HexToAddress("0x0000000000000000000000000000000000000033")
+		taker        = common.HexToAddress("0x0000000000000000000000000000000000000044")
+		maker        = common.HexToAddress("0x0000000000000000000000000000000000000055")
+		baseToken    = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		quoteToken   = common.HexToAddress(common.TomoNativeAddress)
+	)
+	tomox.SetTokenDecimal(baseToken, common.BasePrice)
+	statedb.SetCode(baseToken, []byte{0})
+	tradingstate.SetTokenBalance(maker, new(big.Int).Mul(big.NewInt(1000), common.BasePrice), baseToken, statedb)
+	tradingstate.SetTokenBalance(taker, new(big.Int).Mul(big.NewInt(1000), common.BasePrice), quoteToken, statedb)
+
+	// a relayer charging a trade fee of 0.1%, then maker/taker fees of
+	// 0.02%/0.2% lowered to 0.01%/0.05% from a traded volume of 100 TOMO
+	relayerLoc := tradingstate.GetLocMappingAtKey(relayer.Hash(), tradingstate.RelayerMappingSlot["RELAYER_LIST"])
+	setRelayer := func(field string, value common.Hash) {
+		statedb.SetState(registration, common.BigToHash(new(big.Int).Add(relayerLoc, tradingstate.RelayerStructMappingSlot[field])), value)
+	}
+	setRelayer("_deposit", common.BigToHash(new(big.Int).Mul(big.NewInt(1000000), common.BasePrice)))
+	setRelayer("_fee", common.BigToHash(big.NewInt(10)))
+	setRelayer("_owner", owner.Hash())
+
+	misc.ApplyTomoXMakerTakerFeeFork(statedb, config.TomoXMakerTakerFeeBlock, config.TomoXMakerTakerFeeBlock)
+	feesLoc := tradingstate.GetLocMappingAtKey(relayer.Hash(), tradingstate.RelayerFeesMappingSlot["RELAYER_FEES"])
+	setFees := func(loc *big.Int, value common.Hash) {
+		statedb.SetState(relayerFees, common.BigToHash(loc), value)
+	}
+	setFees(new(big.Int).Add(feesLoc, tradingstate.RelayerFeesStructMappingSlot["_owner"]), owner.Hash())
+	setFees(new(big.Int).Add(feesLoc, tradingstate.RelayerFeesStructMappingSlot["_makerFee"]), common.BigToHash(big.NewInt(2)))
+	setFees(new(big.Int).Add(feesLoc, tradingstate.RelayerFeesStructMappingSlot["_takerFee"]), common.BigToHash(big.NewInt(20)))
+	tiersHash := common.BigToHash(new(big.Int).Add(feesLoc, tradingstate.RelayerFeesStructMappingSlot["_tiers"]))
+	setFees(tiersHash.Big(), common.BigToHash(common.Big1))
+	tierLoc := state.GetLocDynamicArrAtElement(tiersHash, 0, uint64(len(tradingstate.FeeTierStructMappingSlot))).Big()
+	setFees(new(big.Int).Add(tierLoc, new(big.Int).SetUint64(tradingstate.FeeTierStructMappingSlot["_volume"])), common.BigToHash(new(big.Int).Mul(big.NewInt(100), common.BasePrice)))
+	setFees(new(big.Int).Add(tierLoc, new(big.Int).SetUint64(tradingstate.FeeTierStructMappingSlot["_makerFee"])), common.BigToHash(big.NewInt(1)))
+	setFees(new(big.Int).Add(tierLoc, new(big.Int).SetUint64(tradingstate.FeeTierStructMappingSlot["_takerFee"])), common.BigToHash(big.NewInt(5)))
+
+	tests := []struct {
+		name               string
+		number             int64
+		takerFee, makerFee int64 // Fees charged for a trade of 100 TOMO, in 1/100 TOMO
+		volume             int64 // Volume of the users after the trade, in TOMO
+	}{
+		{"BeforeFork", 9, 10, 10, 0},
+		{"Fork", 10, 20, 2, 100},
+		{"FirstTier", 11, 5, 1, 200},
+	}
+	quantity := new(big.Int).Mul(big.NewInt(100), common.BasePrice)
+	for _, test := range tests {
+		takerOrder := &tradingstate.OrderItem{
+			ExchangeAddress: relayer,
+			UserAddress:     taker,
+			BaseToken:       baseToken,
+			QuoteToken:      quoteToken,
+			Side:            tradingstate.Bid,
+			Price:           common.BasePrice,
+			Quantity:        quantity,
+		}
+		makerOrder := &tradingstate.OrderItem{
+			ExchangeAddress: relayer,
+			UserAddress:     maker,
+			BaseToken:       baseToken,
+			QuoteToken:      quoteToken,
+			Side:            tradingstate.Ask,
+			Price:           common.BasePrice,
+			Quantity:        quantity,
+		}
+		header := &types.Header{Number: big.NewInt(test.number)}
+		traded, _, settleBalance, err := tomox.getTradeQuantity(header, nil, common.Address{}, chain, statedb, takerOrder, makerOrder, quantity)
+		if err != nil || traded.Cmp(quantity) != 0 {
+			t.Fatalf("%s: trade failed: quantity %v, err %v", test.name, traded, err)
+		}
+		unit := new(big.Int).Div(common.BasePrice, big.NewInt(100))
+		if want := new(big.Int).Mul(unit, big.NewInt(test.takerFee)); settleBalance.Taker.Fee.Cmp(want) != 0 {
+			t.Errorf("%s: taker fee mismatch: have %v, want %v", test.name, settleBalance.Taker.Fee, want)
+		}
+		if want := new(big.Int).Mul(unit, big.NewInt(test.makerFee)); settleBalance.Maker.Fee.Cmp(want) != 0 {
+			t.Errorf("%s: maker fee mismatch: have %v, want %v", test.name, settleBalance.Maker.Fee, want)
+		}
+		want := new(big.Int).Mul(common.BasePrice, big.NewInt(test.volume))
+		for _, user := range []common.Address{taker, maker} {
+			if volume := tradingstate.GetUserVolume(relayer, user, statedb); volume.Cmp(want) != 0 {
+				t.Errorf("%s: volume of %x mismatch: have %v, want %v", test.name, user, volume, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tomox
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus"
+	"github.com/tomochain/tomochain/core/state"
+	"github.com/tomochain/tomochain/log"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+// maxPriceRouteHops is the number of pairs a price is routed through at most.
+const maxPriceRouteHops = 3
+
+// PairPrice returns the price of baseToken in quoteToken over the last epoch
+// from the pair trading them, in either direction, or nil if it has no price.
+type PairPrice func(baseToken common.Address, quoteToken common.Address) (*big.Int, error)
+
+// GetRoutedPriceLastEpoch returns the price of baseToken in quoteToken over the
+// last epoch, routed through the medium prices of the TomoX pairs when they're
+// not traded against each other. It returns nil if no route has a price.
+func (tomox *TomoX) GetRoutedPriceLastEpoch(chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) (*big.Int, error) {
+	return tomox.RoutePriceLastEpoch(chain, statedb, baseToken, quoteToken, func(base common.Address, quote common.Address) (*big.Int, error) {
+		return tomox.GetAveragePriceLastEpoch(chain, statedb, tradingStateDb, base, quote)
+	})
+}
+
+// RoutePriceLastEpoch returns the price of baseToken in quoteToken along the
+// route of the fewest pairs with a price, up to maxPriceRouteHops of them, e.g.
+// X/USDT then USDT/TOMO for the price of X in TOMO. Among the routes of as many
+// pairs, the one through the lowest token addresses is taken, so that every
+// node prices alike. It returns nil if no route has a price.
+func (tomox *TomoX) RoutePriceLastEpoch(chain consensus.ChainContext, statedb *state.StateDB, baseToken common.Address, quoteToken common.Address, pairPrice PairPrice) (*big.Int, error) {
+	if baseToken == quoteToken {
+		return nil, fmt.Errorf("can't route the price of %s in itself", baseToken.Hex())
+	}
+	pairTokens, err := tradingstate.GetAllTradingPairTokens(statedb)
+	if err != nil {
+		return nil, err
+	}
+	return tomox.routePrice(chain, statedb, pairTokens, baseToken, quoteToken, pairPrice)
+}
+
+// routePrice routes the price of baseToken in quoteToken through the pairs of
+// the given tokens.
+func (tomox *TomoX) routePrice(chain consensus.ChainContext, statedb *state.StateDB, pairTokens map[common.Hash][2]common.Address, baseToken common.Address, quoteToken common.Address, pairPrice PairPrice) (*big.Int, error) {
+	neighbours := map[common.Address][]common.Address{}
+	for _, tokens := range pairTokens {
+		neighbours[tokens[0]] = append(neighbours[tokens[0]], tokens[1])
+		neighbours[tokens[1]] = append(neighbours[tokens[1]], tokens[0])
+	}
+	for _, tokens := range neighbours {
+		sort.Slice(tokens, func(i, j int) bool { return bytes.Compare(tokens[i][:], tokens[j][:]) < 0 })
+	}
+
+	// breadth first search from the base token, pricing each token reached in it
+	prices := map[common.Address]*big.Int{baseToken: nil}
+	frontier := []common.Address{baseToken}
+	for hop := 0; hop < maxPriceRouteHops && len(frontier) > 0; hop++ {
+		var next []common.Address
+		for _, from := range frontier {
+			for _, to := range neighbours[from] {
+				if _, seen := prices[to]; seen {
+					continue
+				}
+				price, err := pairPrice(from, to)
+				if err != nil || price == nil || price.Sign() <= 0 {
+					log.Debug("RoutePriceLastEpoch: no price", "baseToken", from.Hex(), "quoteToken", to.Hex(), "err", err)
+					continue
+				}
+				if hop > 0 {
+					// price of the base token in the token reached, through the one it's reached from
+					decimal, err := tomox.GetTokenDecimal(chain, statedb, from)
+					if err != nil || decimal.Sign() == 0 {
+						return nil, fmt.Errorf("fail to get tokenDecimal. Token: %v . Err: %v", from.String(), err)
+					}
+					price = new(big.Int).Div(new(big.Int).Mul(prices[from], price), decimal)
+					if price.Sign() == 0 {
+						continue
+					}
+				}
+				if to == quoteToken {
+					log.Debug("RoutePriceLastEpoch", "baseToken", baseToken.Hex(), "quoteToken", quoteToken.Hex(), "hops", hop+1, "price", price)
+					return price, nil
+				}
+				prices[to] = price
+				next = append(next, to)
+			}
+		}
+		frontier = next
+	}
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+package tomox
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+func TestRoutePrice(t *testing.T) {
+	tomox := New(&DefaultConfig)
+	tradingStateDb, _ := tradingstate.New(common.Hash{}, tradingstate.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	var (
+		tomo  = common.HexToAddress(common.TomoNativeAddress)
+		usdt  = common.HexToAddress("0x2000000000000000000000000000000000000002") // 6 decimals
+		x     = common.HexToAddress("0x3000000000000000000000000000000000000003") // 8 decimals, traded against USDT
+		y     = common.HexToAddress("0x4000000000000000000000000000000000000004") // 8 decimals, USDT traded against it
+		w     = common.HexToAddress("0x5000000000000000000000000000000000000005") // 8 decimals, traded against Y
+		v     = common.HexToAddress("0x6000000000000000000000000000000000000006") // 8 decimals, traded against W
+		z     = common.HexToAddress("0x7000000000000000000000000000000000000007") // 8 decimals, traded against X and Y
+		u     = common.HexToAddress("0x8000000000000000000000000000000000000008") // 8 decimals, traded against USDT without a price
+		e6    = big.NewInt(1e6)
+		e8    = big.NewInt(1e8)
+		pairs = map[common.Hash][2]common.Address{}
+	)
+	tomox.SetTokenDecimal(usdt, e6)
+	for _, token := range []common.Address{x, y, w, v, z, u} {
+		tomox.SetTokenDecimal(token, e8)
+	}
+	addPair := func(base, quote common.Address, price *big.Int) {
+		orderBook := tradingstate.GetTradingOrderBookHash(base, quote)
+		pairs[orderBook] = [2]common.Address{base, quote}
+		if price != nil {
+			tradingStateDb.SetMediumPriceBeforeEpoch(orderBook, price)
+		}
+	}
+	addPair(usdt, tomo, new(big.Int).Mul(big.NewInt(2), common.BasePrice)) // 1 USDT = 2 TOMO
+	addPair(x, usdt, big.NewInt(50e6))                                     // 1 X = 50 USDT
+	addPair(usdt, y, big.NewInt(4e8))                                      // 1 USDT = 4 Y
+	addPair(w, y, big.NewInt(2e8))                                         // 1 W = 2 Y
+	addPair(v, w, e8)                                                      // 1 V = 1 W
+	addPair(z, x, e8)                                                      // 1 Z = 1 X
+	addPair(z, y, e8)                                                      // 1 Z = 1 Y
+	addPair(u, usdt, nil)
+
+	pairPrice := func(base, quote common.Address) (*big.Int, error) {
+		return tomox.GetAveragePriceLastEpoch(nil, nil, tradingStateDb, base, quote)
+	}
+	tests := []struct {
+		name  string
+		token common.Address
+		want  *big.Int
+	}{
+		{"Direct", usdt, new(big.Int).Mul(big.NewInt(2), common.BasePrice)},
+		{"TwoHops", x, new(big.Int).Mul(big.NewInt(100), common.BasePrice)},
+		{"TwoHopsInverse", y, new(big.Int).Div(common.BasePrice, big.NewInt(2))},
+		{"ThreeHops", w, common.BasePrice},
+		{"TooManyHops", v, nil},
+		{"LowestRoute", z, new(big.Int).Mul(big.NewInt(100), common.BasePrice)},
+		{"NoPrice", u, nil},
+	}
+	for _, test := range tests {
+		price, err := tomox.routePrice(nil, nil, pairs, test.token, tomo, pairPrice)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if (price == nil) != (test.want == nil) || (price != nil && price.Cmp(test.want) != 0) {
+			t.Errorf("%s: price mismatch: have %v, want %v", test.name, price, test.want)
+		}
+	}
+
+	// routed the other way, TOMO in X
+	price, err := tomox.routePrice(nil, nil, pairs, tomo, x, pairPrice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(1e6); price == nil || price.Cmp(want) != 0 {
+		t.Errorf("TOMO in X: price mismatch: have %v, want %v", price, want)
+	}
+}
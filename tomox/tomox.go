@@ -42,6 +42,8 @@ type Config struct {
 	DBName         string `toml:",omitempty"`
 	ConnectionUrl  string `toml:",omitempty"`
 	ReplicaSetName string `toml:",omitempty"`
+
+	LendingPriceFeed string `toml:",omitempty"` // JSON schedule of the prices the lending collateral is valued at, on test networks
 }
 
 // DefaultConfig represents (shocker!) the default configuration.
@@ -291,11 +293,15 @@ func (tomox *TomoX) GetAveragePriceLastEpoch(chain consensus.ChainContext, state
 }
 
 // return tokenQuantity (after convert from TOMO to token), tokenPriceInTOMO, error
-func (tomox *TomoX) ConvertTOMOToToken(chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, token common.Address, quantity *big.Int) (*big.Int, *big.Int, error) {
+// past the price routing fork, tokens without a pair with TOMO are priced through the pairs they're traded in
+func (tomox *TomoX) ConvertTOMOToToken(chain consensus.ChainContext, header *types.Header, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, token common.Address, quantity *big.Int) (*big.Int, *big.Int, error) {
 	if token.String() == common.TomoNativeAddress {
 		return quantity, common.BasePrice, nil
 	}
 	tokenPriceInTomo, err := tomox.GetAveragePriceLastEpoch(chain, statedb, tradingStateDb, token, common.HexToAddress(common.TomoNativeAddress))
+	if err == nil && (tokenPriceInTomo == nil || tokenPriceInTomo.Sign() <= 0) && chain.Config().IsTomoXPriceRouting(header.Number) {
+		tokenPriceInTomo, err = tomox.GetRoutedPriceLastEpoch(chain, statedb, tradingStateDb, token, common.HexToAddress(common.TomoNativeAddress))
+	}
 	if err != nil || tokenPriceInTomo == nil || tokenPriceInTomo.Sign() <= 0 {
 		return common.Big0, common.Big0, err
 	}
@@ -12,6 +12,13 @@ import (
 
 const (
 	OrderCacheLimit = 10000
+
+	// EpochPriceHistory is the number of last epochs the medium price of an
+	// order book is kept for past the TomoX epoch average fork.
+	EpochPriceHistory = 168
+
+	// MaxFeeTiers is the number of fee tiers a relayer can set at most.
+	MaxFeeTiers = 10
 )
 
 var (
@@ -102,6 +109,26 @@ var (
 		"_index":      big.NewInt(4),
 		"_owner":      big.NewInt(5),
 	}
+	RelayerFeesMappingSlot = map[string]uint64{
+		"RelayerRegistration": 0,
+		"RELAYER_FEES":        1,
+		"USER_VOLUMES":        2,
+	}
+	RelayerFeesStructMappingSlot = map[string]*big.Int{
+		"_owner":    big.NewInt(0),
+		"_makerFee": big.NewInt(1),
+		"_takerFee": big.NewInt(2),
+		"_tiers":    big.NewInt(3),
+	}
+	FeeTierStructMappingSlot = map[string]uint64{
+		"_volume":   0,
+		"_makerFee": 1,
+		"_takerFee": 2,
+	}
+	ListingMappingSlot = map[string]uint64{
+		"_tokens":     0,
+		"tokensState": 1,
+	}
 )
 
 type TxDataMatch struct {
@@ -214,6 +241,12 @@ func GetTradingOrderBookHash(baseToken common.Address, quoteToken common.Address
 	return common.BytesToHash(append(baseToken[:16], quoteToken[4:]...))
 }
 
+// GetEpochPriceHash returns the hash of the exchange object keeping the medium
+// price of an order book over an epoch, recycled every EpochPriceHistory epochs.
+func GetEpochPriceHash(orderBook common.Hash, epoch uint64) common.Hash {
+	return crypto.Keccak256Hash(orderBook.Bytes(), new(big.Int).SetUint64(epoch%EpochPriceHistory).Bytes())
+}
+
 func GetMatchingResultCacheKey(order *OrderItem) common.Hash {
 	return crypto.Keccak256Hash(order.UserAddress.Bytes(), order.Nonce.Bytes())
 }
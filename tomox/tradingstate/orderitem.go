@@ -354,7 +354,7 @@ func VerifyPair(statedb *state.StateDB, exchangeAddress, baseToken, quoteToken c
 	return fmt.Errorf("invalid exchange pair. Base: %s. Quote: %s. Exchange: %s", baseToken.Hex(), quoteToken.Hex(), exchangeAddress.Hex())
 }
 
-func VerifyBalance(statedb *state.StateDB, tomoxStateDb *TradingStateDB, order *types.OrderTransaction, baseDecimal, quoteDecimal *big.Int) error {
+func VerifyBalance(isTomoXMakerTakerFeeFork bool, statedb *state.StateDB, tomoxStateDb *TradingStateDB, order *types.OrderTransaction, baseDecimal, quoteDecimal *big.Int) error {
 	var quotePrice *big.Int
 	if order.QuoteToken().String() != common.TomoNativeAddress {
 		quotePrice = tomoxStateDb.GetLastPrice(GetTradingOrderBookHash(order.QuoteToken(), common.HexToAddress(common.TomoNativeAddress)))
@@ -371,8 +371,12 @@ func VerifyBalance(statedb *state.StateDB, tomoxStateDb *TradingStateDB, order *
 	} else {
 		quotePrice = common.BasePrice
 	}
-	feeRate := GetExRelayerFee(order.ExchangeAddress(), statedb)
-	balanceResult, err := GetSettleBalance(quotePrice, order.Side(), feeRate, order.BaseToken(), order.QuoteToken(), order.Price(), feeRate, baseDecimal, quoteDecimal, order.Quantity())
+	takerFeeRate := GetExRelayerFee(order.ExchangeAddress(), statedb)
+	makerFeeRate := takerFeeRate
+	if isTomoXMakerTakerFeeFork {
+		_, makerFeeRate, takerFeeRate = GetUserFeeTier(order.ExchangeAddress(), order.UserAddress(), statedb)
+	}
+	balanceResult, err := GetSettleBalance(quotePrice, order.Side(), takerFeeRate, order.BaseToken(), order.QuoteToken(), order.Price(), makerFeeRate, baseDecimal, quoteDecimal, order.Quantity())
 	if err != nil {
 		return err
 	}
@@ -99,9 +99,23 @@ func GetAllCoinbases(statedb *state.StateDB) []common.Address {
 	return coinbases
 }
 func GetAllTradingPairs(statedb *state.StateDB) (map[common.Hash]bool, error) {
+	pairTokens, err := GetAllTradingPairTokens(statedb)
+	if err != nil {
+		return map[common.Hash]bool{}, err
+	}
+	allPairs := map[common.Hash]bool{}
+	for orderBook := range pairTokens {
+		allPairs[orderBook] = true
+	}
+	return allPairs, nil
+}
+
+// GetAllTradingPairTokens returns the base and quote tokens of the pairs traded
+// by the relayers, by order book.
+func GetAllTradingPairTokens(statedb *state.StateDB) (map[common.Hash][2]common.Address, error) {
 	coinbases := GetAllCoinbases(statedb)
 	slot := RelayerMappingSlot["RELAYER_LIST"]
-	allPairs := map[common.Hash]bool{}
+	allPairs := map[common.Hash][2]common.Address{}
 	for _, coinbase := range coinbases {
 		locBig := GetLocMappingAtKey(coinbase.Hash(), slot)
 		fromTokenSlot := new(big.Int).Add(locBig, RelayerStructMappingSlot["_fromTokens"])
@@ -109,7 +123,7 @@ func GetAllTradingPairs(statedb *state.StateDB) (map[common.Hash]bool, error) {
 		toTokenSlot := new(big.Int).Add(locBig, RelayerStructMappingSlot["_toTokens"])
 		toTokenLength := statedb.GetState(common.HexToAddress(common.RelayerRegistrationSMC), common.BigToHash(toTokenSlot)).Big().Uint64()
 		if toTokenLength != fromTokenLength {
-			return map[common.Hash]bool{}, fmt.Errorf("Invalid length from token & to toke : from :%d , to :%d ", fromTokenLength, toTokenLength)
+			return map[common.Hash][2]common.Address{}, fmt.Errorf("Invalid length from token & to toke : from :%d , to :%d ", fromTokenLength, toTokenLength)
 		}
 		fromTokens := []common.Address{}
 		fromTokenSlotHash := common.BytesToHash(fromTokenSlot.Bytes())
@@ -122,7 +136,7 @@ func GetAllTradingPairs(statedb *state.StateDB) (map[common.Hash]bool, error) {
 			toToken := common.BytesToAddress(statedb.GetState(common.HexToAddress(common.RelayerRegistrationSMC), state.GetLocDynamicArrAtElement(toTokenSlotHash, i, uint64(1))).Bytes())
 
 			log.Debug("GetAllTradingPairs all pair info", "from", fromTokens[i].Hex(), "toToken", toToken.Hex())
-			allPairs[GetTradingOrderBookHash(fromTokens[i], toToken)] = true
+			allPairs[GetTradingOrderBookHash(fromTokens[i], toToken)] = [2]common.Address{fromTokens[i], toToken}
 		}
 	}
 	log.Debug("GetAllTradingPairs", "coinbase", len(coinbases), "allPairs", len(allPairs))
@@ -350,3 +364,124 @@ func SetSubRelayerFee(relayer common.Address, balance *big.Int, fee *big.Int, st
 	statedb.SetState(common.HexToAddress(common.RelayerRegistrationSMC), locHashDeposit, common.BigToHash(balance))
 	statedb.SubBalance(common.HexToAddress(common.RelayerRegistrationSMC), fee)
 }
+
+func GetRelayerDeposit(relayer common.Address, statedb *state.StateDB) *big.Int {
+	slot := RelayerMappingSlot["RELAYER_LIST"]
+	locBig := GetLocMappingAtKey(relayer.Hash(), slot)
+	locBig = new(big.Int).Add(locBig, RelayerStructMappingSlot["_deposit"])
+	locHash := common.BigToHash(locBig)
+	return statedb.GetState(common.HexToAddress(common.RelayerRegistrationSMC), locHash).Big()
+}
+
+// GetResignRequest returns the time the deposit of a resigned relayer is
+// released at, or zero if the relayer didn't resign.
+func GetResignRequest(relayer common.Address, statedb *state.StateDB) *big.Int {
+	slot := RelayerMappingSlot["RESIGN_REQUESTS"]
+	locHash := common.BigToHash(GetLocMappingAtKey(relayer.Hash(), slot))
+	return statedb.GetState(common.HexToAddress(common.RelayerRegistrationSMC), locHash).Big()
+}
+
+func GetMinimumDeposit(statedb *state.StateDB) *big.Int {
+	slotHash := state.GetLocSimpleVariable(RelayerMappingSlot["MinimumDeposit"])
+	return statedb.GetState(common.HexToAddress(common.RelayerRegistrationSMC), slotHash).Big()
+}
+
+// GetListedTokens returns the tokens applied to the TomoX listing contract.
+func GetListedTokens(statedb *state.StateDB) []common.Address {
+	slotHash := state.GetLocSimpleVariable(ListingMappingSlot["_tokens"])
+	length := statedb.GetState(common.TomoXListingSMC, slotHash).Big().Uint64()
+	tokens := make([]common.Address, 0, length)
+	for i := uint64(0); i < length; i++ {
+		valueHash := statedb.GetState(common.TomoXListingSMC, state.GetLocDynamicArrAtElement(slotHash, i, 1))
+		tokens = append(tokens, common.BytesToAddress(valueHash.Bytes()))
+	}
+	return tokens
+}
+
+func IsListedToken(token common.Address, statedb *state.StateDB) bool {
+	locHash := common.BigToHash(GetLocMappingAtKey(token.Hash(), ListingMappingSlot["tokensState"]))
+	return statedb.GetState(common.TomoXListingSMC, locHash) != (common.Hash{})
+}
+
+// FeeTier is the maker and taker fees a relayer charges the users whose traded
+// volume reaches the volume of the tier.
+type FeeTier struct {
+	Volume   *big.Int // Traded volume, in TOMO, the tier applies from
+	MakerFee *big.Int
+	TakerFee *big.Int
+}
+
+// GetRelayerMakerTakerFee returns the maker and taker fees of a relayer, and
+// whether the relayer set them instead of its single trade fee. The fees set
+// by a previous owner of the relayer are ignored.
+func GetRelayerMakerTakerFee(relayer common.Address, statedb *state.StateDB) (*big.Int, *big.Int, bool) {
+	locBig := GetLocMappingAtKey(relayer.Hash(), RelayerFeesMappingSlot["RELAYER_FEES"])
+	get := func(field string) common.Hash {
+		locHash := common.BigToHash(new(big.Int).Add(locBig, RelayerFeesStructMappingSlot[field]))
+		return statedb.GetState(common.HexToAddress(common.RelayerFeesSMC), locHash)
+	}
+	owner := common.BytesToAddress(get("_owner").Bytes())
+	if owner == (common.Address{}) || owner != GetRelayerOwner(relayer, statedb) {
+		return nil, nil, false
+	}
+	return get("_makerFee").Big(), get("_takerFee").Big(), true
+}
+
+// GetRelayerFeeTiers returns the fee tiers of a relayer, by increasing volume.
+func GetRelayerFeeTiers(relayer common.Address, statedb *state.StateDB) []FeeTier {
+	locBig := GetLocMappingAtKey(relayer.Hash(), RelayerFeesMappingSlot["RELAYER_FEES"])
+	locHash := common.BigToHash(new(big.Int).Add(locBig, RelayerFeesStructMappingSlot["_tiers"]))
+	length := statedb.GetState(common.HexToAddress(common.RelayerFeesSMC), locHash).Big().Uint64()
+	if length > MaxFeeTiers {
+		length = MaxFeeTiers
+	}
+	elementSize := uint64(len(FeeTierStructMappingSlot))
+	tiers := make([]FeeTier, 0, length)
+	for i := uint64(0); i < length; i++ {
+		loc := state.GetLocDynamicArrAtElement(locHash, i, elementSize).Big()
+		get := func(field string) *big.Int {
+			fieldHash := common.BigToHash(new(big.Int).Add(loc, new(big.Int).SetUint64(FeeTierStructMappingSlot[field])))
+			return statedb.GetState(common.HexToAddress(common.RelayerFeesSMC), fieldHash).Big()
+		}
+		tiers = append(tiers, FeeTier{Volume: get("_volume"), MakerFee: get("_makerFee"), TakerFee: get("_takerFee")})
+	}
+	return tiers
+}
+
+func getUserVolumeLoc(relayer common.Address, user common.Address) common.Hash {
+	locBig := GetLocMappingAtKey(relayer.Hash(), RelayerFeesMappingSlot["USER_VOLUMES"])
+	return crypto.Keccak256Hash(user.Hash().Bytes(), common.BigToHash(locBig).Bytes())
+}
+
+// GetUserVolume returns the volume, in TOMO, a user traded through a relayer
+// since the maker/taker fee fork.
+func GetUserVolume(relayer common.Address, user common.Address, statedb *state.StateDB) *big.Int {
+	return statedb.GetState(common.HexToAddress(common.RelayerFeesSMC), getUserVolumeLoc(relayer, user)).Big()
+}
+
+// AddUserVolume adds to the volume a user traded through a relayer.
+func AddUserVolume(relayer common.Address, user common.Address, volume *big.Int, statedb *state.StateDB) {
+	loc := getUserVolumeLoc(relayer, user)
+	total := statedb.GetState(common.HexToAddress(common.RelayerFeesSMC), loc).Big()
+	statedb.SetState(common.HexToAddress(common.RelayerFeesSMC), loc, common.BigToHash(new(big.Int).Add(total, volume)))
+}
+
+// GetUserFeeTier returns the tier a user reached at a relayer, 0 being none,
+// with the maker and taker fees the user is charged. The relayers which didn't
+// set maker and taker fees charge their trade fee for both.
+func GetUserFeeTier(relayer common.Address, user common.Address, statedb *state.StateDB) (uint64, *big.Int, *big.Int) {
+	makerFee, takerFee, ok := GetRelayerMakerTakerFee(relayer, statedb)
+	if !ok {
+		fee := GetExRelayerFee(relayer, statedb)
+		return 0, fee, new(big.Int).Set(fee)
+	}
+	tier := uint64(0)
+	volume := GetUserVolume(relayer, user, statedb)
+	for i, feeTier := range GetRelayerFeeTiers(relayer, statedb) {
+		if volume.Cmp(feeTier.Volume) < 0 {
+			break
+		}
+		tier, makerFee, takerFee = uint64(i+1), feeTier.MakerFee, feeTier.TakerFee
+	}
+	return tier, makerFee, takerFee
+}
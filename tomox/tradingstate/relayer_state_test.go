@@ -0,0 +1,75 @@
+package tradingstate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/state"
+)
+
+func TestGetUserFeeTier(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	var (
+		registration = common.HexToAddress(common.RelayerRegistrationSMC)
+		contract     = common.HexToAddress(common.RelayerFeesSMC)
+		relayer      = common.HexToAddress("0x0000000000000000000000000000000000000011")
+		owner        = common.HexToAddress("0x0000000000000000000000000000000000000033")
+		user         = common.HexToAddress("0x0000000000000000000000000000000000000022")
+	)
+	set := func(loc *big.Int, value int64) {
+		statedb.SetState(contract, common.BigToHash(loc), common.BigToHash(big.NewInt(value)))
+	}
+	check := func(name string, wantTier uint64, wantMakerFee, wantTakerFee int64) {
+		tier, makerFee, takerFee := GetUserFeeTier(relayer, user, statedb)
+		if tier != wantTier || makerFee.Int64() != wantMakerFee || takerFee.Int64() != wantTakerFee {
+			t.Errorf("%s: have tier %d, fees %v/%v, want tier %d, fees %d/%d", name, tier, makerFee, takerFee, wantTier, wantMakerFee, wantTakerFee)
+		}
+	}
+
+	// without maker/taker fees, the trade fee for both
+	relayerLoc := GetLocMappingAtKey(relayer.Hash(), RelayerMappingSlot["RELAYER_LIST"])
+	statedb.SetState(registration, common.BigToHash(new(big.Int).Add(relayerLoc, RelayerStructMappingSlot["_fee"])), common.BigToHash(big.NewInt(10)))
+	statedb.SetState(registration, common.BigToHash(new(big.Int).Add(relayerLoc, RelayerStructMappingSlot["_owner"])), owner.Hash())
+	check("TradeFee", 0, 10, 10)
+
+	feesLoc := GetLocMappingAtKey(relayer.Hash(), RelayerFeesMappingSlot["RELAYER_FEES"])
+	set(new(big.Int).Add(feesLoc, RelayerFeesStructMappingSlot["_makerFee"]), 5)
+	set(new(big.Int).Add(feesLoc, RelayerFeesStructMappingSlot["_takerFee"]), 15)
+	check("NoOwner", 0, 10, 10)
+	statedb.SetState(contract, common.BigToHash(new(big.Int).Add(feesLoc, RelayerFeesStructMappingSlot["_owner"])), user.Hash())
+	check("PreviousOwner", 0, 10, 10)
+	statedb.SetState(contract, common.BigToHash(new(big.Int).Add(feesLoc, RelayerFeesStructMappingSlot["_owner"])), owner.Hash())
+	check("MakerTakerFee", 0, 5, 15)
+
+	tiersHash := common.BigToHash(new(big.Int).Add(feesLoc, RelayerFeesStructMappingSlot["_tiers"]))
+	tiers := []FeeTier{
+		{Volume: big.NewInt(1000), MakerFee: big.NewInt(4), TakerFee: big.NewInt(12)},
+		{Volume: big.NewInt(5000), MakerFee: big.NewInt(0), TakerFee: big.NewInt(8)},
+	}
+	set(tiersHash.Big(), int64(len(tiers)))
+	for i, tier := range tiers {
+		loc := state.GetLocDynamicArrAtElement(tiersHash, uint64(i), uint64(len(FeeTierStructMappingSlot))).Big()
+		set(new(big.Int).Add(loc, new(big.Int).SetUint64(FeeTierStructMappingSlot["_volume"])), tier.Volume.Int64())
+		set(new(big.Int).Add(loc, new(big.Int).SetUint64(FeeTierStructMappingSlot["_makerFee"])), tier.MakerFee.Int64())
+		set(new(big.Int).Add(loc, new(big.Int).SetUint64(FeeTierStructMappingSlot["_takerFee"])), tier.TakerFee.Int64())
+	}
+	if have := GetRelayerFeeTiers(relayer, statedb); len(have) != len(tiers) || have[1].Volume.Cmp(tiers[1].Volume) != 0 {
+		t.Fatalf("fee tiers mismatch: have %v, want %v", have, tiers)
+	}
+	check("NoVolume", 0, 5, 15)
+
+	AddUserVolume(relayer, user, big.NewInt(999), statedb)
+	check("BelowFirstTier", 0, 5, 15)
+	AddUserVolume(relayer, user, big.NewInt(1), statedb)
+	check("FirstTier", 1, 4, 12)
+	AddUserVolume(relayer, user, big.NewInt(10000), statedb)
+	check("LastTier", 2, 0, 8)
+	if volume := GetUserVolume(relayer, user, statedb); volume.Int64() != 11000 {
+		t.Errorf("volume mismatch: have %v, want %d", volume, 11000)
+	}
+	if volume := GetUserVolume(relayer, relayer, statedb); volume.Sign() != 0 {
+		t.Errorf("volume of another user: have %v, want 0", volume)
+	}
+}
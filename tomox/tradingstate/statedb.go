@@ -133,6 +133,31 @@ func (self *TradingStateDB) GetMediumPriceAndTotalAmount(addr common.Hash) (*big
 	return Zero, Zero
 }
 
+// GetEpochPrice returns the medium price of an order book over an epoch and the
+// time the epoch closed at, or a nil price if it wasn't recorded or was recycled
+// since.
+func (self *TradingStateDB) GetEpochPrice(orderBook common.Hash, epoch uint64) (*big.Int, uint64) {
+	stateObject := self.getStateExchangeObject(GetEpochPriceHash(orderBook, epoch))
+	if stateObject == nil || stateObject.data.Nonce != epoch {
+		return nil, 0
+	}
+	var closed uint64
+	if stateObject.data.LastPrice != nil {
+		closed = stateObject.data.LastPrice.Uint64()
+	}
+	return stateObject.data.MediumPriceBeforeEpoch, closed
+}
+
+// SetEpochPrice records the medium price of an order book over an epoch and the
+// time the epoch closed at in the exchange object of the epoch, whose nonce is
+// the epoch number and last price the closing time.
+func (self *TradingStateDB) SetEpochPrice(orderBook common.Hash, epoch uint64, price *big.Int, closed uint64) {
+	hash := GetEpochPriceHash(orderBook, epoch)
+	self.SetNonce(hash, epoch)
+	self.SetMediumPriceBeforeEpoch(hash, price)
+	self.SetLastPrice(hash, new(big.Int).SetUint64(closed))
+}
+
 // Database retrieves the low level database supporting the lower level trie ops.
 func (self *TradingStateDB) Database() Database {
 	return self.db
@@ -300,3 +300,43 @@ func TestDumpState(t *testing.T) {
 	fmt.Println("bidTrie", bidTrie)
 	db.Close()
 }
+
+func TestEpochPrices(t *testing.T) {
+	orderBook := common.StringToHash("BTC/TOMO")
+	db := rawdb.NewMemoryDatabase()
+	stateCache := NewDatabase(db)
+	statedb, _ := New(common.Hash{}, stateCache)
+
+	for epoch := uint64(1); epoch <= 3; epoch++ {
+		statedb.SetEpochPrice(orderBook, epoch, new(big.Int).SetUint64(epoch*100), epoch*1800)
+	}
+	root, err := statedb.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ = New(root, stateCache)
+	for epoch := uint64(1); epoch <= 3; epoch++ {
+		price, closed := statedb.GetEpochPrice(orderBook, epoch)
+		if price == nil || price.Uint64() != epoch*100 {
+			t.Errorf("epoch %d: price mismatch: have %v, want %d", epoch, price, epoch*100)
+		}
+		if closed != epoch*1800 {
+			t.Errorf("epoch %d: closing time mismatch: have %d, want %d", epoch, closed, epoch*1800)
+		}
+	}
+	if price, _ := statedb.GetEpochPrice(orderBook, 4); price != nil {
+		t.Errorf("epoch 4: have price %v, want none", price)
+	}
+	if price, _ := statedb.GetEpochPrice(common.StringToHash("ETH/TOMO"), 1); price != nil {
+		t.Errorf("unknown order book: have price %v, want none", price)
+	}
+
+	// The price of an epoch is recycled for the epoch EpochPriceHistory later
+	statedb.SetEpochPrice(orderBook, 1+EpochPriceHistory, big.NewInt(1000), (1+EpochPriceHistory)*1800)
+	if price, _ := statedb.GetEpochPrice(orderBook, 1); price != nil {
+		t.Errorf("recycled epoch: have price %v, want none", price)
+	}
+	if price, _ := statedb.GetEpochPrice(orderBook, 1+EpochPriceHistory); price == nil || price.Int64() != 1000 {
+		t.Errorf("recycling epoch: price mismatch: have %v, want 1000", price)
+	}
+}
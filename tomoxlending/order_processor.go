@@ -725,7 +725,7 @@ func (l *Lending) ProcessCancelOrder(header *types.Header, lendingStateDB *lendi
 	if !chain.Config().IsTomoXCancellationFeeEnabled(header.Number) {
 		tokenCancelFee = getCancelFeeV1(collateralTokenDecimal, collateralPrice, feeRate, &originOrder)
 	} else {
-		tokenCancelFee, tokenPriceInTOMO = l.getCancelFee(chain, statedb, tradingStateDb, &originOrder, feeRate)
+		tokenCancelFee, tokenPriceInTOMO = l.getCancelFee(chain, header, statedb, tradingStateDb, &originOrder, feeRate)
 	}
 
 	if tokenBalance.Cmp(tokenCancelFee) < 0 {
@@ -911,16 +911,16 @@ func getCancelFeeV1(collateralTokenDecimal *big.Int, collateralPrice, borrowFee
 }
 
 // return tokenQuantity, tokenPriceInTOMO
-func (l *Lending) getCancelFee(chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, order *lendingstate.LendingItem, feeRate *big.Int) (*big.Int, *big.Int) {
+func (l *Lending) getCancelFee(chain consensus.ChainContext, header *types.Header, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, order *lendingstate.LendingItem, feeRate *big.Int) (*big.Int, *big.Int) {
 	if feeRate == nil || feeRate.Sign() == 0 {
 		return common.Big0, common.Big0
 	}
 	cancelFee, tokenPriceInTOMO := common.Big0, common.Big0
 	var err error
 	if order.Side == lendingstate.Investing {
-		cancelFee, tokenPriceInTOMO, err = l.tomox.ConvertTOMOToToken(chain, statedb, tradingStateDb, order.LendingToken, common.RelayerLendingCancelFee)
+		cancelFee, tokenPriceInTOMO, err = l.tomox.ConvertTOMOToToken(chain, header, statedb, tradingStateDb, order.LendingToken, common.RelayerLendingCancelFee)
 	} else {
-		cancelFee, tokenPriceInTOMO, err = l.tomox.ConvertTOMOToToken(chain, statedb, tradingStateDb, order.CollateralToken, common.RelayerLendingCancelFee)
+		cancelFee, tokenPriceInTOMO, err = l.tomox.ConvertTOMOToToken(chain, header, statedb, tradingStateDb, order.CollateralToken, common.RelayerLendingCancelFee)
 	}
 	if err != nil {
 		return common.Big0, common.Big0
@@ -928,13 +928,14 @@ func (l *Lending) getCancelFee(chain consensus.ChainContext, statedb *state.Stat
 	return cancelFee, tokenPriceInTOMO
 }
 
-func (l *Lending) GetMediumTradePriceBeforeEpoch(chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) (*big.Int, error) {
-	price := tradingStateDb.GetMediumPriceBeforeEpoch(tradingstate.GetTradingOrderBookHash(baseToken, quoteToken))
+func (l *Lending) GetMediumTradePriceBeforeEpoch(header *types.Header, chain consensus.ChainContext, statedb *state.StateDB, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) (*big.Int, error) {
+	priceSource := l.getPriceSource()
+	price := priceSource.MediumPriceBeforeEpoch(header, tradingStateDb, baseToken, quoteToken)
 	if price != nil && price.Sign() > 0 {
 		log.Debug("getMediumTradePriceBeforeEpoch", "baseToken", baseToken.Hex(), "quoteToken", quoteToken.Hex(), "price", price)
 		return price, nil
 	} else {
-		inversePrice := tradingStateDb.GetMediumPriceBeforeEpoch(tradingstate.GetTradingOrderBookHash(quoteToken, baseToken))
+		inversePrice := priceSource.MediumPriceBeforeEpoch(header, tradingStateDb, quoteToken, baseToken)
 		log.Debug("getMediumTradePriceBeforeEpoch", "baseToken", baseToken.Hex(), "quoteToken", quoteToken.Hex(), "inversePrice", inversePrice)
 		if inversePrice != nil && inversePrice.Sign() > 0 {
 			quoteTokenDecimal, err := l.tomox.GetTokenDecimal(chain, statedb, quoteToken)
@@ -996,7 +997,7 @@ func (l *Lending) GetCollateralPrices(header *types.Header, chain consensus.Chai
 	}
 	// if contract doesn't provide any price information
 	// getting price from pair in tomox
-	lastAveragePrice, err := l.GetMediumTradePriceBeforeEpoch(chain, statedb, tradingStateDb, collateralToken, lendingToken)
+	lastAveragePrice, err := l.GetMediumTradePriceBeforeEpoch(header, chain, statedb, tradingStateDb, collateralToken, lendingToken)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1047,7 +1048,7 @@ func (l *Lending) GetTOMOBasePrices(header *types.Header, chain consensus.ChainC
 			tokenTomoPrice = new(big.Int).Div(tokenTomoPrice, tomoTokenPriceFromContract)
 			return tokenTomoPrice, nil
 		}
-		tokenTOMOPrice, err := l.GetMediumTradePriceBeforeEpoch(chain, statedb, tradingStateDb, token, common.HexToAddress(common.TomoNativeAddress))
+		tokenTOMOPrice, err := l.GetMediumTradePriceBeforeEpoch(header, chain, statedb, tradingStateDb, token, common.HexToAddress(common.TomoNativeAddress))
 		if err != nil {
 			return nil, err
 		}
@@ -1055,6 +1056,19 @@ func (l *Lending) GetTOMOBasePrices(header *types.Header, chain consensus.ChainC
 			log.Debug("Getting token/TOMO from tomox", "price", tokenTOMOPrice, "err", err)
 			return tokenTOMOPrice, nil
 		}
+		if chain.Config().IsTomoXPriceRouting(header.Number) {
+			// no pair with TOMO, routing through the pairs the token is traded in
+			tokenTOMOPrice, err = l.tomox.RoutePriceLastEpoch(chain, statedb, token, common.HexToAddress(common.TomoNativeAddress), func(baseToken common.Address, quoteToken common.Address) (*big.Int, error) {
+				return l.GetMediumTradePriceBeforeEpoch(header, chain, statedb, tradingStateDb, baseToken, quoteToken)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if tokenTOMOPrice != nil && tokenTOMOPrice.Sign() > 0 {
+				log.Debug("Getting token/TOMO from routed pairs in tomox", "price", tokenTOMOPrice)
+				return tokenTOMOPrice, nil
+			}
+		}
 	}
 	log.Debug("Can't getting tokenTOMOPrice ", "token", token.Hex())
 	return nil, nil
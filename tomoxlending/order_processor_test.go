@@ -2,7 +2,10 @@ package tomoxlending
 
 import (
 	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/consensus"
 	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/params"
 	"github.com/tomochain/tomochain/tomox"
 	"github.com/tomochain/tomochain/tomox/tradingstate"
 	"github.com/tomochain/tomochain/tomoxlending/lendingstate"
@@ -11,6 +14,16 @@ import (
 	"testing"
 )
 
+// testChain is a chain context only serving the chain configuration.
+type testChain struct {
+	config *params.ChainConfig
+}
+
+func (c *testChain) Engine() consensus.Engine                    { return nil }
+func (c *testChain) GetHeader(common.Hash, uint64) *types.Header { return nil }
+func (c *testChain) CurrentHeader() *types.Header                { return nil }
+func (c *testChain) Config() *params.ChainConfig                 { return c.config }
+
 func Test_getCancelFeeV1(t *testing.T) {
 	type CancelFeeArg struct {
 		collateralTokenDecimal *big.Int
@@ -94,6 +107,7 @@ func Test_getCancelFeeV1(t *testing.T) {
 
 func Test_getCancelFee(t *testing.T) {
 	tomox := tomox.New(&tomox.DefaultConfig)
+	chain, header := &testChain{params.TestChainConfig}, &types.Header{Number: common.Big1}
 	db := rawdb.NewMemoryDatabase()
 	stateCache := tradingstate.NewDatabase(db)
 	tradingStateDb, _ := tradingstate.New(common.Hash{}, stateCache)
@@ -313,7 +327,7 @@ func Test_getCancelFee(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := l.getCancelFee(nil, nil, tradingStateDb, tt.args.order, tt.args.borrowFeeRate); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := l.getCancelFee(chain, header, nil, tradingStateDb, tt.args.order, tt.args.borrowFeeRate); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getCancelFee() = %v, want %v", got, tt.want)
 			}
 		})
@@ -331,7 +345,7 @@ func Test_getCancelFee(t *testing.T) {
 			Side:            lendingstate.Borrowing,
 		},
 	}
-	if fee, _ := l.getCancelFee(nil, nil, tradingStateDb, tokenCOrder.order, tokenCOrder.borrowFeeRate); fee != nil && fee.Sign() != 0 {
+	if fee, _ := l.getCancelFee(chain, header, nil, tradingStateDb, tokenCOrder.order, tokenCOrder.borrowFeeRate); fee != nil && fee.Sign() != 0 {
 		t.Errorf("getCancelFee() = %v, want %v", fee, common.Big0)
 	}
 }
@@ -0,0 +1,119 @@
+package tomoxlending
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/common/math"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/log"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+var ErrPriceSourceOnMainnet = errors.New("lending collateral can only be valued at the trading state prices on the mainnet")
+
+// PriceSource provides the medium prices of the pairs over the last epoch, the
+// collateral of the loans is valued at when the lending contract sets no price.
+type PriceSource interface {
+	// MediumPriceBeforeEpoch returns the price of baseToken in quoteToken at the
+	// given block, or nil if the source has none.
+	MediumPriceBeforeEpoch(header *types.Header, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) *big.Int
+}
+
+// TradingStatePriceSource is the consensus price source, reading the medium
+// prices the TomoX order books traded at over the last epoch.
+type TradingStatePriceSource struct{}
+
+func (TradingStatePriceSource) MediumPriceBeforeEpoch(header *types.Header, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) *big.Int {
+	return tradingStateDb.GetMediumPriceBeforeEpoch(tradingstate.GetTradingOrderBookHash(baseToken, quoteToken))
+}
+
+// ScheduledPrice is the price of a pair from a block on, until the next price
+// scheduled for the pair.
+type ScheduledPrice struct {
+	Block      uint64                `json:"block"`
+	BaseToken  common.Address        `json:"baseToken"`
+	QuoteToken common.Address        `json:"quoteToken"`
+	Price      *math.HexOrDecimal256 `json:"price"`
+}
+
+// ScheduledPriceSource values the collateral at prices scheduled per block, to
+// drive the liquidation, top-up and recall paths of the test networks where
+// little is traded. The pairs without a price scheduled at a block are valued
+// at the trading state prices. As the nodes without the same schedule value
+// the collateral differently, it's refused on the mainnet.
+type ScheduledPriceSource struct {
+	prices map[common.Hash][]ScheduledPrice // Scheduled prices of the order books, by block
+}
+
+// NewScheduledPriceSource creates a price source from the scheduled prices.
+func NewScheduledPriceSource(schedule []ScheduledPrice) (*ScheduledPriceSource, error) {
+	source := &ScheduledPriceSource{prices: make(map[common.Hash][]ScheduledPrice)}
+	for i, price := range schedule {
+		if price.Price == nil || (*big.Int)(price.Price).Sign() < 0 {
+			return nil, fmt.Errorf("scheduled price %d: invalid price", i)
+		}
+		orderBook := tradingstate.GetTradingOrderBookHash(price.BaseToken, price.QuoteToken)
+		source.prices[orderBook] = append(source.prices[orderBook], price)
+	}
+	for _, prices := range source.prices {
+		sort.SliceStable(prices, func(i, j int) bool { return prices[i].Block < prices[j].Block })
+	}
+	return source, nil
+}
+
+// LoadScheduledPriceSource creates a price source from a JSON file listing the
+// scheduled prices.
+func LoadScheduledPriceSource(file string) (*ScheduledPriceSource, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var schedule []ScheduledPrice
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("invalid price schedule %s: %v", file, err)
+	}
+	return NewScheduledPriceSource(schedule)
+}
+
+func (s *ScheduledPriceSource) MediumPriceBeforeEpoch(header *types.Header, tradingStateDb *tradingstate.TradingStateDB, baseToken common.Address, quoteToken common.Address) *big.Int {
+	prices := s.prices[tradingstate.GetTradingOrderBookHash(baseToken, quoteToken)]
+	// the last price scheduled at or before the block
+	i := sort.Search(len(prices), func(i int) bool { return prices[i].Block > header.Number.Uint64() })
+	if i == 0 {
+		return TradingStatePriceSource{}.MediumPriceBeforeEpoch(header, tradingStateDb, baseToken, quoteToken)
+	}
+	return new(big.Int).Set((*big.Int)(prices[i-1].Price))
+}
+
+// SetPriceSource replaces the source of the prices the collateral is valued at.
+func (l *Lending) SetPriceSource(source PriceSource) {
+	l.priceSource = source
+}
+
+// ValidatePriceSource checks the collateral may be valued at the prices of the
+// price source on a chain, which only the trading state prices may on the
+// mainnet.
+func (l *Lending) ValidatePriceSource(config *params.ChainConfig) error {
+	if _, ok := l.getPriceSource().(TradingStatePriceSource); ok {
+		return nil
+	}
+	if config.ChainId != nil && config.ChainId.Cmp(params.VicMainnetChainConfig.ChainId) == 0 {
+		return ErrPriceSourceOnMainnet
+	}
+	log.Warn("Valuing lending collateral at prices out of the trading state, the node only agrees with nodes using the same prices")
+	return nil
+}
+
+func (l *Lending) getPriceSource() PriceSource {
+	if l.priceSource == nil {
+		return TradingStatePriceSource{}
+	}
+	return l.priceSource
+}
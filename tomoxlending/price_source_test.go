@@ -0,0 +1,85 @@
+package tomoxlending
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tomochain/tomochain/common"
+	"github.com/tomochain/tomochain/core/rawdb"
+	"github.com/tomochain/tomochain/core/types"
+	"github.com/tomochain/tomochain/params"
+	"github.com/tomochain/tomochain/tomox/tradingstate"
+)
+
+func TestScheduledPriceSource(t *testing.T) {
+	var (
+		btc  = common.HexToAddress("0x1000000000000000000000000000000000000000")
+		usdt = common.HexToAddress("0x2000000000000000000000000000000000000000")
+		eth  = common.HexToAddress("0x3000000000000000000000000000000000000000")
+	)
+	file := filepath.Join(t.TempDir(), "prices.json")
+	schedule := `[
+		{"block": 200, "baseToken": "` + btc.Hex() + `", "quoteToken": "` + usdt.Hex() + `", "price": "8000"},
+		{"block": 100, "baseToken": "` + btc.Hex() + `", "quoteToken": "` + usdt.Hex() + `", "price": "0x2710"}
+	]`
+	if err := os.WriteFile(file, []byte(schedule), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source, err := LoadScheduledPriceSource(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tradingStateDb, _ := tradingstate.New(common.Hash{}, tradingstate.NewDatabase(rawdb.NewMemoryDatabase()))
+	tradingStateDb.SetMediumPriceBeforeEpoch(tradingstate.GetTradingOrderBookHash(btc, usdt), big.NewInt(9000))
+	tradingStateDb.SetMediumPriceBeforeEpoch(tradingstate.GetTradingOrderBookHash(eth, usdt), big.NewInt(300))
+
+	l := &Lending{}
+	l.SetPriceSource(source)
+	tests := []struct {
+		block       int64
+		base, quote common.Address
+		want        *big.Int
+	}{
+		{99, btc, usdt, big.NewInt(9000)}, // before the schedule, the trading price
+		{100, btc, usdt, big.NewInt(10000)},
+		{199, btc, usdt, big.NewInt(10000)},
+		{200, btc, usdt, big.NewInt(8000)},
+		{1000, btc, usdt, big.NewInt(8000)},
+		{1000, eth, usdt, big.NewInt(300)}, // unscheduled pair, the trading price
+	}
+	for _, test := range tests {
+		header := &types.Header{Number: big.NewInt(test.block)}
+		price, err := l.GetMediumTradePriceBeforeEpoch(header, nil, nil, tradingStateDb, test.base, test.quote)
+		if err != nil {
+			t.Fatalf("block %d: %v", test.block, err)
+		}
+		if price == nil || price.Cmp(test.want) != 0 {
+			t.Errorf("block %d, %s/%s: price mismatch: have %v, want %v", test.block, test.base.Hex(), test.quote.Hex(), price, test.want)
+		}
+	}
+
+	if err := l.ValidatePriceSource(params.VicMainnetChainConfig); err != ErrPriceSourceOnMainnet {
+		t.Errorf("mainnet validation mismatch: have %v, want %v", err, ErrPriceSourceOnMainnet)
+	}
+	if err := l.ValidatePriceSource(params.VicTestnetChainConfig); err != nil {
+		t.Errorf("testnet validation failed: %v", err)
+	}
+	if err := new(Lending).ValidatePriceSource(params.VicMainnetChainConfig); err != nil {
+		t.Errorf("mainnet validation of the trading state source failed: %v", err)
+	}
+}
+
+func TestScheduledPriceSourceInvalid(t *testing.T) {
+	if _, err := NewScheduledPriceSource([]ScheduledPrice{{Block: 1}}); err == nil {
+		t.Error("missing price accepted")
+	}
+	file := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(file, []byte(`{"block": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadScheduledPriceSource(file); err == nil {
+		t.Error("malformed schedule accepted")
+	}
+}
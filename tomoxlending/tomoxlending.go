@@ -42,6 +42,7 @@ type Lending struct {
 	orderNonce map[common.Address]*big.Int
 
 	tomox               *tomox.TomoX
+	priceSource         PriceSource // Source of the prices the collateral is valued at, the trading state if nil
 	lendingItemHistory  *lru.Cache
 	lendingTradeHistory *lru.Cache
 }